@@ -1,5 +1,4 @@
 using UnityEngine;
-using System;
 using Core.Runtime;
 
 namespace Application.Runtime
@@ -8,24 +7,18 @@ public class AccountLoader
     {
         public Account Load()
         {
-            var account = new Account
+            return new Account
             {
                 Id = PlayerPrefs.GetString("AccountId"),
-                AccessToken = PlayerPrefs.GetString("AccessToken"),
+                Secret = PlayerPrefs.GetString("Secret"),
             };
-            if (string.IsNullOrEmpty(account.Id) || string.IsNullOrEmpty(account.AccessToken))
-            {
-                account = new Account
-                {
-                    Id = Guid.NewGuid().ToString(),
-                    AccessToken = Guid.NewGuid().ToString(),
-                };
-
-                PlayerPrefs.SetString("AccountId", account.Id);
-                PlayerPrefs.SetString("AccessToken", account.AccessToken);
-            }
+        }
 
-            return account;
+        public void Save(Account account)
+        {
+            PlayerPrefs.SetString("AccountId", account.Id);
+            PlayerPrefs.SetString("Secret", account.Secret);
+            PlayerPrefs.Save();
         }
     }
-}
\ No newline at end of file
+}
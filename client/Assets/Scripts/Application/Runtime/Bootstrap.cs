@@ -42,9 +42,9 @@ private async void Start()
             var commandService = new CommandService(client);
             var authenticationService = new AuthenticationService(client);
             var synchronizationService = new SynchronizationService(client);
-            var connectionHandler = new ConnectionHandler(client, authenticationService, synchronizationService);
-
             var accountLoader = new AccountLoader();
+            var connectionHandler = new ConnectionHandler(client, authenticationService, synchronizationService, accountLoader);
+
             var account = accountLoader.Load();
 
             var ct = UnityEngine.Application.exitCancellationToken;
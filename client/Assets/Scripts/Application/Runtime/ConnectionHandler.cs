@@ -16,12 +16,14 @@ public class ConnectionHandler
         readonly IClient client;
         readonly IAuthenticationService authenticationService;
         readonly ISynchronizationService synchronizationService;
+        readonly AccountLoader accountLoader;
 
-        public ConnectionHandler(IClient client, IAuthenticationService authenticationService, ISynchronizationService synchronizationService)
+        public ConnectionHandler(IClient client, IAuthenticationService authenticationService, ISynchronizationService synchronizationService, AccountLoader accountLoader)
         {
             this.client = client;
             this.authenticationService = authenticationService;
             this.synchronizationService = synchronizationService;
+            this.accountLoader = accountLoader;
         }
 
         public async Task<((Player player, Configs configs, IClock clock), Error)> HandleAsync(Account account, ProgressDelegate setProgress, CancellationToken ct)
@@ -35,7 +37,21 @@ public async Task<((Player player, Configs configs, IClock clock), Error)> Handl
             setProgress(0.2f);
             Logger.Log("Connected");
 
-            var authError = await authenticationService.AuthenticateAsync(account, ct);
+            if (!account.IsRegistered)
+            {
+                var (registered, registerError) = await authenticationService.RegisterAsync(ct);
+                if (registerError != null)
+                {
+                    return (default, registerError);
+                }
+
+                account.Id = registered.Id;
+                account.Secret = registered.Secret;
+                accountLoader.Save(account);
+                Logger.Log("Registered");
+            }
+
+            var authError = await authenticationService.LoginAsync(account, ct);
             if (authError != null)
             {
                 return (default, authError);
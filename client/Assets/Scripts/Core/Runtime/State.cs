@@ -70,7 +70,9 @@ public int CompareTo(LevelStats other)
     public class Account
     {
         public string Id;
-        public string AccessToken;
+        public string Secret;
+
+        public bool IsRegistered => !string.IsNullOrEmpty(Id) && !string.IsNullOrEmpty(Secret);
     }
 
     [Serializable]
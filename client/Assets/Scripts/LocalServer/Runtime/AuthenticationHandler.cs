@@ -17,16 +17,31 @@ public AuthenticationHandler(IAccountStorage accountStorage)
         }
 
         [EndpointHandler]
-        public (AuthenticateRes, Error) Authenticate(ConnectionState connState, AuthenticateArgs args)
+        public (RegisterRes, Error) Register(ConnectionState connState, RegisterArgs args)
+        {
+            var accountId = Guid.NewGuid().ToString();
+            var secret = Guid.NewGuid().ToString("N");
+
+            var error = CreateAccount(accountId, secret);
+            if (error != null)
+            {
+                return (null, error);
+            }
+
+            return (new RegisterRes { AccountId = accountId, Secret = secret }, null);
+        }
+
+        [EndpointHandler]
+        public (LoginRes, Error) Login(ConnectionState connState, LoginArgs args)
         {
             if (args.AccountId == null)
             {
                 return (null, new Error { Message = "missing account id argument" });
             }
 
-            if (args.AccessToken == null)
+            if (args.Secret == null)
             {
-                return (null, new Error { Message = "missing access token argument" });
+                return (null, new Error { Message = "missing secret argument" });
             }
 
             if (connState.AccountId != null)
@@ -34,33 +49,18 @@ public AuthenticationHandler(IAccountStorage accountStorage)
                 return (null, new Error { Message = "connection already authenticated" });
             }
 
-            var (accessToken, error) = accountStorage.GetAccessToken(args.AccountId);
-            if (error != null)
-            {
-                if (error.Message != "account not found")
-                {
-                    return (null, error);
-                }
-
-                error = CreateAccount(args.AccountId, args.AccessToken);
-                if (error != null)
-                {
-                    return (null, error);
-                }
-                accessToken = args.AccessToken;
-            }
-
-            if (accessToken != args.AccessToken)
+            var (secret, error) = accountStorage.GetSecret(args.AccountId);
+            if (error != null || secret != args.Secret)
             {
-                return (null, new Error { Message = "Invalid access token" });
+                return (null, new Error { Message = "invalid credentials" });
             }
 
             connState.AccountId = args.AccountId;
 
-            return (new AuthenticateRes { }, null);
+            return (new LoginRes { }, null);
         }
 
-        Error CreateAccount(string accountId, string accessToken)
+        Error CreateAccount(string accountId, string secret)
         {
             var initialState = new PersistentState
             {
@@ -76,7 +76,7 @@ Error CreateAccount(string accountId, string accessToken)
                 }
             };
 
-            var error = accountStorage.Create(accountId, accessToken, initialState);
+            var error = accountStorage.Create(accountId, secret, initialState);
             if (error != null)
             {
                 return error;
@@ -85,4 +85,4 @@ Error CreateAccount(string accountId, string accessToken)
             return null;
         }
     }
-}
\ No newline at end of file
+}
@@ -5,9 +5,9 @@ namespace LocalServer.Runtime
 {
     public interface IAccountStorage
     {
-        Error Create(string accountId, string accessToken, PersistentState state);
+        Error Create(string accountId, string secret, PersistentState state);
         (PersistentState, Error) GetPersistentState(string accountId);
         Error SetPersistentState(string accountId, PersistentState state);
-        (string, Error) GetAccessToken(string accountId);
+        (string, Error) GetSecret(string accountId);
     }
 }
\ No newline at end of file
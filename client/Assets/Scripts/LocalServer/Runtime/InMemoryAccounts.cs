@@ -9,14 +9,14 @@ public class InMemoryAccounts : IAccountStorage
         public Dictionary<string, string> Accounts = new();
         public Dictionary<string, PersistentState> PersistentStates = new();
 
-        public Error Create(string accountId, string accessToken, PersistentState state)
+        public Error Create(string accountId, string secret, PersistentState state)
         {
             if (Accounts.ContainsKey(accountId))
             {
                 return new Error { Message = "account already exists" };
             }
 
-            Accounts.Add(accountId, accessToken);
+            Accounts.Add(accountId, secret);
             PersistentStates.Add(accountId, state);
             return null;
         }
@@ -37,14 +37,14 @@ public Error SetPersistentState(string accountId, PersistentState state)
             return null;
         }
 
-        public (string, Error) GetAccessToken(string accountId)
+        public (string, Error) GetSecret(string accountId)
         {
-            if (!Accounts.TryGetValue(accountId, out var accessToken))
+            if (!Accounts.TryGetValue(accountId, out var secret))
             {
                 return (null, new Error { Message = "account not found" });
             }
 
-            return (accessToken, null);
+            return (secret, null);
         }
     }
 
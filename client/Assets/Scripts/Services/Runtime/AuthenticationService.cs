@@ -8,22 +8,30 @@ namespace Services.Runtime
 {
     public interface IAuthenticationService
     {
-        Task<Error> AuthenticateAsync(Account account, CancellationToken ct);
+        Task<(Account, Error)> RegisterAsync(CancellationToken ct);
+        Task<Error> LoginAsync(Account account, CancellationToken ct);
     }
 
     [Serializable]
-    public class AuthenticateArgs
+    public class RegisterArgs { }
+
+    [Serializable]
+    public class RegisterRes
     {
         public string AccountId;
-        public string AccessToken;
+        public string Secret;
     }
 
     [Serializable]
-    public class AuthenticateRes
+    public class LoginArgs
     {
-        public string SessionId;
+        public string AccountId;
+        public string Secret;
     }
 
+    [Serializable]
+    public class LoginRes { }
+
     public class AuthenticationService : IAuthenticationService
     {
         readonly IClient client;
@@ -33,9 +41,20 @@ public AuthenticationService(IClient client)
             this.client = client;
         }
 
-        public async Task<Error> AuthenticateAsync(Account account, CancellationToken ct)
+        public async Task<(Account, Error)> RegisterAsync(CancellationToken ct)
+        {
+            var (res, error) = await client.SendMessage<RegisterArgs, RegisterRes>("AuthenticationHandler/Register", new RegisterArgs(), ct);
+            if (error != null)
+            {
+                return (null, error);
+            }
+
+            return (new Account { Id = res.AccountId, Secret = res.Secret }, null);
+        }
+
+        public async Task<Error> LoginAsync(Account account, CancellationToken ct)
         {
-            var (res, error) = await client.SendMessage<AuthenticateArgs, AuthenticateRes>("AuthenticationHandler/Authenticate", new AuthenticateArgs { AccountId = account.Id, AccessToken = account.AccessToken }, ct);
+            var (_, error) = await client.SendMessage<LoginArgs, LoginRes>("AuthenticationHandler/Login", new LoginArgs { AccountId = account.Id, Secret = account.Secret }, ct);
             if (error != null)
             {
                 return error;
@@ -44,4 +63,4 @@ public async Task<Error> AuthenticateAsync(Account account, CancellationToken ct
             return null;
         }
     }
-}
\ No newline at end of file
+}
@@ -1,26 +1,74 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 	"technical-test-backend/internal/app"
-	"technical-test-backend/internal/sessions/memory"
-	"technical-test-backend/internal/usecases/commands"
-	"technical-test-backend/internal/usecases/configs"
-	"time"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
 )
 
 func main() {
-	app := app.NewHTTP(app.Config{
-		Port: 8080,
-		SessionPool: memory.SessionPoolConfig{
-			TTL: 10 * time.Second,
-		},
-		ConfigProvider: configs.ProviderConfig{
-			FilePath: "../../config/game_config.json",
-		},
-		Commands: commands.Config{
-			MaxTimeDifferenceSeconds: 1,
-		},
-	})
-
-	app.Run()
+	if len(os.Args) > 1 && os.Args[1] == "validate-config" {
+		os.Exit(validateConfig(os.Args[2:]))
+	}
+
+	flags := flag.NewFlagSet("server", flag.ExitOnError)
+	printConfig := flags.Bool("print-config", false, "print the effective configuration, with secrets redacted, and exit")
+
+	config, err := app.LoadConfig(flags, os.Args[1:], os.LookupEnv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *printConfig {
+		if err := app.PrintConfig(os.Stdout, config); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to print configuration: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		printInvalidConfig(err)
+		os.Exit(2)
+	}
+
+	if *printConfig {
+		return
+	}
+
+	slog.SetDefault(app.NewLogger(config.Log, os.Stderr))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// A second signal kills the process instead of waiting for the
+		// shutdown to complete.
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := app.NewHTTP(config).Run(ctx); err != nil {
+		log.Printf("Server stopped with an error: %v", err)
+		os.Exit(1)
+	}
+}
+
+func printInvalidConfig(err error) {
+	var violations core.ConfigViolations
+	if !errors.As(err, &violations) {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		return
+	}
+
+	for _, violation := range violations {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %s: %s\n", violation.Path, violation.Message)
+	}
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/usecases/configs"
+)
+
+// validateConfig lints config files, printing every violation found, and
+// returns the exit code of the process.
+func validateConfig(args []string) int {
+	flags := flag.NewFlagSet("validate-config", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server validate-config <file>...")
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	exitCode := 0
+	for _, path := range flags.Args() {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			_, err = configs.ParseConfigs(data)
+		}
+
+		if err == nil {
+			fmt.Printf("%s: ok\n", path)
+			continue
+		}
+
+		exitCode = 1
+
+		var violations core.ConfigViolations
+		if !errors.As(err, &violations) {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			continue
+		}
+
+		for _, violation := range violations {
+			fmt.Fprintf(os.Stderr, "%s: %s: %s\n", path, violation.Path, violation.Message)
+		}
+	}
+
+	return exitCode
+}
@@ -0,0 +1,160 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"technical-test-backend/internal/app"
+	"technical-test-backend/internal/grpc/gamepb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func getGRPCTestConfig(t *testing.T) app.Config {
+	config, err := GetDefaultTestConfig()
+	require.NoError(t, err)
+
+	config.GRPCPort, err = getFreePort()
+	require.NoError(t, err)
+
+	return config
+}
+
+func newGRPCTestClient(t *testing.T, config app.Config) *GRPCTestClient {
+	client, err := NewGRPCTestClient(config.GRPCPort)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestGRPC_RegisterLoginAndGetPlayerState(t *testing.T) {
+	config := getGRPCTestConfig(t)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := newGRPCTestClient(t, config)
+
+	sessionID, err := client.RegisterAndLogin()
+	require.NoError(t, err)
+	assert.NotEmpty(t, sessionID)
+
+	res, err := client.Initialization.GetPlayerState(WithSession(sessionID), &gamepb.GetPlayerStateRequest{})
+	require.NoError(t, err)
+	assert.Greater(t, res.GetPlayerState().GetPersistent().GetEnergy().GetCurrentAmount(), int32(0))
+	assert.Nil(t, res.GetPlayerState().GetSession().CurrentLevelId)
+
+	configsRes, err := client.Initialization.GetConfigs(WithSession(sessionID), &gamepb.GetConfigsRequest{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, configsRes.GetConfigs().GetLevels())
+	assert.NotEmpty(t, configsRes.GetVersion())
+
+	heartbeatRes, err := client.Heartbeat.Heartbeat(WithSession(sessionID), &gamepb.HeartbeatRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, configsRes.GetVersion(), heartbeatRes.GetConfigsVersion())
+}
+
+func TestGRPC_Login_WithWrongSecret_ShouldReturnInvalidCredentials(t *testing.T) {
+	config := getGRPCTestConfig(t)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := newGRPCTestClient(t, config)
+
+	credentials, err := client.Authentication.Register(context.Background(), &gamepb.RegisterRequest{})
+	require.NoError(t, err)
+
+	_, err = client.Login(credentials.GetAccountId(), "wrong-secret")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "INVALID_CREDENTIALS", grpcErrorInfo(err).GetReason())
+}
+
+func TestGRPC_HandleCommand_ShouldShareSessionWithHTTP(t *testing.T) {
+	config := getGRPCTestConfig(t)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := newGRPCTestClient(t, config)
+
+	sessionID, err := client.RegisterAndLogin()
+	require.NoError(t, err)
+
+	res, err := client.Commands.HandleCommand(WithSession(sessionID), &gamepb.CommandRequest{
+		Command: NewProtoCommand(NewBeginLevelArgs(1)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), res.GetSession().GetCurrentLevelId())
+	assert.NotNil(t, res.GetSession().LevelSeed)
+
+	state, err := NewTestClient(config.Port).GetPlayerState(sessionID)
+	require.NoError(t, err)
+	require.NotNil(t, state.PlayerState.Session.CurrentLevelID)
+	assert.Equal(t, 1, *state.PlayerState.Session.CurrentLevelID)
+}
+
+func TestGRPC_HandleCommands_WithFailingCommand_ShouldReturnIndex(t *testing.T) {
+	config := getGRPCTestConfig(t)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := newGRPCTestClient(t, config)
+
+	sessionID, err := client.RegisterAndLogin()
+	require.NoError(t, err)
+
+	_, err = client.Commands.HandleCommands(WithSession(sessionID), &gamepb.BatchCommandRequest{
+		Commands: []*gamepb.Command{
+			NewProtoCommand(NewBeginLevelArgs(1)),
+			NewProtoCommand(NewBeginLevelArgs(2)),
+		},
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	info := grpcErrorInfo(err)
+	assert.Equal(t, "LEVEL_NOT_UNLOCKED", info.GetReason())
+	assert.Equal(t, "1", info.GetMetadata()["index"])
+}
+
+func TestGRPC_WhenSessionIsReplaced_ShouldReturnSessionReplaced(t *testing.T) {
+	config := getGRPCTestConfig(t)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := newGRPCTestClient(t, config)
+
+	credentials, err := client.Authentication.Register(context.Background(), &gamepb.RegisterRequest{})
+	require.NoError(t, err)
+
+	firstSessionID, err := client.Login(credentials.GetAccountId(), credentials.GetSecret())
+	require.NoError(t, err)
+	_, err = client.Login(credentials.GetAccountId(), credentials.GetSecret())
+	require.NoError(t, err)
+
+	_, err = client.Initialization.GetPlayerState(WithSession(firstSessionID), &gamepb.GetPlayerStateRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "SESSION_REPLACED", grpcErrorInfo(err).GetReason())
+}
+
+func TestGRPC_Unauthenticated(t *testing.T) {
+	config := getGRPCTestConfig(t)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := newGRPCTestClient(t, config)
+
+	_, err := client.Initialization.GetPlayerState(context.Background(), &gamepb.GetPlayerStateRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.Initialization.GetPlayerState(WithSession("invalid-session-id"), &gamepb.GetPlayerStateRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
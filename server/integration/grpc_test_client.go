@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	grpcutils "technical-test-backend/internal/grpc"
+	"technical-test-backend/internal/grpc/gamepb"
+	usecasescommands "technical-test-backend/internal/usecases/commands"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCTestClient waits for the server to be ready on every call, as it may
+// still be starting.
+type GRPCTestClient struct {
+	conn           *grpc.ClientConn
+	Authentication gamepb.AuthenticationHandlerClient
+	Initialization gamepb.InitializationHandlerClient
+	Commands       gamepb.CommandHandlerClient
+	Heartbeat      gamepb.HeartbeatHandlerClient
+}
+
+func NewGRPCTestClient(port int) (*GRPCTestClient, error) {
+	conn, err := grpc.NewClient(
+		fmt.Sprintf("localhost:%d", port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCTestClient{
+		conn:           conn,
+		Authentication: gamepb.NewAuthenticationHandlerClient(conn),
+		Initialization: gamepb.NewInitializationHandlerClient(conn),
+		Commands:       gamepb.NewCommandHandlerClient(conn),
+		Heartbeat:      gamepb.NewHeartbeatHandlerClient(conn),
+	}, nil
+}
+
+func (tc *GRPCTestClient) Close() error {
+	return tc.conn.Close()
+}
+
+// Login returns the session id sent in the response header.
+func (tc *GRPCTestClient) Login(accountID, secret string) (string, error) {
+	var header metadata.MD
+	_, err := tc.Authentication.Login(context.Background(), &gamepb.LoginRequest{
+		AccountId: accountID,
+		Secret:    secret,
+	}, grpc.Header(&header))
+	if err != nil {
+		return "", err
+	}
+
+	sessionIDs := header.Get(grpcutils.SessionIDMetadata)
+	if len(sessionIDs) == 0 {
+		return "", fmt.Errorf("missing session id header")
+	}
+
+	return sessionIDs[0], nil
+}
+
+func (tc *GRPCTestClient) RegisterAndLogin() (string, error) {
+	credentials, err := tc.Authentication.Register(context.Background(), &gamepb.RegisterRequest{})
+	if err != nil {
+		return "", err
+	}
+
+	return tc.Login(credentials.GetAccountId(), credentials.GetSecret())
+}
+
+// WithSession returns a context authenticating the calls with the session.
+func WithSession(sessionID string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), grpcutils.SessionIDMetadata, sessionID)
+}
+
+func NewProtoCommand(args usecasescommands.CommandArgs) *gamepb.Command {
+	return &gamepb.Command{Command: args.Command, Data: string(args.Data)}
+}
+
+// grpcErrorInfo returns the ErrorInfo detail of a status error, if any.
+func grpcErrorInfo(err error) *errdetails.ErrorInfo {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
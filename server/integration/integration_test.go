@@ -5,13 +5,24 @@
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"path/filepath"
 	"technical-test-backend/internal/app"
+	"technical-test-backend/internal/core"
 	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/resp"
+	"technical-test-backend/internal/resp/fake"
+	"technical-test-backend/internal/sessions"
 	"technical-test-backend/internal/sessions/memory"
+	"technical-test-backend/internal/sessions/redis"
+	"technical-test-backend/internal/sessions/token"
 	"technical-test-backend/internal/usecases/commands"
 	"technical-test-backend/internal/usecases/configs"
+	playersfile "technical-test-backend/internal/usecases/players/dal/file"
+	"technical-test-backend/internal/usecases/push"
 	"testing"
 	"time"
 
@@ -19,7 +30,7 @@
 	"github.com/stretchr/testify/assert"
 )
 
-func TestAuthentication_Success(t *testing.T) {
+func TestRegisterAndLogin_Success(t *testing.T) {
 	config, err := GetDefaultTestConfig()
 	assert.NoError(t, err)
 
@@ -28,40 +39,50 @@ func TestAuthentication_Success(t *testing.T) {
 
 	client := NewTestClient(config.Port)
 
-	sessionID, err := client.Authenticate(uuid.New().String(), uuid.New().String())
+	credentials, err := client.Register()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, credentials.AccountID)
+	assert.NotEmpty(t, credentials.Secret)
+
+	sessionID, err := client.Login(credentials.AccountID, credentials.Secret)
 	assert.NoError(t, err)
 
 	assert.NotEmpty(t, sessionID)
 }
 
-func TestAuthentication(t *testing.T) {
+func TestLogin(t *testing.T) {
 	config, err := GetDefaultTestConfig()
 	assert.NoError(t, err)
 
 	_, stop := runServer(t, config)
 	defer stop()
 
+	credentials, err := NewTestClient(config.Port).Register()
+	assert.NoError(t, err)
+
 	table := map[string]struct {
 		accountID     string
-		token         string
+		secret        string
 		expectedError *httpError
 	}{
-		"valid credentials":  {uuid.New().String(), uuid.New().String(), nil},
-		"invalid account id": {"invalid-account-id", uuid.New().String(), &httpError{StatusCode: http.StatusBadRequest}},
-		"missing account id": {"", uuid.New().String(), &httpError{StatusCode: http.StatusBadRequest}},
-		"invalid token":      {uuid.New().String(), "invalid-token", &httpError{StatusCode: http.StatusBadRequest}},
-		"missing token":      {uuid.New().String(), "", &httpError{StatusCode: http.StatusBadRequest}},
+		"valid credentials":  {credentials.AccountID, credentials.Secret, nil},
+		"wrong secret":       {credentials.AccountID, "wrong-secret", &httpError{StatusCode: http.StatusUnauthorized, Code: "INVALID_CREDENTIALS"}},
+		"unknown account":    {uuid.New().String(), credentials.Secret, &httpError{StatusCode: http.StatusUnauthorized, Code: "INVALID_CREDENTIALS"}},
+		"invalid account id": {"invalid-account-id", credentials.Secret, &httpError{StatusCode: http.StatusBadRequest}},
+		"missing account id": {"", credentials.Secret, &httpError{StatusCode: http.StatusBadRequest}},
+		"missing secret":     {credentials.AccountID, "", &httpError{StatusCode: http.StatusBadRequest}},
 	}
 
 	for name, row := range table {
 		t.Run(name, func(t *testing.T) {
 			client := NewTestClient(config.Port)
 
-			_, err := client.Authenticate(row.accountID, row.token)
+			_, err := client.Login(row.accountID, row.secret)
 
 			if row.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, err.(*httpError).StatusCode, row.expectedError.StatusCode)
+				assert.Equal(t, row.expectedError.Code, err.(*httpError).Code)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -69,6 +90,70 @@ func TestAuthentication(t *testing.T) {
 	}
 }
 
+func TestLogout_ShouldEndSessionAndAbandonLevel(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	credentials, err := client.Register()
+	assert.NoError(t, err)
+
+	sessionID, err := client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	_, err = client.BeginLevel(sessionID, 1)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Logout(sessionID))
+
+	_, err = client.GetPlayerState(sessionID)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*httpError).StatusCode)
+	assert.Equal(t, sessions.ErrorCodeSessionLoggedOut, err.(*httpError).Code)
+
+	sessionID, err = client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	state, err := client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+	assert.Equal(t, []core.LevelStats{{LevelID: 1, Losses: 1}}, state.PlayerState.Persistent.LevelProgression.Statistics)
+}
+
+func TestLogin_WithRejectNewPolicy_ShouldKeepExistingSession(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	config.Sessions.Memory.Policy = sessions.PolicyRejectNew
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	credentials, err := client.Register()
+	assert.NoError(t, err)
+
+	sessionID, err := client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	_, err = client.Login(credentials.AccountID, credentials.Secret)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, err.(*httpError).StatusCode)
+	assert.Equal(t, "SESSION_LIMIT_REACHED", err.(*httpError).Code)
+
+	_, err = client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Logout(sessionID))
+
+	_, err = client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+}
+
 func TestGetPlayerState_Success(t *testing.T) {
 	config, err := GetDefaultTestConfig()
 	assert.NoError(t, err)
@@ -78,7 +163,7 @@ func TestGetPlayerState_Success(t *testing.T) {
 
 	client := NewTestClient(config.Port)
 
-	sessionID, err := client.Authenticate(uuid.New().String(), uuid.New().String())
+	sessionID, err := client.RegisterAndLogin()
 	assert.NoError(t, err)
 
 	state, err := client.GetPlayerState(sessionID)
@@ -102,6 +187,245 @@ func TestGetPlayerState_Unauthorized(t *testing.T) {
 	assert.Equal(t, err.(*httpError).StatusCode, http.StatusUnauthorized)
 }
 
+func TestGetPlayerState_WhenSessionIsReplaced_ShouldReturnSessionReplaced(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	credentials, err := client.Register()
+	assert.NoError(t, err)
+
+	firstSessionID, err := client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	_, err = client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	_, err = client.GetPlayerState(firstSessionID)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*httpError).StatusCode)
+	assert.Equal(t, sessions.ErrorCodeSessionReplaced, err.(*httpError).Code)
+
+	_, err = client.GetPlayerState("unknown")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*httpError).StatusCode)
+	assert.Empty(t, err.(*httpError).Code)
+}
+
+func TestRevokeSessions_ShouldReturnSessionRevoked(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+	config.AdminToken = "admin-token-0123456789"
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	credentials, err := client.Register()
+	assert.NoError(t, err)
+
+	sessionID, err := client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	err = client.RevokeSessions("wrong-token", credentials.AccountID)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*httpError).StatusCode)
+
+	_, err = client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.RevokeSessions(config.AdminToken, credentials.AccountID))
+
+	_, err = client.GetPlayerState(sessionID)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*httpError).StatusCode)
+	assert.Equal(t, sessions.ErrorCodeSessionRevoked, err.(*httpError).Code)
+}
+
+func TestRevokeSessions_WithoutAdminToken_ShouldNotBeServed(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	err = client.RevokeSessions("", "account-1")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*httpError).StatusCode)
+}
+
+func TestSubscribe_WhenSessionIsReplaced_ShouldPushSessionEnded(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	credentials, err := client.Register()
+	assert.NoError(t, err)
+
+	sessionID, err := client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	stream, err := client.Subscribe(sessionID)
+	assert.NoError(t, err)
+	defer stream.Close()
+
+	event, err := stream.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, string(push.EventConnected), event.Type)
+
+	_, err = client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	event, err = stream.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, string(push.EventSessionEnded), event.Type)
+	assert.JSONEq(t, `{"reason": "replaced"}`, string(event.Data))
+
+	_, err = stream.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestSubscribe_Unauthorized(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	_, err = client.Subscribe(uuid.New().String())
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*httpError).StatusCode)
+}
+
+func TestTokenSessions_ShouldKickOlderSession(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	config.Sessions = app.SessionsConfig{
+		Storage: app.SessionsStorageToken,
+		Token: token.SessionPoolConfig{
+			TTL:  30 * time.Second,
+			Keys: []token.Key{{ID: "test", Secret: []byte("0123456789abcdef0123456789abcdef")}},
+		},
+	}
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	credentials, err := client.Register()
+	assert.NoError(t, err)
+
+	firstSessionID, err := client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	_, err = client.BeginLevel(firstSessionID, 1)
+	assert.NoError(t, err)
+
+	secondSessionID, err := client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	_, err = client.GetPlayerState(firstSessionID)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*httpError).StatusCode)
+	assert.Equal(t, sessions.ErrorCodeSessionReplaced, err.(*httpError).Code)
+
+	state, err := client.GetPlayerState(secondSessionID)
+	assert.NoError(t, err)
+	assert.Nil(t, state.PlayerState.Session.CurrentLevelID)
+}
+
+func TestRedisSessions_ShouldKeepSessionData(t *testing.T) {
+	redisServer, err := fake.NewServer()
+	assert.NoError(t, err)
+	defer redisServer.Close()
+
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	config.Sessions = app.SessionsConfig{
+		Storage: app.SessionsStorageRedis,
+		Redis: redis.SessionPoolConfig{
+			Client:       resp.ClientConfig{Address: redisServer.Addr()},
+			TTL:          30 * time.Second,
+			TombstoneTTL: time.Minute,
+		},
+	}
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	_, err = client.BeginLevel(sessionID, 1)
+	assert.NoError(t, err)
+
+	state, err := client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+	assert.NotNil(t, state.PlayerState.Session.CurrentLevelID)
+}
+
+func TestGetConfigs_Success(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	configs, err := client.GetConfigs(sessionID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, configs.Configs.Levels)
+	assert.NotEmpty(t, configs.Version)
+
+	heartbeat, err := client.Heartbeat(sessionID)
+	assert.NoError(t, err)
+	assert.Equal(t, configs.Version, heartbeat.ConfigsVersion)
+}
+
+func TestRegister_ShouldUseOnboardingConfig(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	configs, err := client.GetConfigs(sessionID)
+	assert.NoError(t, err)
+	variant := configs.Configs.Onboarding.Variants[0]
+
+	state, err := client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+	assert.Equal(t, variant.Energy, state.PlayerState.Persistent.Energy.CurrentAmount)
+	assert.Equal(t, variant.CurrentLevel, state.PlayerState.Persistent.LevelProgression.CurrentLevel)
+}
+
 func TestBeginLevel_Success(t *testing.T) {
 	config, err := GetDefaultTestConfig()
 	assert.NoError(t, err)
@@ -111,11 +435,13 @@ func TestBeginLevel_Success(t *testing.T) {
 
 	client := NewTestClient(config.Port)
 
-	sessionID, err := client.Authenticate(uuid.New().String(), uuid.New().String())
+	sessionID, err := client.RegisterAndLogin()
 	assert.NoError(t, err)
 
-	err = client.BeginLevel(sessionID, 1)
+	res, err := client.BeginLevel(sessionID, 1)
 	assert.NoError(t, err)
+	assert.Equal(t, 1, *res.Session.CurrentLevelID)
+	assert.NotNil(t, res.Session.LevelSeed)
 }
 
 func TestBeginLevel_Unauthorized(t *testing.T) {
@@ -127,11 +453,29 @@ func TestBeginLevel_Unauthorized(t *testing.T) {
 
 	client := NewTestClient(config.Port)
 
-	err = client.BeginLevel(uuid.New().String(), 1)
+	_, err = client.BeginLevel(uuid.New().String(), 1)
 	assert.Error(t, err)
 	assert.Equal(t, err.(*httpError).StatusCode, http.StatusUnauthorized)
 }
 
+func TestBeginLevel_LevelNotUnlocked(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	_, err = client.BeginLevel(sessionID, 2)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(*httpError).StatusCode)
+	assert.Equal(t, "LEVEL_NOT_UNLOCKED", err.(*httpError).Code)
+}
+
 func TestEndLevel_Success(t *testing.T) {
 	config, err := GetDefaultTestConfig()
 	assert.NoError(t, err)
@@ -141,14 +485,88 @@ func TestEndLevel_Success(t *testing.T) {
 
 	client := NewTestClient(config.Port)
 
-	sessionID, err := client.Authenticate(uuid.New().String(), uuid.New().String())
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	configs, err := client.GetConfigs(sessionID)
+	assert.NoError(t, err)
+
+	res, err := client.BeginLevel(sessionID, 1)
+	assert.NoError(t, err)
+
+	_, err = client.HandleCommand(sessionID, PlayLevel(res, configs.Configs.Levels[1]))
+	assert.NoError(t, err)
+
+	state, err := client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+	assert.Nil(t, state.PlayerState.Session.CurrentLevelID)
+	assert.Len(t, state.PlayerState.Persistent.LevelProgression.Statistics, 1)
+}
+
+func TestEndLevel_ResultMismatch(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	_, err = client.BeginLevel(sessionID, 1)
+	assert.NoError(t, err)
+
+	err = client.EndLevel(sessionID, true, 100, 0)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(*httpError).StatusCode)
+	assert.Equal(t, "RESULT_MISMATCH", err.(*httpError).Code)
+}
+
+func TestBeginLevel_WhenSessionIsReplaced_ShouldRecordLoss(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	credentials, err := client.Register()
 	assert.NoError(t, err)
 
-	err = client.BeginLevel(sessionID, 1)
+	sessionID, err := client.Login(credentials.AccountID, credentials.Secret)
 	assert.NoError(t, err)
 
-	err = client.EndLevel(sessionID, true, 100)
+	_, err = client.BeginLevel(sessionID, 1)
+	assert.NoError(t, err)
+
+	sessionID, err = client.Login(credentials.AccountID, credentials.Secret)
+	assert.NoError(t, err)
+
+	state, err := client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+	assert.Nil(t, state.PlayerState.Session.CurrentLevelID)
+	assert.Equal(t, []core.LevelStats{{LevelID: 1, Losses: 1}}, state.PlayerState.Persistent.LevelProgression.Statistics)
+}
+
+func TestEndLevel_NoLevelInProgress(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
 	assert.NoError(t, err)
+
+	err = client.EndLevel(sessionID, true, 100, 0)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(*httpError).StatusCode)
+	assert.Equal(t, "NO_LEVEL_IN_PROGRESS", err.(*httpError).Code)
 }
 
 func TestEndLevel_Unauthorized(t *testing.T) {
@@ -160,14 +578,278 @@ func TestEndLevel_Unauthorized(t *testing.T) {
 
 	client := NewTestClient(config.Port)
 
-	err = client.EndLevel(uuid.New().String(), true, 100)
+	err = client.EndLevel(uuid.New().String(), true, 100, 0)
 	assert.Error(t, err)
 	assert.Equal(t, err.(*httpError).StatusCode, http.StatusUnauthorized)
 }
 
+func TestHandleCommands_Success(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	configs, err := client.GetConfigs(sessionID)
+	assert.NoError(t, err)
+
+	res, err := client.BeginLevel(sessionID, 1)
+	assert.NoError(t, err)
+
+	err = client.HandleCommands(sessionID, []commands.CommandArgs{
+		PlayLevel(res, configs.Configs.Levels[1]),
+		NewBeginLevelArgs(1),
+	})
+	assert.NoError(t, err)
+
+	state, err := client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+	assert.Len(t, state.PlayerState.Persistent.LevelProgression.Statistics, 1)
+	assert.Equal(t, 1, *state.PlayerState.Session.CurrentLevelID)
+}
+
+func TestHandleCommands_WithFailingCommand(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	err = client.HandleCommands(sessionID, []commands.CommandArgs{
+		NewBeginLevelArgs(1),
+		NewBeginLevelArgs(2),
+	})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(*httpError).StatusCode)
+	assert.Equal(t, "LEVEL_NOT_UNLOCKED", err.(*httpError).Code)
+	assert.Equal(t, 1, *err.(*httpError).Index)
+
+	state, err := client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+	assert.Nil(t, state.PlayerState.Session.CurrentLevelID)
+}
+
+func TestHandleCommands_WithSequence_ShouldIgnoreRetries(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	initialState, err := client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+
+	batch := []commands.CommandArgs{NewBeginLevelArgs(1)}
+
+	res, err := client.HandleSequencedCommands(sessionID, 1, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), res.Sequence)
+	assert.False(t, res.Duplicate)
+
+	res, err = client.HandleSequencedCommands(sessionID, 1, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), res.Sequence)
+	assert.True(t, res.Duplicate)
+
+	_, err = client.HandleSequencedCommands(sessionID, 3, []commands.CommandArgs{NewEndLevelArgs(true, 5, 5)})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, err.(*httpError).StatusCode)
+	assert.Equal(t, "SEQUENCE_GAP", err.(*httpError).Code)
+
+	state, err := client.GetPlayerState(sessionID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *state.PlayerState.Session.CurrentLevelID)
+	assert.Equal(t, initialState.PlayerState.Persistent.Revision+1, state.PlayerState.Persistent.Revision)
+}
+
+func TestListCommands(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	res, err := client.ListCommands()
+	assert.NoError(t, err)
+	assert.Len(t, res.Commands, 2)
+	assert.Equal(t, "BeginLevel", res.Commands[0].Name)
+	assert.Equal(t, "integer", res.Commands[0].Schema.Properties["levelId"].Type)
+	assert.Equal(t, "EndLevel", res.Commands[1].Name)
+}
+
+func TestHandleCommands_UnknownCommand(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	err = client.HandleCommands(sessionID, []commands.CommandArgs{
+		{Command: "SkipLevel", Data: []byte("{}")},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*httpError).StatusCode)
+	assert.Equal(t, "INVALID_COMMAND", err.(*httpError).Code)
+}
+
+func TestStop_WithOpenStream_ShouldNotWaitForDeadline(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	server := app.NewHTTP(config)
+	assert.NoError(t, server.Start(context.Background()))
+
+	// Without keep-alives, no idle connection is left for Shutdown to wait
+	// for, only the stream.
+	client := NewTestClient(config.Port)
+	client.Client.Transport = &http.Transport{DisableKeepAlives: true}
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+
+	stream, err := client.Subscribe(sessionID)
+	assert.NoError(t, err)
+	defer stream.Close()
+
+	_, err = stream.Next()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client.Client.CloseIdleConnections()
+	stopStart := time.Now()
+	assert.NoError(t, server.Stop(ctx))
+	assert.Less(t, time.Since(stopStart), time.Second)
+
+	_, err = stream.Next()
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = client.Register()
+	assert.Error(t, err)
+}
+
+func TestStop_WhenDeadlinePasses_ShouldCloseConnections(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	server := app.NewHTTP(config)
+	assert.NoError(t, server.Start(context.Background()))
+
+	// The handler blocks reading the body that never comes.
+	conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", config.Port))
+	assert.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "POST /AuthenticationHandler/Register HTTP/1.1\r\nHost: localhost\r\nContent-Length: 10\r\n\r\n")
+	assert.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, server.Stop(ctx), context.DeadlineExceeded)
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = io.ReadAll(conn)
+	assert.NoError(t, err)
+}
+
+func TestStop_WithFileStorage_ShouldKeepAccounts(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	config.Players = app.PlayersConfig{
+		Storage: app.PlayersStorageFile,
+		File:    playersfile.DALConfig{FilePath: filepath.Join(t.TempDir(), "players.log")},
+	}
+
+	server := app.NewHTTP(config)
+	assert.NoError(t, server.Start(context.Background()))
+
+	credentials, err := NewTestClient(config.Port).Register()
+	assert.NoError(t, err)
+
+	assert.NoError(t, server.Stop(context.Background()))
+
+	dal, err := playersfile.NewDAL(config.Players.File)
+	assert.NoError(t, err)
+	defer dal.Close()
+
+	_, err = dal.GetSecretHash(context.Background(), credentials.AccountID)
+	assert.NoError(t, err)
+}
+
+func TestStart_WhenPortIsTaken_ShouldReturnError(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", config.Port))
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	server := app.NewHTTP(config)
+	err = server.Start(context.Background())
+
+	assert.ErrorContains(t, err, "failed to start HTTP server")
+}
+
+func TestMetrics_ShouldReportTrafficAndGameplay(t *testing.T) {
+	config, err := GetDefaultTestConfig()
+	assert.NoError(t, err)
+
+	_, stop := runServer(t, config)
+	defer stop()
+
+	client := NewTestClient(config.Port)
+
+	sessionID, err := client.RegisterAndLogin()
+	assert.NoError(t, err)
+	_, err = client.BeginLevel(sessionID, 1)
+	assert.NoError(t, err)
+	_, err = client.BeginLevel(sessionID, 2)
+	assert.Error(t, err)
+
+	metrics, err := client.Metrics()
+	assert.NoError(t, err)
+
+	assert.Contains(t, metrics, `http_requests_total{route="POST /CommandHandler/HandleCommand",status="200"} 1`)
+	assert.Contains(t, metrics, `http_requests_total{route="POST /CommandHandler/HandleCommand",status="422"} 1`)
+	assert.Contains(t, metrics, `http_request_duration_seconds_count{route="POST /AuthenticationHandler/Login"} 1`)
+	assert.Contains(t, metrics, "\nsessions_active 1\n")
+	assert.Contains(t, metrics, "\naccounts 1\n")
+	assert.Contains(t, metrics, `command_executions_total{command="BeginLevel"} 2`)
+	assert.Contains(t, metrics, `command_failures_total{command="BeginLevel",error="LEVEL_NOT_UNLOCKED"} 1`)
+	assert.Contains(t, metrics, `configs_reloads_total{result="success"}`)
+	assert.Contains(t, metrics, "\nconfigs_last_reload_success 1\n")
+}
+
 func runServer(t *testing.T, config app.Config) (*app.HTTP, func()) {
 	server := app.NewHTTP(config)
-	go server.Run()
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+
 	return server, func() {
 		assert.NoError(t, server.Stop(context.Background()))
 	}
@@ -180,15 +862,25 @@ func GetDefaultTestConfig() (app.Config, error) {
 	}
 
 	return app.Config{
-		Port: port,
-		SessionPool: memory.SessionPoolConfig{
-			TTL: 30 * time.Second,
+		Port:            port,
+		ShutdownTimeout: 5 * time.Second,
+		RequestTimeout:  5 * time.Second,
+		Sessions: app.SessionsConfig{
+			Memory: memory.SessionPoolConfig{
+				TTL:          30 * time.Second,
+				TombstoneTTL: time.Minute,
+			},
 		},
 		ConfigProvider: configs.ProviderConfig{
 			FilePath: "../config/game_config.json",
 		},
 		Commands: commands.Config{
 			MaxTimeDifferenceSeconds: 1,
+			MaxConflictRetries:       3,
+			MaxBatchSize:             100,
+		},
+		Push: push.Config{
+			KeepAliveInterval: 5 * time.Second,
 		},
 	}, nil
 }
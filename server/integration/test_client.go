@@ -1,14 +1,21 @@
 package integration
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"technical-test-backend/internal/core"
 	"technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/core/dice"
+	adminhttp "technical-test-backend/internal/usecases/admin/http"
 	usecasesauthentication "technical-test-backend/internal/usecases/authentication"
 	usecasescommands "technical-test-backend/internal/usecases/commands"
 	usecasesconfigs "technical-test-backend/internal/usecases/configs"
+	heartbeathttp "technical-test-backend/internal/usecases/heartbeat/http"
 	usecasesplayers "technical-test-backend/internal/usecases/players"
 	"time"
 )
@@ -26,26 +33,48 @@ func NewTestClient(port int) *TestClient {
 }
 
 type errorResponse struct {
+	Code    string `json:"code"`
 	Message string `json:"message"`
+	Index   *int   `json:"index"`
 }
 
 func parseErrorResponse(resp *http.Response) error {
 	defer resp.Body.Close()
 	var errResp errorResponse
 	if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil && errResp.Message != "" {
-		return &httpError{StatusCode: resp.StatusCode, Message: errResp.Message}
+		return &httpError{StatusCode: resp.StatusCode, Code: errResp.Code, Message: errResp.Message, Index: errResp.Index}
 	}
 	return &httpError{StatusCode: resp.StatusCode, Message: ""}
 }
 
-func (tc *TestClient) Authenticate(accountID, accessToken string) (string, error) {
-	req := usecasesauthentication.AuthenticateArgs{
-		AccountID:   accountID,
-		AccessToken: accessToken,
+func (tc *TestClient) Register() (usecasesauthentication.RegisterRes, error) {
+	resp, err := tc.Client.Post(tc.BaseURL+"/AuthenticationHandler/Register",
+		"application/json", bytes.NewBufferString("{}"))
+	if err != nil {
+		return usecasesauthentication.RegisterRes{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return usecasesauthentication.RegisterRes{}, parseErrorResponse(resp)
+	}
+
+	var registerResp usecasesauthentication.RegisterRes
+	if err := json.NewDecoder(resp.Body).Decode(&registerResp); err != nil {
+		return usecasesauthentication.RegisterRes{}, err
+	}
+
+	return registerResp, nil
+}
+
+func (tc *TestClient) Login(accountID, secret string) (string, error) {
+	req := usecasesauthentication.LoginArgs{
+		AccountID: accountID,
+		Secret:    secret,
 	}
 
 	reqBody, _ := json.Marshal(req)
-	resp, err := tc.Client.Post(tc.BaseURL+"/AuthenticationHandler/Authenticate",
+	resp, err := tc.Client.Post(tc.BaseURL+"/AuthenticationHandler/Login",
 		"application/json", bytes.NewBuffer(reqBody))
 	if err != nil {
 		return "", err
@@ -59,6 +88,110 @@ func (tc *TestClient) Authenticate(accountID, accessToken string) (string, error
 	return resp.Header.Get("X-Session-ID"), nil
 }
 
+// RegisterAndLogin registers a new account and returns the ID of a session
+// logged into it.
+func (tc *TestClient) RegisterAndLogin() (string, error) {
+	credentials, err := tc.Register()
+	if err != nil {
+		return "", err
+	}
+
+	return tc.Login(credentials.AccountID, credentials.Secret)
+}
+
+func (tc *TestClient) Logout(sessionID string) error {
+	reqBody := []byte("{}")
+	req, _ := http.NewRequest("POST", tc.BaseURL+"/AuthenticationHandler/Logout",
+		bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Session-ID", sessionID)
+
+	resp, err := tc.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
+func (tc *TestClient) RevokeSessions(adminToken, accountID string) error {
+	reqBody, _ := json.Marshal(adminhttp.RevokeSessionsArgs{AccountID: accountID})
+	req, _ := http.NewRequest("POST", tc.BaseURL+"/AdminHandler/RevokeSessions",
+		bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+
+	resp, err := tc.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// EventStream reads the server-sent events of a push stream.
+type EventStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+}
+
+type streamEvent struct {
+	Type string
+	Data json.RawMessage
+}
+
+func (tc *TestClient) Subscribe(sessionID string) (*EventStream, error) {
+	req, _ := http.NewRequest("GET", tc.BaseURL+"/PushHandler/Subscribe", nil)
+	req.Header.Set("X-Session-ID", sessionID)
+
+	resp, err := tc.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, parseErrorResponse(resp)
+	}
+
+	return &EventStream{body: resp.Body, scanner: bufio.NewScanner(resp.Body)}, nil
+}
+
+// Next returns the next event, skipping comments.
+func (s *EventStream) Next() (streamEvent, error) {
+	var event streamEvent
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		switch {
+		case line == "" && event.Type != "":
+			return event, nil
+		case strings.HasPrefix(line, "event: "):
+			event.Type = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.Data = json.RawMessage(strings.TrimPrefix(line, "data: "))
+		}
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return streamEvent{}, err
+	}
+	return streamEvent{}, io.EOF
+}
+
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
+
 func (tc *TestClient) GetPlayerState(sessionID string) (usecasesplayers.GetPlayerStateRes, error) {
 	reqBody := []byte("{}")
 	req, _ := http.NewRequest("POST", tc.BaseURL+"/InitializationHandler/GetPlayerState",
@@ -109,19 +242,41 @@ func (tc *TestClient) GetConfigs(sessionID string) (usecasesconfigs.GetConfigsRe
 	return configsResp, nil
 }
 
-func (tc *TestClient) BeginLevel(sessionID string, levelID int) error {
-	beginCmd := commands.BeginLevel{
-		LevelID: levelID,
-		Now:     time.Now(),
+func (tc *TestClient) Heartbeat(sessionID string) (heartbeathttp.HeartbeatRes, error) {
+	reqBody := []byte("{}")
+	req, _ := http.NewRequest("POST", tc.BaseURL+"/HeartbeatHandler/Heartbeat",
+		bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Session-ID", sessionID)
+
+	resp, err := tc.Client.Do(req)
+	if err != nil {
+		return heartbeathttp.HeartbeatRes{}, err
 	}
+	defer resp.Body.Close()
 
-	beginData, _ := json.Marshal(beginCmd)
+	if resp.StatusCode != http.StatusOK {
+		return heartbeathttp.HeartbeatRes{}, parseErrorResponse(resp)
+	}
 
-	cmd := usecasescommands.CommandArgs{
-		Command: "BeginLevel",
-		Data:    beginData,
+	var heartbeatResp heartbeathttp.HeartbeatRes
+	if err := json.NewDecoder(resp.Body).Decode(&heartbeatResp); err != nil {
+		return heartbeathttp.HeartbeatRes{}, err
 	}
 
+	return heartbeatResp, nil
+}
+
+func (tc *TestClient) BeginLevel(sessionID string, levelID int) (usecasescommands.CommandRes, error) {
+	return tc.HandleCommand(sessionID, NewBeginLevelArgs(levelID))
+}
+
+func (tc *TestClient) EndLevel(sessionID string, success bool, score, rolls int) error {
+	_, err := tc.HandleCommand(sessionID, NewEndLevelArgs(success, score, rolls))
+	return err
+}
+
+func (tc *TestClient) HandleCommand(sessionID string, cmd usecasescommands.CommandArgs) (usecasescommands.CommandRes, error) {
 	reqBody, _ := json.Marshal(cmd)
 	req, _ := http.NewRequest("POST", tc.BaseURL+"/CommandHandler/HandleCommand",
 		bytes.NewBuffer(reqBody))
@@ -130,46 +285,120 @@ func (tc *TestClient) BeginLevel(sessionID string, levelID int) error {
 
 	resp, err := tc.Client.Do(req)
 	if err != nil {
-		return err
+		return usecasescommands.CommandRes{}, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return parseErrorResponse(resp)
+		return usecasescommands.CommandRes{}, parseErrorResponse(resp)
 	}
-	return nil
-}
+	defer resp.Body.Close()
 
-func (tc *TestClient) EndLevel(sessionID string, success bool, score int) error {
-	endCmd := commands.EndLevel{
-		Success: success,
-		Score:   score,
+	var commandRes usecasescommands.CommandRes
+	if err := json.NewDecoder(resp.Body).Decode(&commandRes); err != nil {
+		return usecasescommands.CommandRes{}, err
 	}
 
-	endData, _ := json.Marshal(endCmd)
+	return commandRes, nil
+}
 
-	cmd := usecasescommands.CommandArgs{
-		Command: "EndLevel",
-		Data:    endData,
-	}
+func (tc *TestClient) HandleCommands(sessionID string, commands []usecasescommands.CommandArgs) error {
+	_, err := tc.HandleSequencedCommands(sessionID, 0, commands)
+	return err
+}
 
-	reqBody, _ := json.Marshal(cmd)
-	req, _ := http.NewRequest("POST", tc.BaseURL+"/CommandHandler/HandleCommand",
+func (tc *TestClient) HandleSequencedCommands(sessionID string, sequence uint64, commands []usecasescommands.CommandArgs) (usecasescommands.CommandRes, error) {
+	reqBody, _ := json.Marshal(usecasescommands.BatchCommandArgs{
+		Sequence: sequence,
+		Commands: commands,
+	})
+	req, _ := http.NewRequest("POST", tc.BaseURL+"/CommandHandler/HandleCommands",
 		bytes.NewBuffer(reqBody))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Session-ID", sessionID)
 
 	resp, err := tc.Client.Do(req)
 	if err != nil {
-		return err
+		return usecasescommands.CommandRes{}, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return parseErrorResponse(resp)
+		return usecasescommands.CommandRes{}, parseErrorResponse(resp)
 	}
-	return nil
+	defer resp.Body.Close()
+
+	var commandRes usecasescommands.CommandRes
+	if err := json.NewDecoder(resp.Body).Decode(&commandRes); err != nil {
+		return usecasescommands.CommandRes{}, err
+	}
+
+	return commandRes, nil
+}
+
+func (tc *TestClient) ListCommands() (usecasescommands.ListCommandsRes, error) {
+	resp, err := tc.Client.Get(tc.BaseURL + "/CommandHandler/ListCommands")
+	if err != nil {
+		return usecasescommands.ListCommandsRes{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return usecasescommands.ListCommandsRes{}, parseErrorResponse(resp)
+	}
+
+	var listRes usecasescommands.ListCommandsRes
+	if err := json.NewDecoder(resp.Body).Decode(&listRes); err != nil {
+		return usecasescommands.ListCommandsRes{}, err
+	}
+
+	return listRes, nil
+}
+
+// Metrics returns the metrics in the Prometheus text format.
+func (tc *TestClient) Metrics() (string, error) {
+	resp, err := tc.Client.Get(tc.BaseURL + "/metrics")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", parseErrorResponse(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func NewBeginLevelArgs(levelID int) usecasescommands.CommandArgs {
+	data, _ := json.Marshal(commands.BeginLevel{
+		LevelID: levelID,
+		Now:     time.Now(),
+	})
+	return usecasescommands.CommandArgs{Command: "BeginLevel", Data: data}
+}
+
+func NewEndLevelArgs(success bool, score, rolls int) usecasescommands.CommandArgs {
+	data, _ := json.Marshal(commands.EndLevel{
+		Success: success,
+		Score:   score,
+		Rolls:   &rolls,
+	})
+	return usecasescommands.CommandArgs{Command: "EndLevel", Data: data}
+}
+
+// PlayLevel plays a seeded level to the end, returning the EndLevel command
+// reporting its result.
+func PlayLevel(res usecasescommands.CommandRes, levelConfig core.LevelConfig) usecasescommands.CommandArgs {
+	rolls, success := dice.Play(*res.Session.LevelSeed, levelConfig.MaxRolls, levelConfig.TargetNumber)
+	if !success {
+		return NewEndLevelArgs(false, 0, rolls)
+	}
+	return NewEndLevelArgs(true, levelConfig.MaxRolls-rolls, rolls)
 }
 
 type httpError struct {
 	StatusCode int
+	Code       string
 	Message    string
+	Index      *int
 }
 
 func (e *httpError) Error() string {
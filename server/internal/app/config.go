@@ -0,0 +1,211 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"technical-test-backend/internal/core"
+	httputils "technical-test-backend/internal/http"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/sessions/memory"
+	"technical-test-backend/internal/sessions/redis"
+	"technical-test-backend/internal/sessions/token"
+	"technical-test-backend/internal/usecases/commands"
+	"technical-test-backend/internal/usecases/configs"
+	playerssql "technical-test-backend/internal/usecases/players/dal/sql"
+	"technical-test-backend/internal/usecases/push"
+	"time"
+)
+
+// MinAdminTokenSize keeps the admin token out of reach of guessing.
+const MinAdminTokenSize = 16
+
+// DefaultConfig runs the server from the server directory, with in-memory
+// sessions and players.
+func DefaultConfig() Config {
+	return Config{
+		Port:            8080,
+		GRPCPort:        9090,
+		ShutdownTimeout: 10 * time.Second,
+		RequestTimeout:  5 * time.Second,
+		Log: LogConfig{
+			Level:  slog.LevelInfo,
+			Format: LogFormatText,
+			Requests: httputils.LogConfig{
+				Redact: []string{"X-Session-ID", "Authorization", "secret"},
+			},
+		},
+		Sessions: SessionsConfig{
+			Storage: SessionsStorageMemory,
+			Memory: memory.SessionPoolConfig{
+				TTL:          10 * time.Second,
+				Policy:       sessions.PolicyKickOld,
+				TombstoneTTL: 10 * time.Minute,
+			},
+			Token: token.SessionPoolConfig{
+				TTL: time.Hour,
+			},
+			Redis: redis.SessionPoolConfig{
+				TTL:           10 * time.Second,
+				TombstoneTTL:  10 * time.Minute,
+				SweepInterval: 10 * time.Second,
+			},
+		},
+		ConfigProvider: configs.ProviderConfig{
+			FilePath:       "config/game_config.json",
+			ReloadInterval: 5 * time.Second,
+		},
+		Commands: commands.Config{
+			MaxTimeDifferenceSeconds: 1,
+			MaxConflictRetries:       3,
+			MaxBatchSize:             100,
+			AbandonTimeout:           5 * time.Second,
+		},
+		Players: PlayersConfig{
+			Storage: PlayersStorageMemory,
+			SQL: playerssql.DALConfig{
+				Driver: playerssql.DriverSQLite,
+			},
+		},
+		Push: push.Config{
+			KeepAliveInterval: 5 * time.Second,
+		},
+	}
+}
+
+// Validate returns every invalid setting as core.ConfigViolations, located by
+// the name of the setting. Only the settings of the selected storages are
+// checked.
+func (c Config) Validate() error {
+	var violations core.ConfigViolations
+	violate := func(name string, format string, args ...interface{}) {
+		violations = append(violations, core.ConfigViolation{Path: name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		violate("port", "must be between 1 and 65535")
+	}
+	if c.GRPCPort < 0 || c.GRPCPort > 65535 {
+		violate("grpcPort", "must be between 0 and 65535")
+	} else if c.GRPCPort == c.Port {
+		violate("grpcPort", "must differ from port")
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		violate("shutdownTimeout", "must be positive")
+	}
+
+	if c.RequestTimeout < 0 {
+		violate("requestTimeout", "must not be negative")
+	}
+
+	if c.AdminToken != "" && len(c.AdminToken) < MinAdminTokenSize {
+		violate("adminToken", "must be at least %d bytes", MinAdminTokenSize)
+	}
+
+	if c.Log.Format != LogFormatText && c.Log.Format != LogFormatJSON {
+		violate("log.format", "must be one of %s or %s", LogFormatText, LogFormatJSON)
+	}
+
+	sessionTTL := time.Duration(0)
+	switch c.Sessions.Storage {
+	case "", SessionsStorageMemory:
+		sessionTTL = c.Sessions.Memory.TTL
+		if c.Sessions.Memory.TTL <= 0 {
+			violate("sessions.memory.ttl", "must be positive")
+		}
+		switch c.Sessions.Memory.Policy {
+		case "", sessions.PolicyKickOld, sessions.PolicyRejectNew, sessions.PolicyConcurrent:
+		default:
+			violate("sessions.memory.policy", "must be one of %s, %s or %s", sessions.PolicyKickOld, sessions.PolicyRejectNew, sessions.PolicyConcurrent)
+		}
+		if c.Sessions.Memory.Policy == sessions.PolicyConcurrent && c.Sessions.Memory.MaxSessions < 1 {
+			violate("sessions.memory.maxSessions", "must be at least 1 with the %s policy", sessions.PolicyConcurrent)
+		}
+		if c.Sessions.Memory.TombstoneTTL < 0 {
+			violate("sessions.memory.tombstoneTTL", "must not be negative")
+		}
+	case SessionsStorageToken:
+		sessionTTL = c.Sessions.Token.TTL
+		if c.Sessions.Token.TTL <= 0 {
+			violate("sessions.token.ttl", "must be positive")
+		}
+		if len(c.Sessions.Token.Keys) == 0 {
+			violate("sessions.token.keys", "must hold at least one key")
+		}
+		for _, key := range c.Sessions.Token.Keys {
+			if key.ID == "" || len(key.Secret) < token.MinKeySize {
+				violate("sessions.token.keys", "key %q must have an id and a secret of at least %d bytes", key.ID, token.MinKeySize)
+			}
+		}
+	case SessionsStorageRedis:
+		sessionTTL = c.Sessions.Redis.TTL
+		if c.Sessions.Redis.Client.Address == "" {
+			violate("sessions.redis.address", "must be set")
+		}
+		if c.Sessions.Redis.TTL <= 0 {
+			violate("sessions.redis.ttl", "must be positive")
+		}
+		if c.Sessions.Redis.TombstoneTTL < 0 {
+			violate("sessions.redis.tombstoneTTL", "must not be negative")
+		}
+		if c.Sessions.Redis.SweepInterval < 0 {
+			violate("sessions.redis.sweepInterval", "must not be negative")
+		}
+	default:
+		violate("sessions.storage", "must be one of %s, %s or %s", SessionsStorageMemory, SessionsStorageToken, SessionsStorageRedis)
+	}
+
+	if c.ConfigProvider.FilePath == "" {
+		violate("configProvider.filePath", "must be set")
+	} else if _, err := os.Stat(c.ConfigProvider.FilePath); err != nil {
+		violate("configProvider.filePath", "can't be read: %v", err)
+	}
+	if c.ConfigProvider.ReloadInterval < 0 {
+		violate("configProvider.reloadInterval", "must not be negative")
+	}
+
+	if c.Commands.MaxTimeDifferenceSeconds <= 0 {
+		violate("commands.maxTimeDifferenceSeconds", "must be positive")
+	}
+	if c.Commands.MaxConflictRetries < 0 {
+		violate("commands.maxConflictRetries", "must not be negative")
+	}
+	if c.Commands.MaxBatchSize < 1 {
+		violate("commands.maxBatchSize", "must be at least 1")
+	}
+	if c.Commands.AbandonTimeout < 0 {
+		violate("commands.abandonTimeout", "must not be negative")
+	}
+
+	switch c.Players.Storage {
+	case "", PlayersStorageMemory:
+	case PlayersStorageFile:
+		if c.Players.File.FilePath == "" {
+			violate("players.file.filePath", "must be set")
+		}
+		if c.Players.File.CompactionRatio < 0 {
+			violate("players.file.compactionRatio", "must not be negative")
+		}
+	case PlayersStorageSQL:
+		if c.Players.SQL.Driver != playerssql.DriverSQLite {
+			violate("players.sql.driver", "must be %s", playerssql.DriverSQLite)
+		}
+		if c.Players.SQL.DSN == "" {
+			violate("players.sql.dsn", "must be set")
+		}
+	default:
+		violate("players.storage", "must be one of %s, %s or %s", PlayersStorageMemory, PlayersStorageFile, PlayersStorageSQL)
+	}
+
+	if c.Push.KeepAliveInterval < 0 {
+		violate("push.keepAliveInterval", "must not be negative")
+	} else if c.Push.KeepAliveInterval > 0 && sessionTTL > 0 && c.Push.KeepAliveInterval >= sessionTTL {
+		violate("push.keepAliveInterval", "must be shorter than the session ttl (%s)", sessionTTL)
+	}
+
+	if len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	corecommands "technical-test-backend/internal/core/commands"
+	grpcutils "technical-test-backend/internal/grpc"
+	"technical-test-backend/internal/grpc/gamepb"
+	"technical-test-backend/internal/metrics"
+	"technical-test-backend/internal/sessions"
+	authenticationgrpc "technical-test-backend/internal/usecases/authentication/grpc"
+	"technical-test-backend/internal/usecases/commands"
+	commandsgrpc "technical-test-backend/internal/usecases/commands/grpc"
+	"technical-test-backend/internal/usecases/configs"
+	configsgrpc "technical-test-backend/internal/usecases/configs/grpc"
+	heartbeatgrpc "technical-test-backend/internal/usecases/heartbeat/grpc"
+	"technical-test-backend/internal/usecases/players"
+	playersgrpc "technical-test-backend/internal/usecases/players/grpc"
+
+	"google.golang.org/grpc"
+)
+
+// initializationServer serves the InitializationHandler service, which is
+// split between the players and configs use cases like its HTTP routes.
+type initializationServer struct {
+	gamepb.UnimplementedInitializationHandlerServer
+
+	players *playersgrpc.Handler
+	configs *configsgrpc.Handler
+}
+
+func (s *initializationServer) GetPlayerState(ctx context.Context, req *gamepb.GetPlayerStateRequest) (*gamepb.GetPlayerStateResponse, error) {
+	return s.players.GetPlayerState(ctx, req)
+}
+
+func (s *initializationServer) GetConfigs(ctx context.Context, req *gamepb.GetConfigsRequest) (*gamepb.GetConfigsResponse, error) {
+	return s.configs.GetConfigs(ctx, req)
+}
+
+// createGRPCServer serves the same use cases as the HTTP routes, sharing the
+// command handler so both transports lock the same accounts.
+func createGRPCServer(sessionPool sessions.Pool, dal players.DAL, configsProvider *configs.Provider, commandHandler *commands.Handler, registry *corecommands.Registry, metricsRegistry *metrics.Registry) *grpc.Server {
+	metricsInterceptor := grpcutils.NewMetricsInterceptor(metricsRegistry)
+	authInterceptor := grpcutils.NewAuthInterceptor(sessionPool,
+		gamepb.AuthenticationHandler_Register_FullMethodName,
+		gamepb.AuthenticationHandler_Login_FullMethodName,
+	)
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(metricsInterceptor.Unary, authInterceptor.Unary))
+
+	gamepb.RegisterAuthenticationHandlerServer(server, authenticationgrpc.CreateGRPCHandler(sessionPool, dal, configsProvider))
+	gamepb.RegisterInitializationHandlerServer(server, &initializationServer{
+		players: playersgrpc.CreateGRPCHandler(sessionPool, dal),
+		configs: configsgrpc.CreateGRPCHandler(configsProvider),
+	})
+	gamepb.RegisterCommandHandlerServer(server, commandsgrpc.CreateGRPCHandler(commandHandler, sessionPool, registry))
+	gamepb.RegisterHeartbeatHandlerServer(server, heartbeatgrpc.CreateGRPCHandler(sessionPool, configsProvider))
+
+	return server
+}
@@ -4,74 +4,261 @@
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	corecommands "technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/errors"
 	httputils "technical-test-backend/internal/http"
-	"technical-test-backend/internal/sessions/memory"
+	"technical-test-backend/internal/lifecycle"
+	"technical-test-backend/internal/metrics"
+	"technical-test-backend/internal/sessions"
+	adminhttp "technical-test-backend/internal/usecases/admin/http"
 	authenticationhttp "technical-test-backend/internal/usecases/authentication/http"
 	"technical-test-backend/internal/usecases/commands"
 	commandshttp "technical-test-backend/internal/usecases/commands/http"
 	"technical-test-backend/internal/usecases/configs"
 	configshttp "technical-test-backend/internal/usecases/configs/http"
 	heartbeathttp "technical-test-backend/internal/usecases/heartbeat/http"
-	playersmemory "technical-test-backend/internal/usecases/players/dal/memory"
+	"technical-test-backend/internal/usecases/players"
 	playershttp "technical-test-backend/internal/usecases/players/http"
+	"technical-test-backend/internal/usecases/push"
+	pushhttp "technical-test-backend/internal/usecases/push/http"
 	"time"
+
+	"google.golang.org/grpc"
 )
 
+// closedHandlersTimeout is how long the handlers of the connections closed at
+// the end of the shutdown are given to return, as their contexts are canceled.
+const closedHandlersTimeout = 5 * time.Second
+
 type Config struct {
 	Port           int
-	SessionPool    memory.SessionPoolConfig
+	Sessions       SessionsConfig
 	ConfigProvider configs.ProviderConfig
 	Commands       commands.Config
+	Players        PlayersConfig
+	Push           push.Config
+	// GRPCPort serves the gRPC transport alongside the HTTP one. Zero
+	// disables it.
+	GRPCPort int
+	// ShutdownTimeout is how long in-flight requests are given to complete
+	// when stopping.
+	ShutdownTimeout time.Duration
+	// RequestTimeout bounds the context of every HTTP request but the push
+	// stream. Zero disables it.
+	RequestTimeout time.Duration
+	// Log configures the logger set up by NewLogger, and the request logs.
+	Log LogConfig
+	// AdminToken authenticates the moderation endpoints. Empty disables them.
+	AdminToken string
 }
 
 type HTTP struct {
-	config Config
-	server *http.Server
+	config     Config
+	lifecycle  *lifecycle.Lifecycle
+	server     *http.Server
+	inFlight   *httputils.InFlightMiddleware
+	grpcServer *grpc.Server
+	// serveErrors receives the errors stopping the servers while running.
+	serveErrors chan error
 }
 
 func NewHTTP(config Config) *HTTP {
 	return &HTTP{
-		config: config,
+		config:      config,
+		lifecycle:   lifecycle.New(),
+		inFlight:    httputils.NewInFlightMiddleware(),
+		serveErrors: make(chan error, 2),
+	}
+}
+
+// Run starts the server and stops it once ctx is done or a server failed,
+// giving in-flight requests up to ShutdownTimeout to complete.
+func (a *HTTP) Run(ctx context.Context) error {
+	if err := a.Start(ctx); err != nil {
+		return err
 	}
+
+	var serveErr error
+	select {
+	case <-ctx.Done():
+		log.Printf("Shutting down")
+	case serveErr = <-a.serveErrors:
+		log.Printf("Shutting down after a server failed: %v", serveErr)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), a.config.ShutdownTimeout)
+	defer cancel()
+
+	return errors.Join(serveErr, a.Stop(stopCtx))
 }
 
-func (a *HTTP) Run() {
-	sessionPool, close := memory.CreateSessionPool(a.config.SessionPool)
-	defer close()
+// Start creates the components and starts them, returning once the servers
+// accept connections.
+func (a *HTTP) Start(ctx context.Context) error {
+	// The hooks are stopped in reverse order: the servers first, so no request
+	// is left using the components stopped after them.
+	accountsDal, err := createPlayersDAL(a.lifecycle, a.config.Players)
+	if err != nil {
+		return fmt.Errorf("failed to create players DAL: %w", err)
+	}
+
+	sessionPool, err := createSessionPool(a.lifecycle, a.config.Sessions)
+	if err != nil {
+		return fmt.Errorf("failed to create session pool: %w", err)
+	}
+
+	metricsRegistry := metrics.NewRegistry()
+	registerStorageMetrics(metricsRegistry, sessionPool, accountsDal)
+
+	configsProvider := configs.CreateProvider(a.lifecycle, a.config.ConfigProvider, metricsRegistry)
+	commandRegistry := corecommands.NewDefaultRegistry()
+	sharedCommandHandler := commands.CreateHandler(a.config.Commands, sessionPool, accountsDal, configsProvider, metricsRegistry)
+
+	a.server = &http.Server{
+		Addr: fmt.Sprintf(":%d", a.config.Port),
+	}
+	a.server.Handler = a.inFlight.Middleware(a.createMux(sessionPool, accountsDal, configsProvider, sharedCommandHandler, commandRegistry, metricsRegistry))
+	a.lifecycle.Append(lifecycle.Hook{
+		Name: "HTTP server",
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", a.server.Addr)
+			if err != nil {
+				return err
+			}
+
+			log.Printf("Starting server on port %d", a.config.Port)
+			go func() {
+				if err := a.server.Serve(listener); err != http.ErrServerClosed {
+					a.serveErrors <- fmt.Errorf("HTTP server: %w", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: a.stopHTTPServer,
+	})
 
-	accountsDal := playersmemory.NewDAL()
-	configsProvider := configs.NewProvider(a.config.ConfigProvider)
+	if a.config.GRPCPort > 0 {
+		a.grpcServer = createGRPCServer(sessionPool, accountsDal, configsProvider, sharedCommandHandler, commandRegistry, metricsRegistry)
+		a.lifecycle.Append(lifecycle.Hook{
+			Name: "gRPC server",
+			OnStart: func(ctx context.Context) error {
+				listener, err := net.Listen("tcp", fmt.Sprintf(":%d", a.config.GRPCPort))
+				if err != nil {
+					return err
+				}
+
+				log.Printf("Starting gRPC server on port %d", a.config.GRPCPort)
+				go func() {
+					if err := a.grpcServer.Serve(listener); err != nil {
+						a.serveErrors <- fmt.Errorf("gRPC server: %w", err)
+					}
+				}()
+				return nil
+			},
+			OnStop: a.stopGRPCServer,
+		})
+	}
 
-	authHandler := authenticationhttp.CreateHTTPHandler(sessionPool, accountsDal)
+	return a.lifecycle.Start(ctx)
+}
+
+// Stop stops accepting requests and waits for the in-flight ones until ctx is
+// done, before stopping the components and closing the storages.
+func (a *HTTP) Stop(ctx context.Context) error {
+	return a.lifecycle.Stop(ctx)
+}
+
+func (a *HTTP) createMux(sessionPool sessions.Pool, accountsDal players.DAL, configsProvider *configs.Provider, sharedCommandHandler *commands.Handler, commandRegistry *corecommands.Registry, metricsRegistry *metrics.Registry) *http.ServeMux {
+	authHandler := authenticationhttp.CreateHTTPHandler(sessionPool, accountsDal, configsProvider)
 	stateHandler := playershttp.CreateHTTPHandler(sessionPool, accountsDal)
 	configHandler := configshttp.CreateHTTPHandler(configsProvider)
-	commandHandler := commandshttp.CreateHTTPHandler(a.config.Commands, sessionPool, accountsDal, configsProvider)
-	heartbeatHandler := heartbeathttp.CreateHTTPHandler(sessionPool)
+	commandHandler := commandshttp.CreateHTTPHandler(sharedCommandHandler, sessionPool, commandRegistry)
+	heartbeatHandler := heartbeathttp.CreateHTTPHandler(sessionPool, configsProvider)
+
+	// The timeout wraps the auth middleware, which reads the session pool.
+	withTimeout := func(next http.HandlerFunc) http.HandlerFunc {
+		return httputils.TimeoutMiddleware(a.config.RequestTimeout, next)
+	}
+
+	logMiddleware := httputils.NewLogMiddleware(slog.Default(), a.config.Log.Requests)
+	metricsMiddleware := httputils.NewMetricsMiddleware(metricsRegistry)
+	observe := func(next http.HandlerFunc) http.HandlerFunc {
+		return logMiddleware.Middleware(metricsMiddleware.Middleware(next))
+	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /health", httputils.LogMiddleware(handleHealth))
-	mux.HandleFunc("POST /AuthenticationHandler/Authenticate", httputils.LogMiddleware(authHandler.HandleAuthenticate))
+	// Not observed, every scrape would be logged.
+	mux.Handle("GET /metrics", metricsRegistry)
+	mux.HandleFunc("GET /health", observe(handleHealth))
+	mux.HandleFunc("POST /AuthenticationHandler/Register", observe(withTimeout(authHandler.HandleRegister)))
+	mux.HandleFunc("POST /AuthenticationHandler/Login", observe(withTimeout(authHandler.HandleLogin)))
+	mux.HandleFunc("GET /CommandHandler/ListCommands", observe(commandHandler.HandleListCommands))
 
 	authMiddleware := httputils.NewAuthMiddleware(sessionPool)
-	mux.HandleFunc("POST /InitializationHandler/GetPlayerState", httputils.LogMiddleware(authMiddleware.Middleware(stateHandler.HandleGetPlayerState)))
-	mux.HandleFunc("POST /InitializationHandler/GetConfigs", httputils.LogMiddleware(authMiddleware.Middleware(configHandler.HandleGetConfigs)))
-	mux.HandleFunc("POST /CommandHandler/HandleCommand", httputils.LogMiddleware(authMiddleware.Middleware(commandHandler.HandleCommand)))
-	mux.HandleFunc("POST /HeartbeatHandler/Heartbeat", httputils.LogMiddleware(authMiddleware.Middleware(heartbeatHandler.HandleHeartbeat)))
+	mux.HandleFunc("POST /InitializationHandler/GetPlayerState", observe(withTimeout(authMiddleware.Middleware(stateHandler.HandleGetPlayerState))))
+	mux.HandleFunc("POST /InitializationHandler/GetConfigs", observe(withTimeout(authMiddleware.Middleware(configHandler.HandleGetConfigs))))
+	mux.HandleFunc("POST /CommandHandler/HandleCommand", observe(withTimeout(authMiddleware.Middleware(commandHandler.HandleCommand))))
+	mux.HandleFunc("POST /CommandHandler/HandleCommands", observe(withTimeout(authMiddleware.Middleware(commandHandler.HandleCommands))))
+	mux.HandleFunc("POST /HeartbeatHandler/Heartbeat", observe(withTimeout(authMiddleware.Middleware(heartbeatHandler.HandleHeartbeat))))
+	mux.HandleFunc("POST /AuthenticationHandler/Logout", observe(withTimeout(authMiddleware.Middleware(authHandler.HandleLogout))))
 
-	a.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", a.config.Port),
-		Handler: mux,
+	if a.config.AdminToken != "" {
+		adminHandler := adminhttp.CreateHTTPHandler(sessionPool)
+		adminMiddleware := httputils.NewAdminMiddleware(a.config.AdminToken)
+		mux.HandleFunc("POST /AdminHandler/RevokeSessions", observe(withTimeout(adminMiddleware.Middleware(adminHandler.HandleRevokeSessions))))
 	}
 
-	log.Printf("Starting server on port %d", a.config.Port)
-	if err := a.server.ListenAndServe(); err != http.ErrServerClosed {
-		log.Fatalf("Failed to start server: %v", err)
+	if a.config.Push.KeepAliveInterval > 0 {
+		pushHandler, closePush := pushhttp.CreateHTTPHandler(a.config.Push, sessionPool, accountsDal, configsProvider)
+		// The streams never end on their own, they'd hold the shutdown until
+		// its deadline.
+		a.server.RegisterOnShutdown(closePush)
+		// Logged once it ends, its body is never kept.
+		mux.HandleFunc("GET /PushHandler/Subscribe", observe(authMiddleware.Middleware(pushHandler.HandleSubscribe)))
 	}
+
+	return mux
 }
 
-func (a *HTTP) Stop(ctx context.Context) error {
-	return a.server.Shutdown(context.Background())
+// stopHTTPServer waits for the in-flight requests until ctx is done, and
+// closes the remaining connections then. As closing doesn't wait for their
+// handlers, they're given up to closedHandlersTimeout to return, so they don't
+// outlive the stop of the components they use.
+func (a *HTTP) stopHTTPServer(ctx context.Context) error {
+	err := a.server.Shutdown(ctx)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		a.server.Close()
+
+		waitCtx, cancel := context.WithTimeout(context.Background(), closedHandlersTimeout)
+		defer cancel()
+		if err := a.inFlight.Wait(waitCtx); err != nil {
+			return errors.Join(ctxErr, fmt.Errorf("HTTP handlers still running: %w", err))
+		}
+		return ctxErr
+	}
+
+	return err
+}
+
+// stopGRPCServer waits for the in-flight calls until ctx is done, and cancels
+// the remaining ones then.
+func (a *HTTP) stopGRPCServer(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		a.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		a.grpcServer.Stop()
+		return ctx.Err()
+	}
 }
 
 func handleHealth(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	httputils "technical-test-backend/internal/http"
+)
+
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJSON LogFormat = "json"
+)
+
+type LogConfig struct {
+	Level    slog.Level
+	Format   LogFormat
+	Requests httputils.LogConfig
+}
+
+// NewLogger writes the logs of the configured level and above to w. Once set
+// as the slog default, it also receives the lines of the log package.
+func NewLogger(config LogConfig, w io.Writer) *slog.Logger {
+	options := &slog.HandlerOptions{Level: config.Level}
+
+	if config.Format == LogFormatJSON {
+		return slog.New(slog.NewJSONHandler(w, options))
+	}
+
+	return slog.New(slog.NewTextHandler(w, options))
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"technical-test-backend/internal/metrics"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/players"
+)
+
+// registerStorageMetrics reports the number of sessions and accounts, read
+// from the storages on every scrape. The token storage only counts the
+// sessions known to its store, the redis one those of all instances. A failed
+// read leaves the sample out, and is counted in metrics.ScrapeErrorsMetric.
+func registerStorageMetrics(registry *metrics.Registry, sessionPool sessions.Pool, dal players.DAL) {
+	if counter, ok := sessionPool.(interface{ GetSessionCount() (int, error) }); ok {
+		registry.NewGaugeFunc("sessions_active", "Sessions alive.", func() (float64, error) {
+			count, err := counter.GetSessionCount()
+			return float64(count), err
+		})
+	}
+
+	if counter, ok := dal.(interface{ GetAccountCount() (int, error) }); ok {
+		registry.NewGaugeFunc("accounts", "Accounts registered.", func() (float64, error) {
+			count, err := counter.GetAccountCount()
+			return float64(count), err
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"technical-test-backend/internal/lifecycle"
+	"technical-test-backend/internal/usecases/players"
+	playersfile "technical-test-backend/internal/usecases/players/dal/file"
+	playersmemory "technical-test-backend/internal/usecases/players/dal/memory"
+	playerssql "technical-test-backend/internal/usecases/players/dal/sql"
+
+	_ "modernc.org/sqlite"
+)
+
+type PlayersStorage string
+
+const (
+	PlayersStorageMemory PlayersStorage = "memory"
+	PlayersStorageFile   PlayersStorage = "file"
+	PlayersStorageSQL    PlayersStorage = "sql"
+)
+
+type PlayersConfig struct {
+	Storage PlayersStorage
+	File    playersfile.DALConfig
+	SQL     playerssql.DALConfig
+}
+
+// createPlayersDAL registers the closing of the storage on the lifecycle. As
+// it's the first hook appended, it's closed once everything writing to it has
+// stopped.
+func createPlayersDAL(lc *lifecycle.Lifecycle, config PlayersConfig) (players.DAL, error) {
+	var dal interface {
+		players.DAL
+		Close() error
+	}
+
+	switch config.Storage {
+	case "", PlayersStorageMemory:
+		return playersmemory.NewDAL(), nil
+	case PlayersStorageFile:
+		fileDAL, err := playersfile.NewDAL(config.File)
+		if err != nil {
+			return nil, err
+		}
+		dal = fileDAL
+	case PlayersStorageSQL:
+		sqlDAL, err := playerssql.NewDAL(config.SQL)
+		if err != nil {
+			return nil, err
+		}
+		dal = sqlDAL
+	default:
+		return nil, fmt.Errorf("unknown players storage %q", config.Storage)
+	}
+
+	lc.Append(lifecycle.Hook{
+		Name: "players storage",
+		OnStop: func(ctx context.Context) error {
+			return dal.Close()
+		},
+	})
+
+	return dal, nil
+}
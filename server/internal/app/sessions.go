@@ -0,0 +1,38 @@
+package app
+
+import (
+	"fmt"
+	"technical-test-backend/internal/lifecycle"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/sessions/memory"
+	"technical-test-backend/internal/sessions/redis"
+	"technical-test-backend/internal/sessions/token"
+)
+
+type SessionsStorage string
+
+const (
+	SessionsStorageMemory SessionsStorage = "memory"
+	SessionsStorageToken  SessionsStorage = "token"
+	SessionsStorageRedis  SessionsStorage = "redis"
+)
+
+type SessionsConfig struct {
+	Storage SessionsStorage
+	Memory  memory.SessionPoolConfig
+	Token   token.SessionPoolConfig
+	Redis   redis.SessionPoolConfig
+}
+
+func createSessionPool(lc *lifecycle.Lifecycle, config SessionsConfig) (sessions.Pool, error) {
+	switch config.Storage {
+	case "", SessionsStorageMemory:
+		return memory.CreateSessionPool(lc, config.Memory), nil
+	case SessionsStorageToken:
+		return token.CreateSessionPool(lc, config.Token)
+	case SessionsStorageRedis:
+		return redis.CreateSessionPool(lc, config.Redis), nil
+	default:
+		return nil, fmt.Errorf("unknown sessions storage %q", config.Storage)
+	}
+}
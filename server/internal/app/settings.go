@@ -0,0 +1,429 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/sessions/token"
+	"time"
+	"unicode"
+)
+
+// EnvPrefix prefixes the environment variables of the settings, e.g.
+// SERVER_SESSIONS_MEMORY_TTL for sessions.memory.ttl.
+const EnvPrefix = "SERVER_"
+
+// ConfigFileEnv holds the path of the config file when the -config flag is
+// not given.
+const ConfigFileEnv = EnvPrefix + "CONFIG"
+
+// redacted replaces the values of secret settings when printing them.
+const redacted = "<redacted>"
+
+var (
+	ErrUnknownSetting    = errors.New("unknown setting")
+	ErrInvalidConfigFile = errors.New("invalid config file")
+)
+
+// setting is a single value of Config, settable from a string so the config
+// file, environment variables and flags share the same parsing.
+type setting struct {
+	name  string
+	usage string
+	value flag.Getter
+	// path resolves a relative value against the directory of the config
+	// file it's read from.
+	path   bool
+	secret bool
+}
+
+func (s setting) env() string {
+	var env strings.Builder
+	env.WriteString(EnvPrefix)
+
+	previous := rune(0)
+	for _, r := range s.name {
+		switch {
+		case r == '.':
+			env.WriteRune('_')
+		case unicode.IsUpper(r) && unicode.IsLower(previous):
+			env.WriteRune('_')
+			env.WriteRune(r)
+		default:
+			env.WriteRune(unicode.ToUpper(r))
+		}
+		previous = r
+	}
+
+	return env.String()
+}
+
+func settingsOf(config *Config) []setting {
+	return []setting{
+		{name: "port", usage: "HTTP port", value: (*intValue)(&config.Port)},
+		{name: "grpcPort", usage: "gRPC port, zero disables gRPC", value: (*intValue)(&config.GRPCPort)},
+		{name: "shutdownTimeout", usage: "time given to in-flight requests when stopping", value: (*durationValue)(&config.ShutdownTimeout)},
+		{name: "requestTimeout", usage: "time given to a request to complete, zero disables it", value: (*durationValue)(&config.RequestTimeout)},
+		{name: "adminToken", usage: "bearer token of the admin endpoints, empty disables them", value: newStringValue(&config.AdminToken), secret: true},
+
+		{name: "log.level", usage: "minimum level logged: debug, info, warn or error", value: (*levelValue)(&config.Log.Level)},
+		{name: "log.format", usage: "log format: text or json", value: newStringValue(&config.Log.Format)},
+		{name: "log.requests.bodies", usage: "log the headers and bodies of the requests and responses", value: (*boolValue)(&config.Log.Requests.Bodies)},
+		{name: "log.requests.redact", usage: "comma separated headers and JSON fields redacted from the logs", value: (*listValue)(&config.Log.Requests.Redact)},
+
+		{name: "sessions.storage", usage: "sessions storage: memory, token or redis", value: newStringValue(&config.Sessions.Storage)},
+		{name: "sessions.memory.ttl", usage: "lifetime of an idle session", value: (*durationValue)(&config.Sessions.Memory.TTL)},
+		{name: "sessions.memory.policy", usage: "login policy: kick-old, reject-new or concurrent", value: newStringValue(&config.Sessions.Memory.Policy)},
+		{name: "sessions.memory.maxSessions", usage: "sessions per account with the concurrent policy", value: (*intValue)(&config.Sessions.Memory.MaxSessions)},
+		{name: "sessions.memory.tombstoneTTL", usage: "how long ended sessions are remembered, zero disables it", value: (*durationValue)(&config.Sessions.Memory.TombstoneTTL)},
+		{name: "sessions.token.ttl", usage: "lifetime of a session token", value: (*durationValue)(&config.Sessions.Token.TTL)},
+		{name: "sessions.token.keys", usage: "comma separated id:secret signing keys, the first one signs", value: (*keysValue)(&config.Sessions.Token.Keys), secret: true},
+		{name: "sessions.token.redis.address", usage: "Redis server address sharing the revocations and session data, empty keeps them per instance", value: newStringValue(&config.Sessions.Token.Client.Address)},
+		{name: "sessions.token.redis.password", usage: "Redis password", value: newStringValue(&config.Sessions.Token.Client.Password), secret: true},
+		{name: "sessions.token.redis.db", usage: "Redis database", value: (*intValue)(&config.Sessions.Token.Client.DB)},
+		{name: "sessions.token.redis.keyPrefix", usage: "prefix of the Redis keys", value: newStringValue(&config.Sessions.Token.KeyPrefix)},
+		{name: "sessions.redis.address", usage: "Redis server address", value: newStringValue(&config.Sessions.Redis.Client.Address)},
+		{name: "sessions.redis.password", usage: "Redis password", value: newStringValue(&config.Sessions.Redis.Client.Password), secret: true},
+		{name: "sessions.redis.db", usage: "Redis database", value: (*intValue)(&config.Sessions.Redis.Client.DB)},
+		{name: "sessions.redis.poolSize", usage: "idle Redis connections kept", value: (*intValue)(&config.Sessions.Redis.Client.PoolSize)},
+		{name: "sessions.redis.timeout", usage: "Redis dial and command timeout", value: (*durationValue)(&config.Sessions.Redis.Client.Timeout)},
+		{name: "sessions.redis.keyPrefix", usage: "prefix of the Redis keys", value: newStringValue(&config.Sessions.Redis.KeyPrefix)},
+		{name: "sessions.redis.ttl", usage: "lifetime of an idle session", value: (*durationValue)(&config.Sessions.Redis.TTL)},
+		{name: "sessions.redis.tombstoneTTL", usage: "how long ended sessions are remembered, zero disables it", value: (*durationValue)(&config.Sessions.Redis.TombstoneTTL)},
+		{name: "sessions.redis.sweepInterval", usage: "how often expired sessions are looked for to report them, zero disables it", value: (*durationValue)(&config.Sessions.Redis.SweepInterval)},
+
+		{name: "configProvider.filePath", usage: "game config file", value: newStringValue(&config.ConfigProvider.FilePath), path: true},
+		{name: "configProvider.reloadInterval", usage: "how often the game config is reloaded, zero disables it", value: (*durationValue)(&config.ConfigProvider.ReloadInterval)},
+
+		{name: "commands.maxTimeDifferenceSeconds", usage: "tolerance of the command timestamps", value: (*floatValue)(&config.Commands.MaxTimeDifferenceSeconds)},
+		{name: "commands.maxConflictRetries", usage: "retries of a command losing a concurrent write", value: (*intValue)(&config.Commands.MaxConflictRetries)},
+		{name: "commands.maxBatchSize", usage: "commands accepted in a batch", value: (*intValue)(&config.Commands.MaxBatchSize)},
+		{name: "commands.abandonTimeout", usage: "time given to resolve a level abandoned by an ended session, zero disables it", value: (*durationValue)(&config.Commands.AbandonTimeout)},
+
+		{name: "players.storage", usage: "players storage: memory, file or sql", value: newStringValue(&config.Players.Storage)},
+		{name: "players.file.filePath", usage: "players log file", value: newStringValue(&config.Players.File.FilePath), path: true},
+		{name: "players.file.compactionRatio", usage: "records per account triggering a compaction, zero disables it", value: (*intValue)(&config.Players.File.CompactionRatio)},
+		{name: "players.sql.driver", usage: "database/sql driver, only sqlite is supported", value: newStringValue(&config.Players.SQL.Driver)},
+		{name: "players.sql.dsn", usage: "database/sql data source name", value: newStringValue(&config.Players.SQL.DSN), secret: true},
+		{name: "players.sql.maxOpenConns", usage: "open database connections", value: (*intValue)(&config.Players.SQL.MaxOpenConns)},
+
+		{name: "push.keepAliveInterval", usage: "keep-alive interval of the push streams, zero disables them", value: (*durationValue)(&config.Push.KeepAliveInterval)},
+	}
+}
+
+// LoadConfig returns DefaultConfig overridden by the config file, then by the
+// environment variables, then by the flags. The settings are registered on
+// flags before parsing args, along with -config for the config file path.
+func LoadConfig(flags *flag.FlagSet, args []string, lookupEnv func(string) (string, bool)) (Config, error) {
+	config := DefaultConfig()
+	settings := settingsOf(&config)
+
+	configPath := flags.String("config", "", "config file, also read from "+ConfigFileEnv)
+
+	// Flags are only recorded while parsing, to be applied last.
+	flagValues := make(map[string]string)
+	for _, s := range settings {
+		name := s.name
+		usage := fmt.Sprintf("%s (%s)", s.usage, s.env())
+		if value := s.value.String(); value != "" && !s.secret {
+			usage = fmt.Sprintf("%s (%s, default %s)", s.usage, s.env(), value)
+		}
+		flags.Func(name, usage, func(value string) error {
+			flagValues[name] = value
+			return nil
+		})
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if *configPath == "" {
+		*configPath, _ = lookupEnv(ConfigFileEnv)
+	}
+
+	if *configPath != "" {
+		if err := applyConfigFile(settings, *configPath); err != nil {
+			return Config{}, err
+		}
+	}
+
+	for _, s := range settings {
+		value, found := lookupEnv(s.env())
+		if !found {
+			continue
+		}
+		if err := s.value.Set(value); err != nil {
+			return Config{}, fmt.Errorf("environment variable %s: %w", s.env(), err)
+		}
+	}
+
+	for _, s := range settings {
+		value, found := flagValues[s.name]
+		if !found {
+			continue
+		}
+		if err := s.value.Set(value); err != nil {
+			return Config{}, fmt.Errorf("flag -%s: %w", s.name, err)
+		}
+	}
+
+	return config, nil
+}
+
+// applyConfigFile reads a JSON file of nested objects, e.g.
+// {"sessions": {"memory": {"ttl": "10s"}}} for sessions.memory.ttl.
+func applyConfigFile(settings []setting, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(ErrInvalidConfigFile, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document map[string]interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return errors.Wrap(ErrInvalidConfigFile, fmt.Errorf("%s: %w", path, err))
+	}
+
+	values := make(map[string]string)
+	if err := flatten("", document, values); err != nil {
+		return errors.Wrap(ErrInvalidConfigFile, fmt.Errorf("%s: %w", path, err))
+	}
+
+	settingsByName := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		settingsByName[s.name] = s
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s, found := settingsByName[name]
+		if !found {
+			return errors.Wrap(ErrInvalidConfigFile, fmt.Errorf("%s: %w %q", path, ErrUnknownSetting, name))
+		}
+
+		value := values[name]
+		if s.path && value != "" && !filepath.IsAbs(value) {
+			value = filepath.Join(filepath.Dir(path), value)
+		}
+
+		if err := s.value.Set(value); err != nil {
+			return errors.Wrap(ErrInvalidConfigFile, fmt.Errorf("%s: %s: %w", path, name, err))
+		}
+	}
+
+	return nil
+}
+
+func flatten(prefix string, document map[string]interface{}, values map[string]string) error {
+	for key, value := range document {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if err := flatten(name, value, values); err != nil {
+				return err
+			}
+		case string:
+			values[name] = value
+		case json.Number:
+			values[name] = value.String()
+		case bool:
+			values[name] = strconv.FormatBool(value)
+		default:
+			return fmt.Errorf("%s: unsupported value %v", name, value)
+		}
+	}
+
+	return nil
+}
+
+// PrintConfig writes the config in the format of the config file, with the
+// secrets redacted.
+func PrintConfig(w io.Writer, config Config) error {
+	document := make(map[string]interface{})
+	for _, s := range settingsOf(&config) {
+		value := s.value.Get()
+		if s.secret && s.value.String() != "" {
+			value = redacted
+		}
+
+		parts := strings.Split(s.name, ".")
+		parent := document
+		for _, part := range parts[:len(parts)-1] {
+			child, found := parent[part].(map[string]interface{})
+			if !found {
+				child = make(map[string]interface{})
+				parent[part] = child
+			}
+			parent = child
+		}
+		parent[parts[len(parts)-1]] = value
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// stringValue also sets the string types of the config, like PlayersStorage.
+type stringValue[T ~string] struct {
+	value *T
+}
+
+func newStringValue[T ~string](value *T) *stringValue[T] {
+	return &stringValue[T]{value: value}
+}
+
+func (v *stringValue[T]) Set(value string) error {
+	*v.value = T(value)
+	return nil
+}
+
+func (v *stringValue[T]) String() string { return string(*v.value) }
+func (v *stringValue[T]) Get() any       { return string(*v.value) }
+
+type intValue int
+
+func (v *intValue) Set(value string) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid integer %q", value)
+	}
+
+	*v = intValue(parsed)
+	return nil
+}
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+func (v *intValue) Get() any       { return int(*v) }
+
+type floatValue float64
+
+func (v *floatValue) Set(value string) error {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", value)
+	}
+
+	*v = floatValue(parsed)
+	return nil
+}
+
+func (v *floatValue) String() string { return strconv.FormatFloat(float64(*v), 'g', -1, 64) }
+func (v *floatValue) Get() any       { return float64(*v) }
+
+// durationValue is written like "10s" or "1m30s".
+type durationValue time.Duration
+
+func (v *durationValue) Set(value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, expected a value like 10s or 1m30s", value)
+	}
+
+	*v = durationValue(parsed)
+	return nil
+}
+
+func (v *durationValue) String() string { return time.Duration(*v).String() }
+func (v *durationValue) Get() any       { return time.Duration(*v).String() }
+
+// keysValue is written as comma separated id:secret pairs.
+type keysValue []token.Key
+
+func (v *keysValue) Set(value string) error {
+	var keys []token.Key
+	for _, pair := range strings.Split(value, ",") {
+		if pair == "" {
+			continue
+		}
+
+		id, secret, found := strings.Cut(pair, ":")
+		if !found {
+			return fmt.Errorf("invalid key %q, expected id:secret", pair)
+		}
+		keys = append(keys, token.Key{ID: id, Secret: []byte(secret)})
+	}
+
+	*v = keys
+	return nil
+}
+
+func (v *keysValue) String() string {
+	pairs := make([]string, 0, len(*v))
+	for _, key := range *v {
+		pairs = append(pairs, key.ID+":"+string(key.Secret))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v *keysValue) Get() any { return v.String() }
+
+type boolValue bool
+
+func (v *boolValue) Set(value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid boolean %q", value)
+	}
+
+	*v = boolValue(parsed)
+	return nil
+}
+
+func (v *boolValue) String() string { return strconv.FormatBool(bool(*v)) }
+func (v *boolValue) Get() any       { return bool(*v) }
+
+// listValue is written as comma separated values.
+type listValue []string
+
+func (v *listValue) Set(value string) error {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+
+	*v = values
+	return nil
+}
+
+func (v *listValue) String() string { return strings.Join(*v, ",") }
+func (v *listValue) Get() any       { return v.String() }
+
+type levelValue slog.Level
+
+func (v *levelValue) Set(value string) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return fmt.Errorf("invalid level %q", value)
+	}
+
+	*v = levelValue(level)
+	return nil
+}
+
+func (v *levelValue) String() string { return strings.ToLower(slog.Level(*v).String()) }
+func (v *levelValue) Get() any       { return v.String() }
@@ -0,0 +1,252 @@
+//go:build unit
+// +build unit
+
+package app
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/sessions/token"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadTestConfig(t *testing.T, args []string, env map[string]string) (Config, error) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	return LoadConfig(flags, args, func(name string) (string, bool) {
+		value, found := env[name]
+		return value, found
+	})
+}
+
+func writeTestConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "server.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig_WithoutOverrides_ShouldReturnDefaults(t *testing.T) {
+	config, err := loadTestConfig(t, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), config)
+}
+
+func TestLoadConfig_ShouldApplyFileThenEnvThenFlags(t *testing.T) {
+	path := writeTestConfigFile(t, `{
+		"port": 8000,
+		"grpcPort": 9000,
+		"sessions": {"memory": {"ttl": "20s"}},
+		"commands": {"maxTimeDifferenceSeconds": 2.5}
+	}`)
+
+	config, err := loadTestConfig(t, []string{"-config", path, "-grpcPort", "9002"}, map[string]string{
+		"SERVER_GRPC_PORT":           "9001",
+		"SERVER_SESSIONS_MEMORY_TTL": "30s",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 8000, config.Port)
+	assert.Equal(t, 9002, config.GRPCPort)
+	assert.Equal(t, 30*time.Second, config.Sessions.Memory.TTL)
+	assert.Equal(t, 2.5, config.Commands.MaxTimeDifferenceSeconds)
+	assert.Equal(t, DefaultConfig().Commands.MaxBatchSize, config.Commands.MaxBatchSize)
+}
+
+func TestLoadConfig_WithConfigFileEnv_ShouldResolvePathsFromFile(t *testing.T) {
+	path := writeTestConfigFile(t, `{
+		"configProvider": {"filePath": "game_config.json"},
+		"players": {"file": {"filePath": "/var/lib/players.log"}}
+	}`)
+
+	config, err := loadTestConfig(t, nil, map[string]string{ConfigFileEnv: path})
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "game_config.json"), config.ConfigProvider.FilePath)
+	assert.Equal(t, "/var/lib/players.log", config.Players.File.FilePath)
+}
+
+func TestLoadConfig_WithTokenKeys_ShouldParsePairs(t *testing.T) {
+	config, err := loadTestConfig(t, nil, map[string]string{
+		"SERVER_SESSIONS_TOKEN_KEYS": "new:0123456789abcdef0123456789abcdef,old:fedcba9876543210fedcba9876543210",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []token.Key{
+		{ID: "new", Secret: []byte("0123456789abcdef0123456789abcdef")},
+		{ID: "old", Secret: []byte("fedcba9876543210fedcba9876543210")},
+	}, config.Sessions.Token.Keys)
+}
+
+func TestLoadConfig_WithLogSettings_ShouldParseLevelAndRedactList(t *testing.T) {
+	config, err := loadTestConfig(t, []string{"-log.level", "debug"}, map[string]string{
+		"SERVER_LOG_REQUESTS_BODIES": "true",
+		"SERVER_LOG_REQUESTS_REDACT": "Authorization, token",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, config.Log.Level)
+	assert.True(t, config.Log.Requests.Bodies)
+	assert.Equal(t, []string{"Authorization", "token"}, config.Log.Requests.Redact)
+}
+
+func TestLoadConfig_WithInvalidSources_ShouldFail(t *testing.T) {
+	table := map[string]struct {
+		file string
+		args []string
+		env  map[string]string
+	}{
+		"unknown file setting":  {file: `{"sessions": {"ttl": "10s"}}`},
+		"invalid file value":    {file: `{"port": "http"}`},
+		"unsupported file type": {file: `{"port": [8080]}`},
+		"malformed file":        {file: `{"port": `},
+		"invalid env value":     {env: map[string]string{"SERVER_SESSIONS_MEMORY_TTL": "10"}},
+		"invalid flag value":    {args: []string{"-port", "http"}},
+		"invalid log level":     {env: map[string]string{"SERVER_LOG_LEVEL": "verbose"}},
+		"unknown flag":          {args: []string{"-unknown", "1"}},
+		"missing file":          {args: []string{"-config", "missing.json"}},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			args := row.args
+			if row.file != "" {
+				args = append(args, "-config", writeTestConfigFile(t, row.file))
+			}
+
+			_, err := loadTestConfig(t, args, row.env)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSettingEnv(t *testing.T) {
+	table := map[string]string{
+		"port":                              "SERVER_PORT",
+		"grpcPort":                          "SERVER_GRPC_PORT",
+		"sessions.memory.tombstoneTTL":      "SERVER_SESSIONS_MEMORY_TOMBSTONE_TTL",
+		"configProvider.filePath":           "SERVER_CONFIG_PROVIDER_FILE_PATH",
+		"commands.maxTimeDifferenceSeconds": "SERVER_COMMANDS_MAX_TIME_DIFFERENCE_SECONDS",
+	}
+
+	for name, env := range table {
+		assert.Equal(t, env, setting{name: name}.env())
+	}
+}
+
+func TestPrintConfig_ShouldRedactSecretsAndLoadBack(t *testing.T) {
+	config := DefaultConfig()
+	config.Sessions.Redis.Client.Password = "hunter2"
+	config.Players.SQL.DSN = "user:hunter2@/players"
+
+	var output bytes.Buffer
+	require.NoError(t, PrintConfig(&output, config))
+
+	assert.NotContains(t, output.String(), "hunter2")
+	assert.Contains(t, output.String(), redacted)
+
+	config.Sessions.Redis.Client.Password = ""
+	config.Players.SQL.DSN = ""
+	printed := bytes.ReplaceAll(output.Bytes(), []byte(`"`+redacted+`"`), []byte(`""`))
+	path := writeTestConfigFile(t, string(printed))
+
+	loaded, err := loadTestConfig(t, []string{"-config", path}, nil)
+	require.NoError(t, err)
+
+	config.ConfigProvider.FilePath = filepath.Join(filepath.Dir(path), config.ConfigProvider.FilePath)
+	assert.Equal(t, config, loaded)
+}
+
+func TestValidate(t *testing.T) {
+	gameConfigPath := writeTestConfigFile(t, `{}`)
+
+	table := map[string]struct {
+		update       func(config *Config)
+		expectedPath string
+	}{
+		"valid": {
+			update: func(config *Config) {},
+		},
+		"invalid port": {
+			update:       func(config *Config) { config.Port = 0 },
+			expectedPath: "port",
+		},
+		"same ports": {
+			update:       func(config *Config) { config.GRPCPort = config.Port },
+			expectedPath: "grpcPort",
+		},
+		"unknown sessions storage": {
+			update:       func(config *Config) { config.Sessions.Storage = "disk" },
+			expectedPath: "sessions.storage",
+		},
+		"unknown policy": {
+			update:       func(config *Config) { config.Sessions.Memory.Policy = "kick-new" },
+			expectedPath: "sessions.memory.policy",
+		},
+		"token storage without keys": {
+			update:       func(config *Config) { config.Sessions.Storage = SessionsStorageToken },
+			expectedPath: "sessions.token.keys",
+		},
+		"redis storage without address": {
+			update:       func(config *Config) { config.Sessions.Storage = SessionsStorageRedis },
+			expectedPath: "sessions.redis.address",
+		},
+		"missing game config": {
+			update:       func(config *Config) { config.ConfigProvider.FilePath = "missing.json" },
+			expectedPath: "configProvider.filePath",
+		},
+		"empty batches": {
+			update:       func(config *Config) { config.Commands.MaxBatchSize = 0 },
+			expectedPath: "commands.maxBatchSize",
+		},
+		"sql storage without dsn": {
+			update:       func(config *Config) { config.Players.Storage = PlayersStorageSQL },
+			expectedPath: "players.sql.dsn",
+		},
+		"unsupported sql driver": {
+			update: func(config *Config) {
+				config.Players.Storage = PlayersStorageSQL
+				config.Players.SQL.Driver = "mysql"
+				config.Players.SQL.DSN = "players"
+			},
+			expectedPath: "players.sql.driver",
+		},
+		"unknown log format": {
+			update:       func(config *Config) { config.Log.Format = "xml" },
+			expectedPath: "log.format",
+		},
+		"keep-alive longer than sessions": {
+			update:       func(config *Config) { config.Push.KeepAliveInterval = config.Sessions.Memory.TTL },
+			expectedPath: "push.keepAliveInterval",
+		},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.ConfigProvider.FilePath = gameConfigPath
+			row.update(&config)
+
+			err := config.Validate()
+
+			if row.expectedPath == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), row.expectedPath+": ")
+		})
+	}
+}
@@ -13,3 +13,11 @@ type TimedCommand interface {
 	Command
 	GetTimestamp() time.Time
 }
+
+// SeededCommand is implemented by commands relying on server-generated
+// randomness. The seed is set by the server before executing the command, and
+// never taken from the client.
+type SeededCommand interface {
+	Command
+	SetSeed(seed uint64)
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"technical-test-backend/internal/core"
+	"time"
+)
+
+// AbandonLevel resolves the level in progress of a session that ended before
+// the level did, following the configured AbandonedLevelPolicy. It's run by
+// the server and not registered for clients.
+type AbandonLevel struct {
+	Now time.Time
+}
+
+func (c *AbandonLevel) Execute(state *core.PlayerState, configs core.Configs) error {
+	if state.Session.CurrentLevelID == nil {
+		return ErrNoLevelInProgress
+	}
+
+	currentLevelID := *state.Session.CurrentLevelID
+	seeded := state.Session.LevelSeed != nil
+	state.Session.CurrentLevelID = nil
+	state.Session.LevelSeed = nil
+
+	// A level removed by a config update can't be resolved, it's just dropped.
+	if !isConfiguredLevel(configs, currentLevelID) {
+		return nil
+	}
+
+	// The client knows the outcome of a seeded level from its seed, refunding
+	// it would let a player drop the levels they're about to lose for free.
+	switch {
+	case configs.AbandonedLevelPolicy == core.AbandonedLevelRefund && !seeded:
+		energy := &state.Persistent.Energy
+		updateEnergy(energy, c.Now, configs.Energy)
+		// Like recharges, refunds stop at the max energy.
+		if energy.CurrentAmount < configs.Energy.MaxEnergy {
+			energy.CurrentAmount = min(energy.CurrentAmount+configs.Levels[currentLevelID].EnergyCost, configs.Energy.MaxEnergy)
+		}
+	default:
+		stats := findOrCreateLevelStats(&state.Persistent.LevelProgression, currentLevelID)
+		stats.Losses++
+		updateLevelStats(&state.Persistent.LevelProgression, stats)
+	}
+
+	return nil
+}
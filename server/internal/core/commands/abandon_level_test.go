@@ -0,0 +1,120 @@
+//go:build unit
+// +build unit
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/core"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAbandonTestState(levelID *int) *core.PlayerState {
+	seed := uint64(1)
+	return &core.PlayerState{
+		Persistent: &core.PersistentState{
+			Energy: core.Energy{CurrentAmount: 3},
+			LevelProgression: core.LevelProgression{
+				CurrentLevel: 2,
+				Statistics:   []core.LevelStats{{LevelID: 1, Wins: 1}},
+			},
+		},
+		Session: &core.SessionState{
+			CurrentLevelID: levelID,
+			LevelSeed:      &seed,
+		},
+	}
+}
+
+func TestAbandonLevel_WithLossPolicy_ShouldRecordLoss(t *testing.T) {
+	for _, policy := range []core.AbandonedLevelPolicy{"", core.AbandonedLevelLoss} {
+		playerState := newAbandonTestState(intPtr(1))
+		configs := getTestConfigs()
+		configs.AbandonedLevelPolicy = policy
+
+		err := (&AbandonLevel{}).Execute(playerState, configs)
+
+		require.NoError(t, err)
+		assert.Nil(t, playerState.Session.CurrentLevelID)
+		assert.Nil(t, playerState.Session.LevelSeed)
+		assert.Equal(t, 3, playerState.Persistent.Energy.CurrentAmount)
+		assert.Equal(t, []core.LevelStats{{LevelID: 1, Wins: 1, Losses: 1}}, playerState.Persistent.LevelProgression.Statistics)
+	}
+}
+
+func TestAbandonLevel_WithRefundPolicy_ShouldRefundEnergy(t *testing.T) {
+	now := time.Now()
+	playerState := newAbandonTestState(intPtr(2))
+	playerState.Session.LevelSeed = nil
+	playerState.Persistent.Energy.LastRechargeAt = now
+	configs := getTestConfigs()
+	configs.AbandonedLevelPolicy = core.AbandonedLevelRefund
+
+	err := (&AbandonLevel{Now: now}).Execute(playerState, configs)
+
+	require.NoError(t, err)
+	assert.Nil(t, playerState.Session.CurrentLevelID)
+	assert.Equal(t, 4, playerState.Persistent.Energy.CurrentAmount)
+	assert.Equal(t, []core.LevelStats{{LevelID: 1, Wins: 1}}, playerState.Persistent.LevelProgression.Statistics)
+}
+
+func TestAbandonLevel_WithRefundPolicy_ShouldNotExceedMaxEnergy(t *testing.T) {
+	now := time.Now()
+	configs := getTestConfigs()
+	configs.AbandonedLevelPolicy = core.AbandonedLevelRefund
+
+	table := map[string]core.Energy{
+		"full":       {CurrentAmount: 10, LastRechargeAt: now},
+		"recharged":  {CurrentAmount: 3, LastRechargeAt: now.Add(-time.Hour)},
+		"overfilled": {CurrentAmount: 12, LastRechargeAt: now},
+	}
+
+	for name, energy := range table {
+		t.Run(name, func(t *testing.T) {
+			playerState := newAbandonTestState(intPtr(2))
+			playerState.Session.LevelSeed = nil
+			playerState.Persistent.Energy = energy
+
+			err := (&AbandonLevel{Now: now}).Execute(playerState, configs)
+
+			require.NoError(t, err)
+			assert.Equal(t, max(energy.CurrentAmount, configs.Energy.MaxEnergy), playerState.Persistent.Energy.CurrentAmount)
+		})
+	}
+}
+
+func TestAbandonLevel_WithRefundPolicy_WithSeededLevel_ShouldRecordLoss(t *testing.T) {
+	playerState := newAbandonTestState(intPtr(1))
+	configs := getTestConfigs()
+	configs.AbandonedLevelPolicy = core.AbandonedLevelRefund
+
+	err := (&AbandonLevel{Now: time.Now()}).Execute(playerState, configs)
+
+	require.NoError(t, err)
+	assert.Nil(t, playerState.Session.LevelSeed)
+	assert.Equal(t, 3, playerState.Persistent.Energy.CurrentAmount)
+	assert.Equal(t, []core.LevelStats{{LevelID: 1, Wins: 1, Losses: 1}}, playerState.Persistent.LevelProgression.Statistics)
+}
+
+func TestAbandonLevel_WithRemovedLevel_ShouldOnlyClearSession(t *testing.T) {
+	playerState := newAbandonTestState(intPtr(5))
+
+	err := (&AbandonLevel{}).Execute(playerState, getTestConfigs())
+
+	require.NoError(t, err)
+	assert.Nil(t, playerState.Session.CurrentLevelID)
+	assert.Equal(t, 3, playerState.Persistent.Energy.CurrentAmount)
+	assert.Equal(t, []core.LevelStats{{LevelID: 1, Wins: 1}}, playerState.Persistent.LevelProgression.Statistics)
+}
+
+func TestAbandonLevel_WithoutLevelInProgress_ShouldFail(t *testing.T) {
+	playerState := newAbandonTestState(nil)
+
+	err := (&AbandonLevel{}).Execute(playerState, getTestConfigs())
+
+	assert.ErrorIs(t, err, ErrNoLevelInProgress)
+}
@@ -1,30 +1,48 @@
 package commands
 
 import (
-	"fmt"
 	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
 	"time"
 )
 
 type BeginLevel struct {
 	LevelID int       `json:"levelId"`
 	Now     time.Time `json:"now"`
+
+	seed *uint64
+}
+
+func (c *BeginLevel) SetSeed(seed uint64) {
+	c.seed = &seed
+}
+
+func (c *BeginLevel) Validate() error {
+	if c.LevelID <= 0 {
+		return errors.New("levelId must be positive")
+	}
+	return nil
 }
 
 func (c *BeginLevel) Execute(state *core.PlayerState, configs core.Configs) error {
+	if !isConfiguredLevel(configs, c.LevelID) {
+		return ErrInvalidLevel
+	}
+
 	if !canPlayLevel(state.Persistent.LevelProgression.CurrentLevel, c.LevelID) {
-		return fmt.Errorf("level not unlocked")
+		return ErrLevelNotUnlocked
 	}
 
 	levelConfig := configs.Levels[c.LevelID]
-	predictedEnergy := getPredictedEnergyAmount(state.Persistent.Energy, c.Now, configs.Energy)
+	predictedEnergy := state.Persistent.Energy.PredictAmount(c.Now, configs.Energy)
 	if predictedEnergy < levelConfig.EnergyCost {
-		return fmt.Errorf("not enough energy")
+		return ErrNotEnoughEnergy
 	}
 
 	updateEnergy(&state.Persistent.Energy, c.Now, configs.Energy)
 	state.Persistent.Energy.CurrentAmount -= levelConfig.EnergyCost
 	state.Session.CurrentLevelID = &c.LevelID
+	state.Session.LevelSeed = c.seed
 
 	return nil
 }
@@ -33,23 +51,14 @@ func (c *BeginLevel) GetTimestamp() time.Time {
 	return c.Now
 }
 
-func canPlayLevel(currentLevel, targetLevel int) bool {
-	return targetLevel <= currentLevel
+// isConfiguredLevel guards against levels removed by a config update, and the
+// placeholder level 0.
+func isConfiguredLevel(configs core.Configs, levelID int) bool {
+	return levelID > 0 && levelID < len(configs.Levels)
 }
 
-func getPredictedEnergyAmount(energy core.Energy, now time.Time, config core.EnergyConfig) int {
-	if energy.CurrentAmount >= config.MaxEnergy {
-		return energy.CurrentAmount
-	}
-
-	timeSinceRecharge := now.Sub(energy.LastRechargeAt)
-	rechargeIntervals := int(timeSinceRecharge / config.RechargeInterval())
-	predictedAmount := energy.CurrentAmount + rechargeIntervals
-
-	if predictedAmount > config.MaxEnergy {
-		return config.MaxEnergy
-	}
-	return predictedAmount
+func canPlayLevel(currentLevel, targetLevel int) bool {
+	return targetLevel <= currentLevel
 }
 
 func updateEnergy(energy *core.Energy, now time.Time, config core.EnergyConfig) {
@@ -41,6 +41,65 @@ func TestBeginLevel_WithEnergyAndLevelUnlocked_ShouldSucceed(t *testing.T) {
 	assert.Equal(t, 1, *playerState.Session.CurrentLevelID)
 }
 
+func TestBeginLevel_WithSeed_ShouldStoreSeedInSession(t *testing.T) {
+	playerState := &core.PlayerState{
+		Persistent: &core.PersistentState{
+			Energy: core.Energy{
+				CurrentAmount:  1,
+				LastRechargeAt: time.Now(),
+			},
+			LevelProgression: core.LevelProgression{
+				CurrentLevel: 1,
+				Statistics:   []core.LevelStats{},
+			},
+		},
+		Session: &core.SessionState{},
+	}
+
+	configs := getTestConfigs()
+
+	command := &BeginLevel{
+		LevelID: 1,
+		Now:     time.Now(),
+	}
+	command.SetSeed(42)
+
+	err := command.Execute(playerState, configs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), *playerState.Session.LevelSeed)
+}
+
+func TestBeginLevel_UnknownLevel_ShouldReturnError(t *testing.T) {
+	playerState := &core.PlayerState{
+		Persistent: &core.PersistentState{
+			Energy: core.Energy{
+				CurrentAmount:  1,
+				LastRechargeAt: time.Now(),
+			},
+			LevelProgression: core.LevelProgression{
+				CurrentLevel: 5,
+				Statistics:   []core.LevelStats{},
+			},
+		},
+		Session: &core.SessionState{},
+	}
+
+	configs := getTestConfigs()
+
+	for _, levelID := range []int{0, len(configs.Levels)} {
+		command := &BeginLevel{
+			LevelID: levelID,
+			Now:     time.Now(),
+		}
+
+		err := command.Execute(playerState, configs)
+
+		assert.ErrorIs(t, err, ErrInvalidLevel)
+		assert.Nil(t, playerState.Session.CurrentLevelID)
+	}
+}
+
 func TestBeginLevel_WithoutEnergy_ShouldReturnError(t *testing.T) {
 	playerState := &core.PlayerState{
 		Persistent: &core.PersistentState{
@@ -66,6 +125,7 @@ func TestBeginLevel_WithoutEnergy_ShouldReturnError(t *testing.T) {
 	err := command.Execute(playerState, configs)
 
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotEnoughEnergy)
 	assert.Equal(t, "not enough energy", err.Error())
 }
 
@@ -93,6 +153,7 @@ func TestBeginLevel_LevelNotUnlocked_ShouldReturnError(t *testing.T) {
 	err := command.Execute(playerState, configs)
 
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrLevelNotUnlocked)
 	assert.Equal(t, "level not unlocked", err.Error())
 }
 
@@ -1,23 +1,48 @@
 package commands
 
 import (
-	"fmt"
 	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/core/dice"
+	"technical-test-backend/internal/errors"
 )
 
 type EndLevel struct {
 	Success bool `json:"success"`
 	Score   int  `json:"score"`
+	// Rolls is the number of times the dice was rolled, used to replay a
+	// seeded level and check the reported result. Clients that don't roll
+	// the dice from the seed yet leave it out, and their result is trusted.
+	Rolls *int `json:"rolls,omitempty"`
+}
+
+func (c *EndLevel) Validate() error {
+	if c.Score < 0 {
+		return errors.New("score must not be negative")
+	}
+	if c.Rolls != nil && *c.Rolls < 0 {
+		return errors.New("rolls must not be negative")
+	}
+	return nil
 }
 
 func (c *EndLevel) Execute(state *core.PlayerState, configs core.Configs) error {
 	if state.Session.CurrentLevelID == nil {
-		return fmt.Errorf("no level in progress")
+		return ErrNoLevelInProgress
 	}
 
 	currentLevelID := *state.Session.CurrentLevelID
+	if !isConfiguredLevel(configs, currentLevelID) {
+		return ErrInvalidLevel
+	}
+
 	levelConfig := configs.Levels[currentLevelID]
 
+	if state.Session.LevelSeed != nil && c.Rolls != nil {
+		if err := c.verifyResult(*state.Session.LevelSeed, levelConfig); err != nil {
+			return err
+		}
+	}
+
 	stats := findOrCreateLevelStats(&state.Persistent.LevelProgression, currentLevelID)
 
 	if c.Success && c.Score > stats.BestScore {
@@ -39,6 +64,7 @@ func (c *EndLevel) Execute(state *core.PlayerState, configs core.Configs) error
 	}
 
 	state.Session.CurrentLevelID = nil
+	state.Session.LevelSeed = nil
 
 	return nil
 }
@@ -68,3 +94,23 @@ func updateLevelStats(progression *core.LevelProgression, stats core.LevelStats)
 		}
 	}
 }
+
+// verifyResult replays the level from its seed: the player rolls until getting
+// the target number, scoring the remaining rolls, or until running out of
+// rolls. Stopping earlier is a loss.
+func (c *EndLevel) verifyResult(seed uint64, levelConfig core.LevelConfig) error {
+	rolls, success := dice.Play(seed, levelConfig.MaxRolls, levelConfig.TargetNumber)
+	if *c.Rolls > rolls {
+		return ErrInvalidRolls
+	}
+
+	success = success && *c.Rolls == rolls
+	if c.Success != success {
+		return ErrResultMismatch
+	}
+	if success && c.Score != levelConfig.MaxRolls-rolls {
+		return ErrResultMismatch
+	}
+
+	return nil
+}
@@ -7,8 +7,10 @@
 	"testing"
 
 	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/core/dice"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEndLevel_WithLevelInProgress_ShouldSucceed(t *testing.T) {
@@ -277,9 +279,33 @@ func TestEndLevel_NoLevelInProgress_ShouldReturnError(t *testing.T) {
 	err := command.Execute(playerState, configs)
 
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrNoLevelInProgress)
 	assert.Equal(t, "no level in progress", err.Error())
 }
 
+func TestEndLevel_RemovedLevelInProgress_ShouldReturnError(t *testing.T) {
+	playerState := &core.PlayerState{
+		Persistent: &core.PersistentState{
+			LevelProgression: core.LevelProgression{
+				Statistics: []core.LevelStats{},
+			},
+		},
+		Session: &core.SessionState{
+			CurrentLevelID: intPtr(10),
+		},
+	}
+
+	configs := getTestConfigs()
+
+	command := &EndLevel{
+		Success: false,
+	}
+
+	err := command.Execute(playerState, configs)
+
+	assert.ErrorIs(t, err, ErrInvalidLevel)
+}
+
 func TestEndLevel_WithMultipleLevelStats_ShouldUpdateCorrectLevel(t *testing.T) {
 	playerState := &core.PlayerState{
 		Persistent: &core.PersistentState{
@@ -348,3 +374,91 @@ func TestEndLevel_WithLevelInProgressAndSuccess_ShouldNotExceedMaxLevel(t *testi
 	assert.Equal(t, 2, playerState.Persistent.LevelProgression.CurrentLevel)
 	assert.Nil(t, playerState.Session.CurrentLevelID)
 }
+
+func newSeededPlayerState(seed uint64) *core.PlayerState {
+	return &core.PlayerState{
+		Persistent: &core.PersistentState{
+			LevelProgression: core.LevelProgression{
+				CurrentLevel: 1,
+				Statistics:   []core.LevelStats{},
+			},
+		},
+		Session: &core.SessionState{
+			CurrentLevelID: intPtr(1),
+			LevelSeed:      &seed,
+		},
+	}
+}
+
+func TestEndLevel_WithSeed_ShouldVerifyResult(t *testing.T) {
+	const seed = 1234567
+	configs := getTestConfigs()
+	levelConfig := configs.Levels[1]
+	rolls, success := dice.Play(seed, levelConfig.MaxRolls, levelConfig.TargetNumber)
+	require.True(t, success)
+	require.Greater(t, rolls, 1)
+
+	t.Run("matching win", func(t *testing.T) {
+		playerState := newSeededPlayerState(seed)
+		command := &EndLevel{Success: true, Score: levelConfig.MaxRolls - rolls, Rolls: intPtr(rolls)}
+
+		err := command.Execute(playerState, configs)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, playerState.Persistent.LevelProgression.Statistics[0].Wins)
+		assert.Equal(t, levelConfig.MaxRolls-rolls, playerState.Persistent.LevelProgression.Statistics[0].BestScore)
+		assert.Nil(t, playerState.Session.LevelSeed)
+	})
+
+	t.Run("stopped before hitting the target", func(t *testing.T) {
+		playerState := newSeededPlayerState(seed)
+		command := &EndLevel{Success: false, Rolls: intPtr(rolls - 1)}
+
+		err := command.Execute(playerState, configs)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, playerState.Persistent.LevelProgression.Statistics[0].Losses)
+	})
+
+	t.Run("claimed win without hitting the target", func(t *testing.T) {
+		playerState := newSeededPlayerState(seed)
+		command := &EndLevel{Success: true, Score: levelConfig.MaxRolls - rolls + 1, Rolls: intPtr(rolls - 1)}
+
+		err := command.Execute(playerState, configs)
+
+		assert.ErrorIs(t, err, ErrResultMismatch)
+		assert.NotNil(t, playerState.Session.CurrentLevelID)
+	})
+
+	t.Run("inflated score", func(t *testing.T) {
+		playerState := newSeededPlayerState(seed)
+		command := &EndLevel{Success: true, Score: levelConfig.MaxRolls, Rolls: intPtr(rolls)}
+
+		err := command.Execute(playerState, configs)
+
+		assert.ErrorIs(t, err, ErrResultMismatch)
+	})
+
+	t.Run("rolls after hitting the target", func(t *testing.T) {
+		playerState := newSeededPlayerState(seed)
+		command := &EndLevel{Success: false, Rolls: intPtr(rolls + 1)}
+
+		err := command.Execute(playerState, configs)
+
+		assert.ErrorIs(t, err, ErrInvalidRolls)
+	})
+}
+
+func TestEndLevel_WithSeedAndNoRolls_ShouldTrustResult(t *testing.T) {
+	const seed = 1234567
+	configs := getTestConfigs()
+	playerState := newSeededPlayerState(seed)
+	command := &EndLevel{Success: true, Score: 3}
+
+	err := command.Execute(playerState, configs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, playerState.Persistent.LevelProgression.Statistics[0].Wins)
+	assert.Equal(t, 3, playerState.Persistent.LevelProgression.Statistics[0].BestScore)
+	assert.Nil(t, playerState.Session.LevelSeed)
+}
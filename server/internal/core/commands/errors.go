@@ -0,0 +1,22 @@
+package commands
+
+import "technical-test-backend/internal/errors"
+
+const (
+	ErrorCodeLevelNotUnlocked  = "LEVEL_NOT_UNLOCKED"
+	ErrorCodeNotEnoughEnergy   = "NOT_ENOUGH_ENERGY"
+	ErrorCodeNoLevelInProgress = "NO_LEVEL_IN_PROGRESS"
+	ErrorCodeInvalidRolls      = "INVALID_ROLLS"
+	ErrorCodeInvalidLevel      = "INVALID_LEVEL"
+	ErrorCodeResultMismatch    = "RESULT_MISMATCH"
+)
+
+// Command rejections caused by the player state or game rules.
+var (
+	ErrLevelNotUnlocked  = errors.NewCoded(ErrorCodeLevelNotUnlocked, "level not unlocked")
+	ErrNotEnoughEnergy   = errors.NewCoded(ErrorCodeNotEnoughEnergy, "not enough energy")
+	ErrNoLevelInProgress = errors.NewCoded(ErrorCodeNoLevelInProgress, "no level in progress")
+	ErrInvalidRolls      = errors.NewCoded(ErrorCodeInvalidRolls, "rolls don't match the level")
+	ErrResultMismatch    = errors.NewCoded(ErrorCodeResultMismatch, "level result doesn't match the rolls")
+	ErrInvalidLevel      = errors.NewCoded(ErrorCodeInvalidLevel, "level doesn't exist")
+)
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+)
+
+var (
+	ErrUnknownCommand           = errors.New("unknown command")
+	ErrInvalidCommandData       = errors.New("invalid command data")
+	ErrCommandAlreadyRegistered = errors.New("command already registered")
+	ErrInvalidDefinition        = errors.New("invalid command definition")
+)
+
+// Validator is implemented by commands that check their own payload once
+// decoded, before being executed.
+type Validator interface {
+	Validate() error
+}
+
+// Definition describes a command that can be decoded by name.
+type Definition struct {
+	Name string
+	// New returns an empty command, ready to decode its payload into.
+	New func() core.Command
+	// Schema describes the payload. It is derived from the command type when
+	// left empty.
+	Schema *Schema
+}
+
+// Registry maps command names to their definitions, so transports can decode
+// commands without knowing about every concrete type.
+type Registry struct {
+	mutex       sync.RWMutex
+	definitions map[string]Definition
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		definitions: make(map[string]Definition),
+	}
+}
+
+// NewDefaultRegistry returns a registry holding all the game commands.
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	registry.mustRegister(Definition{Name: "BeginLevel", New: func() core.Command { return &BeginLevel{} }})
+	registry.mustRegister(Definition{Name: "EndLevel", New: func() core.Command { return &EndLevel{} }})
+	return registry
+}
+
+func (r *Registry) Register(definition Definition) error {
+	if definition.Name == "" || definition.New == nil {
+		return ErrInvalidDefinition
+	}
+
+	if definition.Schema == nil {
+		schema := SchemaOf(definition.New())
+		definition.Schema = &schema
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.definitions[definition.Name]; exists {
+		return errors.Wrapf(ErrCommandAlreadyRegistered, "command %s", definition.Name)
+	}
+
+	r.definitions[definition.Name] = definition
+	return nil
+}
+
+func (r *Registry) mustRegister(definition Definition) {
+	if err := r.Register(definition); err != nil {
+		panic(err)
+	}
+}
+
+// Parse decodes the payload of the named command, validating it if the command
+// implements Validator.
+func (r *Registry) Parse(name string, data json.RawMessage) (core.Command, error) {
+	r.mutex.RLock()
+	definition, exists := r.definitions[name]
+	r.mutex.RUnlock()
+
+	if !exists {
+		return nil, ErrUnknownCommand
+	}
+
+	command := definition.New()
+	if err := json.Unmarshal(data, command); err != nil {
+		return nil, errors.Wrap(ErrInvalidCommandData, err)
+	}
+
+	if validator, ok := command.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, errors.Wrap(ErrInvalidCommandData, err)
+		}
+	}
+
+	return command, nil
+}
+
+// Definitions returns the registered definitions sorted by name.
+func (r *Registry) Definitions() []Definition {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	definitions := make([]Definition, 0, len(r.definitions))
+	for _, definition := range r.definitions {
+		definitions = append(definitions, definition)
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+
+	return definitions
+}
@@ -0,0 +1,97 @@
+//go:build unit
+// +build unit
+
+package commands
+
+import (
+	"encoding/json"
+	"testing"
+
+	"technical-test-backend/internal/core"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Parse_ShouldDecodeRegisteredCommand(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	command, err := registry.Parse("EndLevel", json.RawMessage(`{"success": true, "score": 8}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, &EndLevel{Success: true, Score: 8}, command)
+}
+
+func TestRegistry_Parse_UnknownCommand_ShouldFail(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	_, err := registry.Parse("Unknown", json.RawMessage(`{}`))
+
+	assert.ErrorIs(t, err, ErrUnknownCommand)
+}
+
+func TestRegistry_Parse_InvalidData_ShouldFail(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	t.Run("malformed payload", func(t *testing.T) {
+		_, err := registry.Parse("BeginLevel", json.RawMessage(`{"levelId": "one"}`))
+
+		assert.ErrorIs(t, err, ErrInvalidCommandData)
+	})
+
+	t.Run("failed validation", func(t *testing.T) {
+		_, err := registry.Parse("BeginLevel", json.RawMessage(`{"levelId": -1}`))
+
+		assert.ErrorIs(t, err, ErrInvalidCommandData)
+	})
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry()
+
+	t.Run("successful registration", func(t *testing.T) {
+		err := registry.Register(Definition{Name: "EndLevel", New: func() core.Command { return &EndLevel{} }})
+
+		require.NoError(t, err)
+		definitions := registry.Definitions()
+		require.Len(t, definitions, 1)
+		assert.Equal(t, "EndLevel", definitions[0].Name)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := registry.Register(Definition{Name: "EndLevel", New: func() core.Command { return &EndLevel{} }})
+
+		assert.ErrorIs(t, err, ErrCommandAlreadyRegistered)
+	})
+
+	t.Run("missing factory", func(t *testing.T) {
+		err := registry.Register(Definition{Name: "BeginLevel"})
+
+		assert.ErrorIs(t, err, ErrInvalidDefinition)
+	})
+}
+
+func TestRegistry_Definitions_ShouldDescribePayloads(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	definitions := registry.Definitions()
+
+	require.Len(t, definitions, 2)
+	assert.Equal(t, "BeginLevel", definitions[0].Name)
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"levelId": {Type: "integer"},
+			"now":     {Type: "string", Format: "date-time"},
+		},
+	}, definitions[0].Schema)
+	assert.Equal(t, "EndLevel", definitions[1].Name)
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success": {Type: "boolean"},
+			"score":   {Type: "integer"},
+			"rolls":   {Type: "integer"},
+		},
+	}, definitions[1].Schema)
+}
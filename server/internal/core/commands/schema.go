@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema needed to describe command payloads.
+type Schema struct {
+	Type       string             `json:"type"`
+	Format     string             `json:"format,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaOf derives the schema of a value from its type, following the same
+// json tags encoding/json does.
+func SchemaOf(v any) Schema {
+	return schemaOf(reflect.TypeOf(v))
+}
+
+func schemaOf(t reflect.Type) Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{Type: "number"}
+	case reflect.String:
+		return Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		items := schemaOf(t.Elem())
+		return Schema{Type: "array", Items: &items}
+	case reflect.Struct:
+		schema := Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := field.Name
+			if tag, ok := field.Tag.Lookup("json"); ok {
+				tagName, _, _ := strings.Cut(tag, ",")
+				if tagName == "-" {
+					continue
+				}
+				if tagName != "" {
+					name = tagName
+				}
+			}
+
+			fieldSchema := schemaOf(field.Type)
+			schema.Properties[name] = &fieldSchema
+		}
+		return schema
+	default:
+		return Schema{Type: "object"}
+	}
+}
@@ -1,12 +1,31 @@
 package core
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"technical-test-backend/internal/core/dice"
+	"time"
+)
 
 type Configs struct {
-	Levels []LevelConfig `json:"levels"`
-	Energy EnergyConfig  `json:"energy"`
+	Levels     []LevelConfig    `json:"levels"`
+	Energy     EnergyConfig     `json:"energy"`
+	Onboarding OnboardingConfig `json:"onboarding"`
+	// AbandonedLevelPolicy resolves the level in progress of a session that
+	// ended before the level did. It defaults to AbandonedLevelLoss.
+	AbandonedLevelPolicy AbandonedLevelPolicy `json:"abandonedLevelPolicy,omitempty"`
 }
 
+type AbandonedLevelPolicy string
+
+const (
+	// AbandonedLevelLoss records a loss for the level, keeping the energy spent.
+	AbandonedLevelLoss AbandonedLevelPolicy = "loss"
+	// AbandonedLevelRefund gives back the energy spent, as if the level was
+	// never begun. Seeded levels still record a loss.
+	AbandonedLevelRefund AbandonedLevelPolicy = "refund"
+)
+
 type EnergyConfig struct {
 	MaxEnergy               int `json:"maxEnergy"`
 	RechargeIntervalSeconds int `json:"rechargeIntervalSeconds"`
@@ -22,3 +41,120 @@ type LevelConfig struct {
 	TargetNumber int `json:"targetNumber"`
 	EnergyReward int `json:"energyReward"`
 }
+
+// ConfigViolation is a single invalid value, located by its JSON path.
+type ConfigViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ConfigViolations lists every invalid value found in a Configs.
+type ConfigViolations []ConfigViolation
+
+func (v ConfigViolations) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, violation := range v {
+		messages = append(messages, violation.Path+": "+violation.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validate checks the configs can be played with, returning ConfigViolations
+// if they can't.
+func (c *Configs) Validate() error {
+	var violations ConfigViolations
+	report := func(path, format string, args ...any) {
+		violations = append(violations, ConfigViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if c.Energy.MaxEnergy <= 0 {
+		report("$.energy.maxEnergy", "must be positive")
+	}
+	if c.Energy.RechargeIntervalSeconds <= 0 {
+		report("$.energy.rechargeIntervalSeconds", "must be positive")
+	}
+
+	if len(c.Levels) < 2 {
+		report("$.levels", "must hold at least one level after the placeholder")
+	}
+	if len(c.Levels) > 0 && c.Levels[0] != (LevelConfig{}) {
+		report("$.levels[0]", "must be an empty placeholder, levels are numbered from 1")
+	}
+
+	for levelID := 1; levelID < len(c.Levels); levelID++ {
+		level := c.Levels[levelID]
+		path := fmt.Sprintf("$.levels[%d]", levelID)
+
+		if level.EnergyCost < 0 {
+			report(path+".energyCost", "must not be negative")
+		} else if c.Energy.MaxEnergy > 0 && level.EnergyCost > c.Energy.MaxEnergy {
+			report(path+".energyCost", "must not exceed maxEnergy (%d)", c.Energy.MaxEnergy)
+		}
+		if level.MaxRolls <= 0 {
+			report(path+".maxRolls", "must be positive")
+		}
+		if level.TargetNumber < 1 || level.TargetNumber > dice.Faces {
+			report(path+".targetNumber", "must be between 1 and %d", dice.Faces)
+		}
+		if level.EnergyReward < 0 {
+			report(path+".energyReward", "must not be negative")
+		}
+	}
+
+	c.validateOnboarding(report)
+
+	switch c.AbandonedLevelPolicy {
+	case "", AbandonedLevelLoss, AbandonedLevelRefund:
+	default:
+		report("$.abandonedLevelPolicy", "must be %q or %q", AbandonedLevelLoss, AbandonedLevelRefund)
+	}
+
+	if len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
+
+func (c *Configs) validateOnboarding(report func(path, format string, args ...any)) {
+	if len(c.Onboarding.Variants) == 0 {
+		report("$.onboarding.variants", "must hold at least one variant")
+	}
+
+	names := make(map[string]bool)
+	for i, variant := range c.Onboarding.Variants {
+		path := fmt.Sprintf("$.onboarding.variants[%d]", i)
+
+		if variant.Name == "" {
+			report(path+".name", "must not be empty")
+		} else if names[variant.Name] {
+			report(path+".name", "must be unique")
+		}
+		names[variant.Name] = true
+
+		if variant.Weight <= 0 {
+			report(path+".weight", "must be positive")
+		}
+		if variant.Energy < 0 {
+			report(path+".energy", "must not be negative")
+		}
+		if variant.CurrentLevel < 1 || variant.CurrentLevel >= len(c.Levels) {
+			report(path+".currentLevel", "must be a configured level")
+		}
+
+		levelIDs := make(map[int]bool)
+		for j, stats := range variant.Statistics {
+			statsPath := fmt.Sprintf("%s.statistics[%d]", path, j)
+
+			if stats.LevelID < 1 || stats.LevelID > variant.CurrentLevel {
+				report(statsPath+".levelId", "must be an unlocked level")
+			} else if levelIDs[stats.LevelID] {
+				report(statsPath+".levelId", "must be unique")
+			}
+			levelIDs[stats.LevelID] = true
+
+			if stats.BestScore < 0 || stats.Wins < 0 || stats.Losses < 0 {
+				report(statsPath, "must not hold negative values")
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+//go:build unit
+// +build unit
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newValidConfigs() Configs {
+	return Configs{
+		Energy: EnergyConfig{
+			MaxEnergy:               10,
+			RechargeIntervalSeconds: 60,
+		},
+		Levels: []LevelConfig{
+			{},
+			{EnergyCost: 1, MaxRolls: 10, TargetNumber: 1, EnergyReward: 2},
+		},
+		Onboarding: OnboardingConfig{
+			Variants: []OnboardingVariant{
+				{Name: "default", Weight: 1, Energy: 5, CurrentLevel: 1},
+			},
+		},
+	}
+}
+
+func TestValidate_ValidConfigs_ShouldSucceed(t *testing.T) {
+	configs := newValidConfigs()
+
+	assert.NoError(t, configs.Validate())
+}
+
+func TestValidate_InvalidConfigs_ShouldReportAllViolations(t *testing.T) {
+	configs := newValidConfigs()
+	configs.Energy.RechargeIntervalSeconds = 0
+	configs.Levels[0] = LevelConfig{MaxRolls: 1}
+	configs.Levels = append(configs.Levels,
+		LevelConfig{EnergyCost: 11, MaxRolls: 0, TargetNumber: 7, EnergyReward: -1},
+	)
+
+	err := configs.Validate()
+
+	var violations ConfigViolations
+	require.ErrorAs(t, err, &violations)
+	assert.Equal(t, ConfigViolations{
+		{Path: "$.energy.rechargeIntervalSeconds", Message: "must be positive"},
+		{Path: "$.levels[0]", Message: "must be an empty placeholder, levels are numbered from 1"},
+		{Path: "$.levels[2].energyCost", Message: "must not exceed maxEnergy (10)"},
+		{Path: "$.levels[2].maxRolls", Message: "must be positive"},
+		{Path: "$.levels[2].targetNumber", Message: "must be between 1 and 6"},
+		{Path: "$.levels[2].energyReward", Message: "must not be negative"},
+	}, violations)
+}
+
+func TestValidate_WithoutLevels_ShouldFail(t *testing.T) {
+	configs := newValidConfigs()
+	configs.Levels = nil
+
+	err := configs.Validate()
+
+	var violations ConfigViolations
+	require.ErrorAs(t, err, &violations)
+	assert.Contains(t, violations, ConfigViolation{Path: "$.levels", Message: "must hold at least one level after the placeholder"})
+}
+
+func TestValidate_InvalidOnboarding_ShouldReportViolations(t *testing.T) {
+	configs := newValidConfigs()
+	configs.Onboarding.Variants = append(configs.Onboarding.Variants, OnboardingVariant{
+		Name:         "default",
+		Weight:       0,
+		Energy:       -1,
+		CurrentLevel: 1,
+		Statistics: []LevelStats{
+			{LevelID: 1, Wins: 1},
+			{LevelID: 1, Wins: 1},
+			{LevelID: 2, Losses: -1},
+		},
+	}, OnboardingVariant{Name: "skip", Weight: 1, CurrentLevel: 2})
+
+	err := configs.Validate()
+
+	var violations ConfigViolations
+	require.ErrorAs(t, err, &violations)
+	assert.Equal(t, ConfigViolations{
+		{Path: "$.onboarding.variants[1].name", Message: "must be unique"},
+		{Path: "$.onboarding.variants[1].weight", Message: "must be positive"},
+		{Path: "$.onboarding.variants[1].energy", Message: "must not be negative"},
+		{Path: "$.onboarding.variants[1].statistics[1].levelId", Message: "must be unique"},
+		{Path: "$.onboarding.variants[1].statistics[2].levelId", Message: "must be an unlocked level"},
+		{Path: "$.onboarding.variants[1].statistics[2]", Message: "must not hold negative values"},
+		{Path: "$.onboarding.variants[2].currentLevel", Message: "must be a configured level"},
+	}, violations)
+}
+
+func TestValidate_WithoutOnboardingVariants_ShouldFail(t *testing.T) {
+	configs := newValidConfigs()
+	configs.Onboarding.Variants = nil
+
+	err := configs.Validate()
+
+	assert.EqualError(t, err, "$.onboarding.variants: must hold at least one variant")
+}
+
+func TestValidate_UnknownAbandonedLevelPolicy_ShouldFail(t *testing.T) {
+	configs := newValidConfigs()
+	configs.AbandonedLevelPolicy = "forfeit"
+
+	err := configs.Validate()
+
+	assert.EqualError(t, err, `$.abandonedLevelPolicy: must be "loss" or "refund"`)
+}
@@ -0,0 +1,43 @@
+package dice
+
+// Faces is the number of faces of the dice.
+const Faces = 6
+
+// RNG is a SplitMix64 generator. Its output is part of the contract with the
+// client, which must roll the same numbers from the same seed, so the
+// algorithm can't change without versioning the seeds.
+type RNG struct {
+	state uint64
+}
+
+func NewRNG(seed uint64) *RNG {
+	return &RNG{
+		state: seed,
+	}
+}
+
+func (r *RNG) Next() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Roll returns the next dice face, from 1 to Faces.
+func (r *RNG) Roll() int {
+	return int(r.Next()%Faces) + 1
+}
+
+// Play rolls the dice of a seeded level until getting the target number or
+// running out of rolls. It returns the number of rolls and whether the target
+// number was hit.
+func Play(seed uint64, maxRolls, targetNumber int) (int, bool) {
+	rng := NewRNG(seed)
+	for roll := 1; roll <= maxRolls; roll++ {
+		if rng.Roll() == targetNumber {
+			return roll, true
+		}
+	}
+	return maxRolls, false
+}
@@ -0,0 +1,57 @@
+//go:build unit
+// +build unit
+
+package dice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRNG_Next_ShouldMatchReferenceOutput(t *testing.T) {
+	rng := NewRNG(1234567)
+
+	assert.Equal(t, uint64(6457827717110365317), rng.Next())
+	assert.Equal(t, uint64(3203168211198807973), rng.Next())
+	assert.Equal(t, uint64(9817491932198370423), rng.Next())
+}
+
+func TestRNG_Roll_ShouldBeDeterministicAndInRange(t *testing.T) {
+	first := NewRNG(42)
+	second := NewRNG(42)
+
+	for range 1000 {
+		roll := first.Roll()
+		assert.Equal(t, roll, second.Roll())
+		assert.GreaterOrEqual(t, roll, 1)
+		assert.LessOrEqual(t, roll, Faces)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	t.Run("target number hit", func(t *testing.T) {
+		rolls, success := Play(1234567, 100, 3)
+
+		assert.True(t, success)
+		rng := NewRNG(1234567)
+		for range rolls - 1 {
+			assert.NotEqual(t, 3, rng.Roll())
+		}
+		assert.Equal(t, 3, rng.Roll())
+	})
+
+	t.Run("out of rolls", func(t *testing.T) {
+		rolls, success := Play(1234567, 0, 3)
+
+		assert.False(t, success)
+		assert.Equal(t, 0, rolls)
+	})
+
+	t.Run("unreachable target number", func(t *testing.T) {
+		rolls, success := Play(1234567, 5, Faces+1)
+
+		assert.False(t, success)
+		assert.Equal(t, 5, rolls)
+	})
+}
@@ -0,0 +1,27 @@
+package core
+
+import "time"
+
+// PredictAmount returns the energy amount at now, recharged by one every
+// recharge interval since the last recharge, up to the max.
+func (e Energy) PredictAmount(now time.Time, config EnergyConfig) int {
+	if e.CurrentAmount >= config.MaxEnergy {
+		return e.CurrentAmount
+	}
+
+	timeSinceRecharge := now.Sub(e.LastRechargeAt)
+	rechargeIntervals := int(timeSinceRecharge / config.RechargeInterval())
+	predictedAmount := e.CurrentAmount + rechargeIntervals
+
+	if predictedAmount > config.MaxEnergy {
+		return config.MaxEnergy
+	}
+	return predictedAmount
+}
+
+// RefilledAt returns when the energy gets back to the max, which is the last
+// recharge if it's already there.
+func (e Energy) RefilledAt(config EnergyConfig) time.Time {
+	missing := max(config.MaxEnergy-e.CurrentAmount, 0)
+	return e.LastRechargeAt.Add(time.Duration(missing) * config.RechargeInterval())
+}
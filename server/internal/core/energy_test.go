@@ -0,0 +1,57 @@
+//go:build unit
+// +build unit
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnergy(t *testing.T) {
+	config := EnergyConfig{MaxEnergy: 10, RechargeIntervalSeconds: 60}
+	lastRechargeAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	table := map[string]struct {
+		currentAmount      int
+		elapsed            time.Duration
+		expectedAmount     int
+		expectedRefilledAt time.Time
+	}{
+		"no recharge yet": {
+			currentAmount:      5,
+			elapsed:            59 * time.Second,
+			expectedAmount:     5,
+			expectedRefilledAt: lastRechargeAt.Add(5 * time.Minute),
+		},
+		"partially recharged": {
+			currentAmount:      5,
+			elapsed:            150 * time.Second,
+			expectedAmount:     7,
+			expectedRefilledAt: lastRechargeAt.Add(5 * time.Minute),
+		},
+		"recharged up to the max": {
+			currentAmount:      5,
+			elapsed:            time.Hour,
+			expectedAmount:     10,
+			expectedRefilledAt: lastRechargeAt.Add(5 * time.Minute),
+		},
+		"above the max": {
+			currentAmount:      12,
+			elapsed:            time.Hour,
+			expectedAmount:     12,
+			expectedRefilledAt: lastRechargeAt,
+		},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			energy := Energy{CurrentAmount: row.currentAmount, LastRechargeAt: lastRechargeAt}
+
+			assert.Equal(t, row.expectedAmount, energy.PredictAmount(lastRechargeAt.Add(row.elapsed), config))
+			assert.Equal(t, row.expectedRefilledAt, energy.RefilledAt(config))
+		})
+	}
+}
@@ -15,12 +15,15 @@ type PlayerState struct {
 }
 
 type PersistentState struct {
+	Revision         int64            `json:"revision"`
 	Energy           Energy           `json:"energy"`
 	LevelProgression LevelProgression `json:"levelProgression"`
 }
 
 type SessionState struct {
 	CurrentLevelID *int `json:"currentLevelId,omitempty"`
+	// LevelSeed seeds the dice of the level in progress.
+	LevelSeed *uint64 `json:"levelSeed,omitempty"`
 }
 
 type Energy struct {
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// OnboardingConfig holds the initial states of new accounts. Accounts are
+// split between variants, so different onboardings can be A/B tested.
+type OnboardingConfig struct {
+	Variants []OnboardingVariant `json:"variants"`
+}
+
+type OnboardingVariant struct {
+	Name string `json:"name"`
+	// Weight is the share of new accounts getting this variant, relative to
+	// the weights of the other variants.
+	Weight       int          `json:"weight"`
+	Energy       int          `json:"energy"`
+	CurrentLevel int          `json:"currentLevel"`
+	Statistics   []LevelStats `json:"statistics,omitempty"`
+}
+
+// VariantFor picks the variant of an account from a hash of its id, so the
+// same account always gets the same variant while the weights don't change.
+func (c *OnboardingConfig) VariantFor(accountID string) OnboardingVariant {
+	totalWeight := 0
+	for _, variant := range c.Variants {
+		totalWeight += variant.Weight
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(accountID))
+	bucket := int(hash.Sum32() % uint32(totalWeight))
+
+	for _, variant := range c.Variants {
+		if bucket < variant.Weight {
+			return variant
+		}
+		bucket -= variant.Weight
+	}
+
+	return c.Variants[len(c.Variants)-1]
+}
+
+func (v *OnboardingVariant) InitialState(now time.Time) PersistentState {
+	statistics := make([]LevelStats, len(v.Statistics))
+	copy(statistics, v.Statistics)
+
+	return PersistentState{
+		Energy: Energy{
+			CurrentAmount:  v.Energy,
+			LastRechargeAt: now,
+		},
+		LevelProgression: LevelProgression{
+			CurrentLevel: v.CurrentLevel,
+			Statistics:   statistics,
+		},
+	}
+}
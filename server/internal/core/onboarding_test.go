@@ -0,0 +1,51 @@
+//go:build unit
+// +build unit
+
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariantFor_ShouldBeStableAndWeighted(t *testing.T) {
+	config := OnboardingConfig{
+		Variants: []OnboardingVariant{
+			{Name: "control", Weight: 3},
+			{Name: "experiment", Weight: 1},
+		},
+	}
+
+	counts := make(map[string]int)
+	for i := range 4000 {
+		accountID := fmt.Sprintf("account-%d", i)
+		variant := config.VariantFor(accountID)
+		assert.Equal(t, variant.Name, config.VariantFor(accountID).Name)
+		counts[variant.Name]++
+	}
+
+	assert.InDelta(t, 3000, counts["control"], 200)
+	assert.InDelta(t, 1000, counts["experiment"], 200)
+}
+
+func TestInitialState_ShouldCopyVariant(t *testing.T) {
+	variant := OnboardingVariant{
+		Name:         "tutorial-skipped",
+		Weight:       1,
+		Energy:       8,
+		CurrentLevel: 2,
+		Statistics:   []LevelStats{{LevelID: 1, Wins: 1}},
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	state := variant.InitialState(now)
+	state.LevelProgression.Statistics[0].Wins++
+
+	assert.Equal(t, 8, state.Energy.CurrentAmount)
+	assert.Equal(t, now, state.Energy.LastRechargeAt)
+	assert.Equal(t, 2, state.LevelProgression.CurrentLevel)
+	assert.Equal(t, 1, variant.Statistics[0].Wins)
+}
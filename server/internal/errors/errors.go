@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -29,3 +30,56 @@ func Is(err, target error) bool {
 func As(err error, target interface{}) bool {
 	return errors.As(err, target)
 }
+
+// Join returns an error wrapping every non-nil error, or nil if there's none.
+func Join(errs ...error) error {
+	return stderrors.Join(errs...)
+}
+
+// CodedError is an error carrying a stable, machine-readable code meant to be
+// matched by clients. Code is declared next to the error, the transports only
+// choose the status of each code.
+type CodedError struct {
+	Code    string
+	Message string
+	// parent is the error it details, if any, still matched by Is.
+	parent *CodedError
+}
+
+func NewCoded(code string, message string) *CodedError {
+	return &CodedError{Code: code, Message: message}
+}
+
+func (e *CodedError) Error() string {
+	return e.Message
+}
+
+func (e *CodedError) ErrorCode() string {
+	return e.Code
+}
+
+// Detailf returns an error with the code of e and a message detailing it, still
+// matching e with Is.
+func (e *CodedError) Detailf(format string, args ...interface{}) error {
+	return errors.WithStack(&CodedError{
+		Code:    e.Code,
+		Message: fmt.Sprintf(format, args...) + ": " + e.Message,
+		parent:  e,
+	})
+}
+
+func (e *CodedError) Unwrap() error {
+	if e.parent == nil {
+		return nil
+	}
+	return e.parent
+}
+
+// AsCoded returns the first CodedError of the chain of err, if any.
+func AsCoded(err error) (*CodedError, bool) {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded, true
+	}
+	return nil, false
+}
@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"technical-test-backend/internal/sessions"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// SessionIDMetadata is the metadata key carrying the session id, both in the
+// requests and in the Login response header.
+const SessionIDMetadata = "x-session-id"
+
+var (
+	ErrInvalidSessionID        = errors.New("invalid session id metadata")
+	ErrInvalidOrExpiredSession = errors.New("invalid or expired session")
+)
+
+var endedSessionStatuses = StatusCodes{
+	sessions.ErrorCodeSessionExpired:   codes.Unauthenticated,
+	sessions.ErrorCodeSessionReplaced:  codes.Unauthenticated,
+	sessions.ErrorCodeSessionLoggedOut: codes.Unauthenticated,
+	sessions.ErrorCodeSessionRevoked:   codes.Unauthenticated,
+}
+
+type contextKey int
+
+const (
+	sessionIDContextKey contextKey = iota
+	accountIDContextKey
+)
+
+// SessionID returns the session id of an authenticated call.
+func SessionID(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionIDContextKey).(string)
+	return sessionID
+}
+
+// AccountID returns the account id of an authenticated call.
+func AccountID(ctx context.Context) string {
+	accountID, _ := ctx.Value(accountIDContextKey).(string)
+	return accountID
+}
+
+// AuthInterceptor is the gRPC equivalent of the HTTP AuthMiddleware. It
+// authenticates every call but the public methods, and refreshes the session.
+type AuthInterceptor struct {
+	sessionPool   sessions.Pool
+	publicMethods map[string]bool
+}
+
+// NewAuthInterceptor takes the full names of the methods callable without a
+// session, like gamepb.AuthenticationHandler_Login_FullMethodName.
+func NewAuthInterceptor(sessionPool sessions.Pool, publicMethods ...string) *AuthInterceptor {
+	interceptor := &AuthInterceptor{
+		sessionPool:   sessionPool,
+		publicMethods: make(map[string]bool, len(publicMethods)),
+	}
+	for _, method := range publicMethods {
+		interceptor.publicMethods[method] = true
+	}
+
+	return interceptor
+}
+
+func (i *AuthInterceptor) Unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if i.publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	var sessionID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(SessionIDMetadata); len(values) > 0 {
+			sessionID = values[0]
+		}
+	}
+	if sessionID == "" {
+		return nil, Error(codes.Unauthenticated, "", ErrInvalidSessionID.Error(), nil)
+	}
+
+	accountID, authenticated := i.sessionPool.GetAccountID(ctx, sessionID)
+	if !authenticated {
+		return nil, i.unauthenticated(ctx, sessionID)
+	}
+
+	if err := i.sessionPool.UpdateActivity(ctx, sessionID); err != nil {
+		log.Printf("Warning: Failed to update session activity: %v", err)
+	}
+
+	ctx = context.WithValue(ctx, sessionIDContextKey, sessionID)
+	ctx = context.WithValue(ctx, accountIDContextKey, accountID)
+
+	return handler(ctx, req)
+}
+
+func (i *AuthInterceptor) unauthenticated(ctx context.Context, sessionID string) error {
+	tombstone, found := i.sessionPool.GetTombstone(ctx, sessionID)
+	// The pool reports sessions as missing once the call context is done.
+	if err := ctx.Err(); err != nil {
+		return MapError(err, nil)
+	}
+
+	if !found {
+		return Error(codes.Unauthenticated, "", ErrInvalidOrExpiredSession.Error(), nil)
+	}
+
+	endedSessionErr := tombstone.Err()
+	if endedSessionErr == nil {
+		return Error(codes.Unauthenticated, "", ErrInvalidOrExpiredSession.Error(), nil)
+	}
+
+	return MapError(endedSessionErr, endedSessionStatuses)
+}
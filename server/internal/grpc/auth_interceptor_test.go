@@ -0,0 +1,88 @@
+//go:build unit
+// +build unit
+
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/sessions/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testMethod       = "/game.Test/Method"
+	testPublicMethod = "/game.Test/PublicMethod"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	sessionPool := memory.NewSessionPool(memory.SessionPoolConfig{TTL: time.Minute, TombstoneTTL: time.Minute})
+	interceptor := NewAuthInterceptor(sessionPool, testPublicMethod)
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return AccountID(ctx), nil
+	}
+
+	replaced, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	loggedOut, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	sessionPool.RemoveSession(context.Background(), loggedOut.ID)
+
+	revoked, err := sessionPool.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+	sessionPool.RevokeSessions(context.Background(), "account-2")
+
+	live, err := sessionPool.CreateSession(context.Background(), "account-3", nil)
+	require.NoError(t, err)
+
+	table := map[string]struct {
+		method            string
+		sessionID         string
+		expectedAccountID string
+		expectedCode      codes.Code
+		expectedReason    string
+	}{
+		"live session":       {testMethod, live.ID, "account-3", codes.OK, ""},
+		"public method":      {testPublicMethod, "", "", codes.OK, ""},
+		"missing metadata":   {testMethod, "", "", codes.Unauthenticated, ""},
+		"unknown session":    {testMethod, "unknown", "", codes.Unauthenticated, ""},
+		"replaced session":   {testMethod, replaced.ID, "", codes.Unauthenticated, sessions.ErrorCodeSessionReplaced},
+		"logged out session": {testMethod, loggedOut.ID, "", codes.Unauthenticated, sessions.ErrorCodeSessionLoggedOut},
+		"revoked session":    {testMethod, revoked.ID, "", codes.Unauthenticated, sessions.ErrorCodeSessionRevoked},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if row.sessionID != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(SessionIDMetadata, row.sessionID))
+			}
+
+			res, err := interceptor.Unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: row.method}, handler)
+
+			assert.Equal(t, row.expectedCode, status.Code(err))
+			if row.expectedCode == codes.OK {
+				assert.Equal(t, row.expectedAccountID, res)
+				return
+			}
+
+			info := errorInfo(t, err)
+			if row.expectedReason == "" {
+				assert.Nil(t, info)
+				return
+			}
+			require.NotNil(t, info)
+			assert.Equal(t, row.expectedReason, info.GetReason())
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/grpc/gamepb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func PersistentStateToProto(state core.PersistentState) *gamepb.PersistentState {
+	return &gamepb.PersistentState{
+		Revision: state.Revision,
+		Energy: &gamepb.Energy{
+			CurrentAmount:  int32(state.Energy.CurrentAmount),
+			LastRechargeAt: timestamppb.New(state.Energy.LastRechargeAt),
+		},
+		LevelProgression: &gamepb.LevelProgression{
+			CurrentLevel: int32(state.LevelProgression.CurrentLevel),
+			Statistics:   levelStatsToProto(state.LevelProgression.Statistics),
+		},
+	}
+}
+
+func SessionStateToProto(state core.SessionState) *gamepb.SessionState {
+	session := &gamepb.SessionState{
+		LevelSeed: state.LevelSeed,
+	}
+	if state.CurrentLevelID != nil {
+		currentLevelID := int32(*state.CurrentLevelID)
+		session.CurrentLevelId = &currentLevelID
+	}
+
+	return session
+}
+
+func ConfigsToProto(configs core.Configs) *gamepb.Configs {
+	levels := make([]*gamepb.LevelConfig, 0, len(configs.Levels))
+	for _, level := range configs.Levels {
+		levels = append(levels, &gamepb.LevelConfig{
+			EnergyCost:   int32(level.EnergyCost),
+			MaxRolls:     int32(level.MaxRolls),
+			TargetNumber: int32(level.TargetNumber),
+			EnergyReward: int32(level.EnergyReward),
+		})
+	}
+
+	variants := make([]*gamepb.OnboardingVariant, 0, len(configs.Onboarding.Variants))
+	for _, variant := range configs.Onboarding.Variants {
+		variants = append(variants, &gamepb.OnboardingVariant{
+			Name:         variant.Name,
+			Weight:       int32(variant.Weight),
+			Energy:       int32(variant.Energy),
+			CurrentLevel: int32(variant.CurrentLevel),
+			Statistics:   levelStatsToProto(variant.Statistics),
+		})
+	}
+
+	return &gamepb.Configs{
+		Levels: levels,
+		Energy: &gamepb.EnergyConfig{
+			MaxEnergy:               int32(configs.Energy.MaxEnergy),
+			RechargeIntervalSeconds: int32(configs.Energy.RechargeIntervalSeconds),
+		},
+		Onboarding: &gamepb.OnboardingConfig{
+			Variants: variants,
+		},
+		AbandonedLevelPolicy: string(configs.AbandonedLevelPolicy),
+	}
+}
+
+func levelStatsToProto(statistics []core.LevelStats) []*gamepb.LevelStats {
+	protoStatistics := make([]*gamepb.LevelStats, 0, len(statistics))
+	for _, stats := range statistics {
+		protoStatistics = append(protoStatistics, &gamepb.LevelStats{
+			LevelId:   int32(stats.LevelID),
+			BestScore: int32(stats.BestScore),
+			Wins:      int32(stats.Wins),
+			Losses:    int32(stats.Losses),
+		})
+	}
+
+	return protoStatistics
+}
@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"technical-test-backend/internal/errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details of the errors.
+const ErrorDomain = "technical-test-backend"
+
+// StatusCodes maps error codes to the status code of their calls.
+type StatusCodes map[string]codes.Code
+
+// MapError returns the status error of the code carried by err, with the code
+// as the reason, or an Internal one with the full error message otherwise.
+func MapError(err error, statusCodes StatusCodes) error {
+	return MapErrorWithMetadata(err, statusCodes, nil)
+}
+
+// MapErrorWithMetadata is MapError, with metadata added to the ErrorInfo
+// detail. Errors caused by the end of the call context keep their code.
+func MapErrorWithMetadata(err error, statusCodes StatusCodes, metadata map[string]string) error {
+	if coded, ok := errors.AsCoded(err); ok {
+		if code, mapped := statusCodes[coded.Code]; mapped {
+			return Error(code, coded.Code, coded.Error(), metadata)
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return Error(codes.DeadlineExceeded, "", err.Error(), metadata)
+	case errors.Is(err, context.Canceled):
+		return Error(codes.Canceled, "", err.Error(), metadata)
+	}
+
+	return Error(codes.Internal, "", err.Error(), metadata)
+}
+
+// Error returns a status error, with an ErrorInfo detail holding the reason
+// if there's one.
+func Error(code codes.Code, reason string, message string, metadata map[string]string) error {
+	st := status.New(code, message)
+	if reason == "" && len(metadata) == 0 {
+		return st.Err()
+	}
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ErrorDomain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package grpc
+
+import (
+	"testing"
+
+	"technical-test-backend/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errTestCoded    = errors.NewCoded("CODED_FAILURE", "coded failure")
+	errTestUnmapped = errors.NewCoded("UNMAPPED_FAILURE", "unmapped failure")
+)
+
+// errorInfo returns the ErrorInfo detail of a status error, if any.
+func errorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
+
+func TestMapErrorWithMetadata(t *testing.T) {
+	statusCodes := StatusCodes{
+		"CODED_FAILURE": codes.FailedPrecondition,
+	}
+
+	table := map[string]struct {
+		err             error
+		metadata        map[string]string
+		expectedCode    codes.Code
+		expectedMessage string
+		expectedReason  string
+	}{
+		"coded error uses its own code and message": {
+			err:             errors.Wrap(errTestCoded, errors.New("wrapper")),
+			expectedCode:    codes.FailedPrecondition,
+			expectedMessage: "coded failure",
+			expectedReason:  "CODED_FAILURE",
+		},
+		"detailed coded error keeps its code": {
+			err:             errTestCoded.Detailf("attempt %d", 2),
+			expectedCode:    codes.FailedPrecondition,
+			expectedMessage: "attempt 2: coded failure",
+			expectedReason:  "CODED_FAILURE",
+		},
+		"metadata is kept": {
+			err:             errTestCoded,
+			metadata:        map[string]string{"index": "2"},
+			expectedCode:    codes.FailedPrecondition,
+			expectedMessage: "coded failure",
+			expectedReason:  "CODED_FAILURE",
+		},
+		"coded error without status falls back to internal error": {
+			err:             errTestUnmapped,
+			expectedCode:    codes.Internal,
+			expectedMessage: "unmapped failure",
+		},
+		"unmapped error falls back to internal error": {
+			err:             errors.New("unexpected failure"),
+			expectedCode:    codes.Internal,
+			expectedMessage: "unexpected failure",
+		},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			err := MapErrorWithMetadata(row.err, statusCodes, row.metadata)
+
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, row.expectedCode, st.Code())
+			assert.Equal(t, row.expectedMessage, st.Message())
+
+			info := errorInfo(t, err)
+			if row.expectedReason == "" && row.metadata == nil {
+				assert.Nil(t, info)
+				return
+			}
+
+			require.NotNil(t, info)
+			assert.Equal(t, row.expectedReason, info.GetReason())
+			assert.Equal(t, ErrorDomain, info.GetDomain())
+			assert.Equal(t, row.metadata, info.GetMetadata())
+		})
+	}
+}
@@ -0,0 +1,1612 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: game.proto
+
+package gamepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RegisterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_game_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{0}
+}
+
+type RegisterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
+	mi := &file_game_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResponse) ProtoMessage() {}
+
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RegisterResponse) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_game_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LoginRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type LoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	mi := &file_game_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{3}
+}
+
+type LogoutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
+	mi := &file_game_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequest) ProtoMessage() {}
+
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{4}
+}
+
+type LogoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
+	mi := &file_game_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutResponse) ProtoMessage() {}
+
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{5}
+}
+
+type GetPlayerStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPlayerStateRequest) Reset() {
+	*x = GetPlayerStateRequest{}
+	mi := &file_game_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPlayerStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPlayerStateRequest) ProtoMessage() {}
+
+func (x *GetPlayerStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPlayerStateRequest.ProtoReflect.Descriptor instead.
+func (*GetPlayerStateRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{6}
+}
+
+type GetPlayerStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerState   *PlayerState           `protobuf:"bytes,1,opt,name=player_state,json=playerState,proto3" json:"player_state,omitempty"`
+	ServerTime    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPlayerStateResponse) Reset() {
+	*x = GetPlayerStateResponse{}
+	mi := &file_game_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPlayerStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPlayerStateResponse) ProtoMessage() {}
+
+func (x *GetPlayerStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPlayerStateResponse.ProtoReflect.Descriptor instead.
+func (*GetPlayerStateResponse) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPlayerStateResponse) GetPlayerState() *PlayerState {
+	if x != nil {
+		return x.PlayerState
+	}
+	return nil
+}
+
+func (x *GetPlayerStateResponse) GetServerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ServerTime
+	}
+	return nil
+}
+
+type PlayerState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Persistent    *PersistentState       `protobuf:"bytes,1,opt,name=persistent,proto3" json:"persistent,omitempty"`
+	Session       *SessionState          `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerState) Reset() {
+	*x = PlayerState{}
+	mi := &file_game_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerState) ProtoMessage() {}
+
+func (x *PlayerState) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerState.ProtoReflect.Descriptor instead.
+func (*PlayerState) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PlayerState) GetPersistent() *PersistentState {
+	if x != nil {
+		return x.Persistent
+	}
+	return nil
+}
+
+func (x *PlayerState) GetSession() *SessionState {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+type PersistentState struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Revision         int64                  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Energy           *Energy                `protobuf:"bytes,2,opt,name=energy,proto3" json:"energy,omitempty"`
+	LevelProgression *LevelProgression      `protobuf:"bytes,3,opt,name=level_progression,json=levelProgression,proto3" json:"level_progression,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PersistentState) Reset() {
+	*x = PersistentState{}
+	mi := &file_game_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PersistentState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PersistentState) ProtoMessage() {}
+
+func (x *PersistentState) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PersistentState.ProtoReflect.Descriptor instead.
+func (*PersistentState) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PersistentState) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *PersistentState) GetEnergy() *Energy {
+	if x != nil {
+		return x.Energy
+	}
+	return nil
+}
+
+func (x *PersistentState) GetLevelProgression() *LevelProgression {
+	if x != nil {
+		return x.LevelProgression
+	}
+	return nil
+}
+
+type Energy struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CurrentAmount  int32                  `protobuf:"varint,1,opt,name=current_amount,json=currentAmount,proto3" json:"current_amount,omitempty"`
+	LastRechargeAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_recharge_at,json=lastRechargeAt,proto3" json:"last_recharge_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Energy) Reset() {
+	*x = Energy{}
+	mi := &file_game_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Energy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Energy) ProtoMessage() {}
+
+func (x *Energy) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Energy.ProtoReflect.Descriptor instead.
+func (*Energy) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Energy) GetCurrentAmount() int32 {
+	if x != nil {
+		return x.CurrentAmount
+	}
+	return 0
+}
+
+func (x *Energy) GetLastRechargeAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRechargeAt
+	}
+	return nil
+}
+
+type LevelProgression struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CurrentLevel  int32                  `protobuf:"varint,1,opt,name=current_level,json=currentLevel,proto3" json:"current_level,omitempty"`
+	Statistics    []*LevelStats          `protobuf:"bytes,2,rep,name=statistics,proto3" json:"statistics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LevelProgression) Reset() {
+	*x = LevelProgression{}
+	mi := &file_game_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LevelProgression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LevelProgression) ProtoMessage() {}
+
+func (x *LevelProgression) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LevelProgression.ProtoReflect.Descriptor instead.
+func (*LevelProgression) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LevelProgression) GetCurrentLevel() int32 {
+	if x != nil {
+		return x.CurrentLevel
+	}
+	return 0
+}
+
+func (x *LevelProgression) GetStatistics() []*LevelStats {
+	if x != nil {
+		return x.Statistics
+	}
+	return nil
+}
+
+type LevelStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LevelId       int32                  `protobuf:"varint,1,opt,name=level_id,json=levelId,proto3" json:"level_id,omitempty"`
+	BestScore     int32                  `protobuf:"varint,2,opt,name=best_score,json=bestScore,proto3" json:"best_score,omitempty"`
+	Wins          int32                  `protobuf:"varint,3,opt,name=wins,proto3" json:"wins,omitempty"`
+	Losses        int32                  `protobuf:"varint,4,opt,name=losses,proto3" json:"losses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LevelStats) Reset() {
+	*x = LevelStats{}
+	mi := &file_game_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LevelStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LevelStats) ProtoMessage() {}
+
+func (x *LevelStats) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LevelStats.ProtoReflect.Descriptor instead.
+func (*LevelStats) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LevelStats) GetLevelId() int32 {
+	if x != nil {
+		return x.LevelId
+	}
+	return 0
+}
+
+func (x *LevelStats) GetBestScore() int32 {
+	if x != nil {
+		return x.BestScore
+	}
+	return 0
+}
+
+func (x *LevelStats) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+func (x *LevelStats) GetLosses() int32 {
+	if x != nil {
+		return x.Losses
+	}
+	return 0
+}
+
+type SessionState struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CurrentLevelId *int32                 `protobuf:"varint,1,opt,name=current_level_id,json=currentLevelId,proto3,oneof" json:"current_level_id,omitempty"`
+	LevelSeed      *uint64                `protobuf:"varint,2,opt,name=level_seed,json=levelSeed,proto3,oneof" json:"level_seed,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionState) Reset() {
+	*x = SessionState{}
+	mi := &file_game_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionState) ProtoMessage() {}
+
+func (x *SessionState) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionState.ProtoReflect.Descriptor instead.
+func (*SessionState) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SessionState) GetCurrentLevelId() int32 {
+	if x != nil && x.CurrentLevelId != nil {
+		return *x.CurrentLevelId
+	}
+	return 0
+}
+
+func (x *SessionState) GetLevelSeed() uint64 {
+	if x != nil && x.LevelSeed != nil {
+		return *x.LevelSeed
+	}
+	return 0
+}
+
+type GetConfigsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigsRequest) Reset() {
+	*x = GetConfigsRequest{}
+	mi := &file_game_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigsRequest) ProtoMessage() {}
+
+func (x *GetConfigsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigsRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigsRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{14}
+}
+
+type GetConfigsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Configs       *Configs               `protobuf:"bytes,1,opt,name=configs,proto3" json:"configs,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigsResponse) Reset() {
+	*x = GetConfigsResponse{}
+	mi := &file_game_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigsResponse) ProtoMessage() {}
+
+func (x *GetConfigsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigsResponse.ProtoReflect.Descriptor instead.
+func (*GetConfigsResponse) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetConfigsResponse) GetConfigs() *Configs {
+	if x != nil {
+		return x.Configs
+	}
+	return nil
+}
+
+func (x *GetConfigsResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type Configs struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Levels               []*LevelConfig         `protobuf:"bytes,1,rep,name=levels,proto3" json:"levels,omitempty"`
+	Energy               *EnergyConfig          `protobuf:"bytes,2,opt,name=energy,proto3" json:"energy,omitempty"`
+	Onboarding           *OnboardingConfig      `protobuf:"bytes,3,opt,name=onboarding,proto3" json:"onboarding,omitempty"`
+	AbandonedLevelPolicy string                 `protobuf:"bytes,4,opt,name=abandoned_level_policy,json=abandonedLevelPolicy,proto3" json:"abandoned_level_policy,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Configs) Reset() {
+	*x = Configs{}
+	mi := &file_game_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Configs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Configs) ProtoMessage() {}
+
+func (x *Configs) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Configs.ProtoReflect.Descriptor instead.
+func (*Configs) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Configs) GetLevels() []*LevelConfig {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+func (x *Configs) GetEnergy() *EnergyConfig {
+	if x != nil {
+		return x.Energy
+	}
+	return nil
+}
+
+func (x *Configs) GetOnboarding() *OnboardingConfig {
+	if x != nil {
+		return x.Onboarding
+	}
+	return nil
+}
+
+func (x *Configs) GetAbandonedLevelPolicy() string {
+	if x != nil {
+		return x.AbandonedLevelPolicy
+	}
+	return ""
+}
+
+type LevelConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnergyCost    int32                  `protobuf:"varint,1,opt,name=energy_cost,json=energyCost,proto3" json:"energy_cost,omitempty"`
+	MaxRolls      int32                  `protobuf:"varint,2,opt,name=max_rolls,json=maxRolls,proto3" json:"max_rolls,omitempty"`
+	TargetNumber  int32                  `protobuf:"varint,3,opt,name=target_number,json=targetNumber,proto3" json:"target_number,omitempty"`
+	EnergyReward  int32                  `protobuf:"varint,4,opt,name=energy_reward,json=energyReward,proto3" json:"energy_reward,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LevelConfig) Reset() {
+	*x = LevelConfig{}
+	mi := &file_game_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LevelConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LevelConfig) ProtoMessage() {}
+
+func (x *LevelConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LevelConfig.ProtoReflect.Descriptor instead.
+func (*LevelConfig) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LevelConfig) GetEnergyCost() int32 {
+	if x != nil {
+		return x.EnergyCost
+	}
+	return 0
+}
+
+func (x *LevelConfig) GetMaxRolls() int32 {
+	if x != nil {
+		return x.MaxRolls
+	}
+	return 0
+}
+
+func (x *LevelConfig) GetTargetNumber() int32 {
+	if x != nil {
+		return x.TargetNumber
+	}
+	return 0
+}
+
+func (x *LevelConfig) GetEnergyReward() int32 {
+	if x != nil {
+		return x.EnergyReward
+	}
+	return 0
+}
+
+type EnergyConfig struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	MaxEnergy               int32                  `protobuf:"varint,1,opt,name=max_energy,json=maxEnergy,proto3" json:"max_energy,omitempty"`
+	RechargeIntervalSeconds int32                  `protobuf:"varint,2,opt,name=recharge_interval_seconds,json=rechargeIntervalSeconds,proto3" json:"recharge_interval_seconds,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *EnergyConfig) Reset() {
+	*x = EnergyConfig{}
+	mi := &file_game_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnergyConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnergyConfig) ProtoMessage() {}
+
+func (x *EnergyConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnergyConfig.ProtoReflect.Descriptor instead.
+func (*EnergyConfig) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EnergyConfig) GetMaxEnergy() int32 {
+	if x != nil {
+		return x.MaxEnergy
+	}
+	return 0
+}
+
+func (x *EnergyConfig) GetRechargeIntervalSeconds() int32 {
+	if x != nil {
+		return x.RechargeIntervalSeconds
+	}
+	return 0
+}
+
+type OnboardingConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variants      []*OnboardingVariant   `protobuf:"bytes,1,rep,name=variants,proto3" json:"variants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnboardingConfig) Reset() {
+	*x = OnboardingConfig{}
+	mi := &file_game_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnboardingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnboardingConfig) ProtoMessage() {}
+
+func (x *OnboardingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnboardingConfig.ProtoReflect.Descriptor instead.
+func (*OnboardingConfig) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *OnboardingConfig) GetVariants() []*OnboardingVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+type OnboardingVariant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Weight        int32                  `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	Energy        int32                  `protobuf:"varint,3,opt,name=energy,proto3" json:"energy,omitempty"`
+	CurrentLevel  int32                  `protobuf:"varint,4,opt,name=current_level,json=currentLevel,proto3" json:"current_level,omitempty"`
+	Statistics    []*LevelStats          `protobuf:"bytes,5,rep,name=statistics,proto3" json:"statistics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnboardingVariant) Reset() {
+	*x = OnboardingVariant{}
+	mi := &file_game_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnboardingVariant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnboardingVariant) ProtoMessage() {}
+
+func (x *OnboardingVariant) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnboardingVariant.ProtoReflect.Descriptor instead.
+func (*OnboardingVariant) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OnboardingVariant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OnboardingVariant) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *OnboardingVariant) GetEnergy() int32 {
+	if x != nil {
+		return x.Energy
+	}
+	return 0
+}
+
+func (x *OnboardingVariant) GetCurrentLevel() int32 {
+	if x != nil {
+		return x.CurrentLevel
+	}
+	return 0
+}
+
+func (x *OnboardingVariant) GetStatistics() []*LevelStats {
+	if x != nil {
+		return x.Statistics
+	}
+	return nil
+}
+
+type Command struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Command string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	// data is the JSON encoded payload of the command, as described by the
+	// schemas of the HTTP ListCommands endpoint.
+	Data          string `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Command) Reset() {
+	*x = Command{}
+	mi := &file_game_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Command) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Command) ProtoMessage() {}
+
+func (x *Command) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Command.ProtoReflect.Descriptor instead.
+func (*Command) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Command) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *Command) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+type CommandRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Command       *Command               `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandRequest) Reset() {
+	*x = CommandRequest{}
+	mi := &file_game_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandRequest) ProtoMessage() {}
+
+func (x *CommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandRequest.ProtoReflect.Descriptor instead.
+func (*CommandRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CommandRequest) GetCommand() *Command {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *CommandRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type BatchCommandRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commands      []*Command             `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCommandRequest) Reset() {
+	*x = BatchCommandRequest{}
+	mi := &file_game_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCommandRequest) ProtoMessage() {}
+
+func (x *BatchCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCommandRequest.ProtoReflect.Descriptor instead.
+func (*BatchCommandRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BatchCommandRequest) GetCommands() []*Command {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+func (x *BatchCommandRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type CommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *SessionState          `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Duplicate     bool                   `protobuf:"varint,3,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandResponse) Reset() {
+	*x = CommandResponse{}
+	mi := &file_game_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResponse) ProtoMessage() {}
+
+func (x *CommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
+func (*CommandResponse) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CommandResponse) GetSession() *SessionState {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+func (x *CommandResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *CommandResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_game_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{25}
+}
+
+type HeartbeatResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConfigsVersion string                 `protobuf:"bytes,1,opt,name=configs_version,json=configsVersion,proto3" json:"configs_version,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_game_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *HeartbeatResponse) GetConfigsVersion() string {
+	if x != nil {
+		return x.ConfigsVersion
+	}
+	return ""
+}
+
+var File_game_proto protoreflect.FileDescriptor
+
+const file_game_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"game.proto\x12\x04game\x1a\x1fgoogle/protobuf/timestamp.proto\"\x11\n" +
+	"\x0fRegisterRequest\"I\n" +
+	"\x10RegisterResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"E\n" +
+	"\fLoginRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"\x0f\n" +
+	"\rLoginResponse\"\x0f\n" +
+	"\rLogoutRequest\"\x10\n" +
+	"\x0eLogoutResponse\"\x17\n" +
+	"\x15GetPlayerStateRequest\"\x8b\x01\n" +
+	"\x16GetPlayerStateResponse\x124\n" +
+	"\fplayer_state\x18\x01 \x01(\v2\x11.game.PlayerStateR\vplayerState\x12;\n" +
+	"\vserver_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"serverTime\"r\n" +
+	"\vPlayerState\x125\n" +
+	"\n" +
+	"persistent\x18\x01 \x01(\v2\x15.game.PersistentStateR\n" +
+	"persistent\x12,\n" +
+	"\asession\x18\x02 \x01(\v2\x12.game.SessionStateR\asession\"\x98\x01\n" +
+	"\x0fPersistentState\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\x12$\n" +
+	"\x06energy\x18\x02 \x01(\v2\f.game.EnergyR\x06energy\x12C\n" +
+	"\x11level_progression\x18\x03 \x01(\v2\x16.game.LevelProgressionR\x10levelProgression\"u\n" +
+	"\x06Energy\x12%\n" +
+	"\x0ecurrent_amount\x18\x01 \x01(\x05R\rcurrentAmount\x12D\n" +
+	"\x10last_recharge_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastRechargeAt\"i\n" +
+	"\x10LevelProgression\x12#\n" +
+	"\rcurrent_level\x18\x01 \x01(\x05R\fcurrentLevel\x120\n" +
+	"\n" +
+	"statistics\x18\x02 \x03(\v2\x10.game.LevelStatsR\n" +
+	"statistics\"r\n" +
+	"\n" +
+	"LevelStats\x12\x19\n" +
+	"\blevel_id\x18\x01 \x01(\x05R\alevelId\x12\x1d\n" +
+	"\n" +
+	"best_score\x18\x02 \x01(\x05R\tbestScore\x12\x12\n" +
+	"\x04wins\x18\x03 \x01(\x05R\x04wins\x12\x16\n" +
+	"\x06losses\x18\x04 \x01(\x05R\x06losses\"\x85\x01\n" +
+	"\fSessionState\x12-\n" +
+	"\x10current_level_id\x18\x01 \x01(\x05H\x00R\x0ecurrentLevelId\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"level_seed\x18\x02 \x01(\x04H\x01R\tlevelSeed\x88\x01\x01B\x13\n" +
+	"\x11_current_level_idB\r\n" +
+	"\v_level_seed\"\x13\n" +
+	"\x11GetConfigsRequest\"W\n" +
+	"\x12GetConfigsResponse\x12'\n" +
+	"\aconfigs\x18\x01 \x01(\v2\r.game.ConfigsR\aconfigs\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"\xce\x01\n" +
+	"\aConfigs\x12)\n" +
+	"\x06levels\x18\x01 \x03(\v2\x11.game.LevelConfigR\x06levels\x12*\n" +
+	"\x06energy\x18\x02 \x01(\v2\x12.game.EnergyConfigR\x06energy\x126\n" +
+	"\n" +
+	"onboarding\x18\x03 \x01(\v2\x16.game.OnboardingConfigR\n" +
+	"onboarding\x124\n" +
+	"\x16abandoned_level_policy\x18\x04 \x01(\tR\x14abandonedLevelPolicy\"\x95\x01\n" +
+	"\vLevelConfig\x12\x1f\n" +
+	"\venergy_cost\x18\x01 \x01(\x05R\n" +
+	"energyCost\x12\x1b\n" +
+	"\tmax_rolls\x18\x02 \x01(\x05R\bmaxRolls\x12#\n" +
+	"\rtarget_number\x18\x03 \x01(\x05R\ftargetNumber\x12#\n" +
+	"\renergy_reward\x18\x04 \x01(\x05R\fenergyReward\"i\n" +
+	"\fEnergyConfig\x12\x1d\n" +
+	"\n" +
+	"max_energy\x18\x01 \x01(\x05R\tmaxEnergy\x12:\n" +
+	"\x19recharge_interval_seconds\x18\x02 \x01(\x05R\x17rechargeIntervalSeconds\"G\n" +
+	"\x10OnboardingConfig\x123\n" +
+	"\bvariants\x18\x01 \x03(\v2\x17.game.OnboardingVariantR\bvariants\"\xae\x01\n" +
+	"\x11OnboardingVariant\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x05R\x06weight\x12\x16\n" +
+	"\x06energy\x18\x03 \x01(\x05R\x06energy\x12#\n" +
+	"\rcurrent_level\x18\x04 \x01(\x05R\fcurrentLevel\x120\n" +
+	"\n" +
+	"statistics\x18\x05 \x03(\v2\x10.game.LevelStatsR\n" +
+	"statistics\"7\n" +
+	"\aCommand\x12\x18\n" +
+	"\acommand\x18\x01 \x01(\tR\acommand\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\tR\x04data\"U\n" +
+	"\x0eCommandRequest\x12'\n" +
+	"\acommand\x18\x01 \x01(\v2\r.game.CommandR\acommand\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\"\\\n" +
+	"\x13BatchCommandRequest\x12)\n" +
+	"\bcommands\x18\x01 \x03(\v2\r.game.CommandR\bcommands\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\"y\n" +
+	"\x0fCommandResponse\x12,\n" +
+	"\asession\x18\x01 \x01(\v2\x12.game.SessionStateR\asession\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\x12\x1c\n" +
+	"\tduplicate\x18\x03 \x01(\bR\tduplicate\"\x12\n" +
+	"\x10HeartbeatRequest\"<\n" +
+	"\x11HeartbeatResponse\x12'\n" +
+	"\x0fconfigs_version\x18\x01 \x01(\tR\x0econfigsVersion2\xb9\x01\n" +
+	"\x15AuthenticationHandler\x129\n" +
+	"\bRegister\x12\x15.game.RegisterRequest\x1a\x16.game.RegisterResponse\x120\n" +
+	"\x05Login\x12\x12.game.LoginRequest\x1a\x13.game.LoginResponse\x123\n" +
+	"\x06Logout\x12\x13.game.LogoutRequest\x1a\x14.game.LogoutResponse2\xa5\x01\n" +
+	"\x15InitializationHandler\x12K\n" +
+	"\x0eGetPlayerState\x12\x1b.game.GetPlayerStateRequest\x1a\x1c.game.GetPlayerStateResponse\x12?\n" +
+	"\n" +
+	"GetConfigs\x12\x17.game.GetConfigsRequest\x1a\x18.game.GetConfigsResponse2\x92\x01\n" +
+	"\x0eCommandHandler\x12<\n" +
+	"\rHandleCommand\x12\x14.game.CommandRequest\x1a\x15.game.CommandResponse\x12B\n" +
+	"\x0eHandleCommands\x12\x19.game.BatchCommandRequest\x1a\x15.game.CommandResponse2P\n" +
+	"\x10HeartbeatHandler\x12<\n" +
+	"\tHeartbeat\x12\x16.game.HeartbeatRequest\x1a\x17.game.HeartbeatResponseB-Z+technical-test-backend/internal/grpc/gamepbb\x06proto3"
+
+var (
+	file_game_proto_rawDescOnce sync.Once
+	file_game_proto_rawDescData []byte
+)
+
+func file_game_proto_rawDescGZIP() []byte {
+	file_game_proto_rawDescOnce.Do(func() {
+		file_game_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_game_proto_rawDesc), len(file_game_proto_rawDesc)))
+	})
+	return file_game_proto_rawDescData
+}
+
+var file_game_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_game_proto_goTypes = []any{
+	(*RegisterRequest)(nil),        // 0: game.RegisterRequest
+	(*RegisterResponse)(nil),       // 1: game.RegisterResponse
+	(*LoginRequest)(nil),           // 2: game.LoginRequest
+	(*LoginResponse)(nil),          // 3: game.LoginResponse
+	(*LogoutRequest)(nil),          // 4: game.LogoutRequest
+	(*LogoutResponse)(nil),         // 5: game.LogoutResponse
+	(*GetPlayerStateRequest)(nil),  // 6: game.GetPlayerStateRequest
+	(*GetPlayerStateResponse)(nil), // 7: game.GetPlayerStateResponse
+	(*PlayerState)(nil),            // 8: game.PlayerState
+	(*PersistentState)(nil),        // 9: game.PersistentState
+	(*Energy)(nil),                 // 10: game.Energy
+	(*LevelProgression)(nil),       // 11: game.LevelProgression
+	(*LevelStats)(nil),             // 12: game.LevelStats
+	(*SessionState)(nil),           // 13: game.SessionState
+	(*GetConfigsRequest)(nil),      // 14: game.GetConfigsRequest
+	(*GetConfigsResponse)(nil),     // 15: game.GetConfigsResponse
+	(*Configs)(nil),                // 16: game.Configs
+	(*LevelConfig)(nil),            // 17: game.LevelConfig
+	(*EnergyConfig)(nil),           // 18: game.EnergyConfig
+	(*OnboardingConfig)(nil),       // 19: game.OnboardingConfig
+	(*OnboardingVariant)(nil),      // 20: game.OnboardingVariant
+	(*Command)(nil),                // 21: game.Command
+	(*CommandRequest)(nil),         // 22: game.CommandRequest
+	(*BatchCommandRequest)(nil),    // 23: game.BatchCommandRequest
+	(*CommandResponse)(nil),        // 24: game.CommandResponse
+	(*HeartbeatRequest)(nil),       // 25: game.HeartbeatRequest
+	(*HeartbeatResponse)(nil),      // 26: game.HeartbeatResponse
+	(*timestamppb.Timestamp)(nil),  // 27: google.protobuf.Timestamp
+}
+var file_game_proto_depIdxs = []int32{
+	8,  // 0: game.GetPlayerStateResponse.player_state:type_name -> game.PlayerState
+	27, // 1: game.GetPlayerStateResponse.server_time:type_name -> google.protobuf.Timestamp
+	9,  // 2: game.PlayerState.persistent:type_name -> game.PersistentState
+	13, // 3: game.PlayerState.session:type_name -> game.SessionState
+	10, // 4: game.PersistentState.energy:type_name -> game.Energy
+	11, // 5: game.PersistentState.level_progression:type_name -> game.LevelProgression
+	27, // 6: game.Energy.last_recharge_at:type_name -> google.protobuf.Timestamp
+	12, // 7: game.LevelProgression.statistics:type_name -> game.LevelStats
+	16, // 8: game.GetConfigsResponse.configs:type_name -> game.Configs
+	17, // 9: game.Configs.levels:type_name -> game.LevelConfig
+	18, // 10: game.Configs.energy:type_name -> game.EnergyConfig
+	19, // 11: game.Configs.onboarding:type_name -> game.OnboardingConfig
+	20, // 12: game.OnboardingConfig.variants:type_name -> game.OnboardingVariant
+	12, // 13: game.OnboardingVariant.statistics:type_name -> game.LevelStats
+	21, // 14: game.CommandRequest.command:type_name -> game.Command
+	21, // 15: game.BatchCommandRequest.commands:type_name -> game.Command
+	13, // 16: game.CommandResponse.session:type_name -> game.SessionState
+	0,  // 17: game.AuthenticationHandler.Register:input_type -> game.RegisterRequest
+	2,  // 18: game.AuthenticationHandler.Login:input_type -> game.LoginRequest
+	4,  // 19: game.AuthenticationHandler.Logout:input_type -> game.LogoutRequest
+	6,  // 20: game.InitializationHandler.GetPlayerState:input_type -> game.GetPlayerStateRequest
+	14, // 21: game.InitializationHandler.GetConfigs:input_type -> game.GetConfigsRequest
+	22, // 22: game.CommandHandler.HandleCommand:input_type -> game.CommandRequest
+	23, // 23: game.CommandHandler.HandleCommands:input_type -> game.BatchCommandRequest
+	25, // 24: game.HeartbeatHandler.Heartbeat:input_type -> game.HeartbeatRequest
+	1,  // 25: game.AuthenticationHandler.Register:output_type -> game.RegisterResponse
+	3,  // 26: game.AuthenticationHandler.Login:output_type -> game.LoginResponse
+	5,  // 27: game.AuthenticationHandler.Logout:output_type -> game.LogoutResponse
+	7,  // 28: game.InitializationHandler.GetPlayerState:output_type -> game.GetPlayerStateResponse
+	15, // 29: game.InitializationHandler.GetConfigs:output_type -> game.GetConfigsResponse
+	24, // 30: game.CommandHandler.HandleCommand:output_type -> game.CommandResponse
+	24, // 31: game.CommandHandler.HandleCommands:output_type -> game.CommandResponse
+	26, // 32: game.HeartbeatHandler.Heartbeat:output_type -> game.HeartbeatResponse
+	25, // [25:33] is the sub-list for method output_type
+	17, // [17:25] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_game_proto_init() }
+func file_game_proto_init() {
+	if File_game_proto != nil {
+		return
+	}
+	file_game_proto_msgTypes[13].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_game_proto_rawDesc), len(file_game_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   27,
+			NumExtensions: 0,
+			NumServices:   4,
+		},
+		GoTypes:           file_game_proto_goTypes,
+		DependencyIndexes: file_game_proto_depIdxs,
+		MessageInfos:      file_game_proto_msgTypes,
+	}.Build()
+	File_game_proto = out.File
+	file_game_proto_goTypes = nil
+	file_game_proto_depIdxs = nil
+}
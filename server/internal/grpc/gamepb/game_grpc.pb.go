@@ -0,0 +1,585 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: game.proto
+
+package gamepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AuthenticationHandler_Register_FullMethodName = "/game.AuthenticationHandler/Register"
+	AuthenticationHandler_Login_FullMethodName    = "/game.AuthenticationHandler/Login"
+	AuthenticationHandler_Logout_FullMethodName   = "/game.AuthenticationHandler/Logout"
+)
+
+// AuthenticationHandlerClient is the client API for AuthenticationHandler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AuthenticationHandlerClient interface {
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// Login returns the session id in the x-session-id header metadata.
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+}
+
+type authenticationHandlerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthenticationHandlerClient(cc grpc.ClientConnInterface) AuthenticationHandlerClient {
+	return &authenticationHandlerClient{cc}
+}
+
+func (c *authenticationHandlerClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, AuthenticationHandler_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationHandlerClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, AuthenticationHandler_Login_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationHandlerClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, AuthenticationHandler_Logout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthenticationHandlerServer is the server API for AuthenticationHandler service.
+// All implementations must embed UnimplementedAuthenticationHandlerServer
+// for forward compatibility.
+type AuthenticationHandlerServer interface {
+	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
+	// Login returns the session id in the x-session-id header metadata.
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	mustEmbedUnimplementedAuthenticationHandlerServer()
+}
+
+// UnimplementedAuthenticationHandlerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAuthenticationHandlerServer struct{}
+
+func (UnimplementedAuthenticationHandlerServer) Register(context.Context, *RegisterRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedAuthenticationHandlerServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedAuthenticationHandlerServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedAuthenticationHandlerServer) mustEmbedUnimplementedAuthenticationHandlerServer() {}
+func (UnimplementedAuthenticationHandlerServer) testEmbeddedByValue()                               {}
+
+// UnsafeAuthenticationHandlerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuthenticationHandlerServer will
+// result in compilation errors.
+type UnsafeAuthenticationHandlerServer interface {
+	mustEmbedUnimplementedAuthenticationHandlerServer()
+}
+
+func RegisterAuthenticationHandlerServer(s grpc.ServiceRegistrar, srv AuthenticationHandlerServer) {
+	// If the following call pancis, it indicates UnimplementedAuthenticationHandlerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AuthenticationHandler_ServiceDesc, srv)
+}
+
+func _AuthenticationHandler_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationHandlerServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthenticationHandler_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationHandlerServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthenticationHandler_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationHandlerServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthenticationHandler_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationHandlerServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthenticationHandler_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationHandlerServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthenticationHandler_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationHandlerServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AuthenticationHandler_ServiceDesc is the grpc.ServiceDesc for AuthenticationHandler service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuthenticationHandler_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "game.AuthenticationHandler",
+	HandlerType: (*AuthenticationHandlerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _AuthenticationHandler_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _AuthenticationHandler_Login_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _AuthenticationHandler_Logout_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "game.proto",
+}
+
+const (
+	InitializationHandler_GetPlayerState_FullMethodName = "/game.InitializationHandler/GetPlayerState"
+	InitializationHandler_GetConfigs_FullMethodName     = "/game.InitializationHandler/GetConfigs"
+)
+
+// InitializationHandlerClient is the client API for InitializationHandler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InitializationHandlerClient interface {
+	GetPlayerState(ctx context.Context, in *GetPlayerStateRequest, opts ...grpc.CallOption) (*GetPlayerStateResponse, error)
+	GetConfigs(ctx context.Context, in *GetConfigsRequest, opts ...grpc.CallOption) (*GetConfigsResponse, error)
+}
+
+type initializationHandlerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInitializationHandlerClient(cc grpc.ClientConnInterface) InitializationHandlerClient {
+	return &initializationHandlerClient{cc}
+}
+
+func (c *initializationHandlerClient) GetPlayerState(ctx context.Context, in *GetPlayerStateRequest, opts ...grpc.CallOption) (*GetPlayerStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPlayerStateResponse)
+	err := c.cc.Invoke(ctx, InitializationHandler_GetPlayerState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *initializationHandlerClient) GetConfigs(ctx context.Context, in *GetConfigsRequest, opts ...grpc.CallOption) (*GetConfigsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetConfigsResponse)
+	err := c.cc.Invoke(ctx, InitializationHandler_GetConfigs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InitializationHandlerServer is the server API for InitializationHandler service.
+// All implementations must embed UnimplementedInitializationHandlerServer
+// for forward compatibility.
+type InitializationHandlerServer interface {
+	GetPlayerState(context.Context, *GetPlayerStateRequest) (*GetPlayerStateResponse, error)
+	GetConfigs(context.Context, *GetConfigsRequest) (*GetConfigsResponse, error)
+	mustEmbedUnimplementedInitializationHandlerServer()
+}
+
+// UnimplementedInitializationHandlerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInitializationHandlerServer struct{}
+
+func (UnimplementedInitializationHandlerServer) GetPlayerState(context.Context, *GetPlayerStateRequest) (*GetPlayerStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlayerState not implemented")
+}
+func (UnimplementedInitializationHandlerServer) GetConfigs(context.Context, *GetConfigsRequest) (*GetConfigsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigs not implemented")
+}
+func (UnimplementedInitializationHandlerServer) mustEmbedUnimplementedInitializationHandlerServer() {}
+func (UnimplementedInitializationHandlerServer) testEmbeddedByValue()                               {}
+
+// UnsafeInitializationHandlerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InitializationHandlerServer will
+// result in compilation errors.
+type UnsafeInitializationHandlerServer interface {
+	mustEmbedUnimplementedInitializationHandlerServer()
+}
+
+func RegisterInitializationHandlerServer(s grpc.ServiceRegistrar, srv InitializationHandlerServer) {
+	// If the following call pancis, it indicates UnimplementedInitializationHandlerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InitializationHandler_ServiceDesc, srv)
+}
+
+func _InitializationHandler_GetPlayerState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlayerStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InitializationHandlerServer).GetPlayerState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InitializationHandler_GetPlayerState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InitializationHandlerServer).GetPlayerState(ctx, req.(*GetPlayerStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InitializationHandler_GetConfigs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InitializationHandlerServer).GetConfigs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InitializationHandler_GetConfigs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InitializationHandlerServer).GetConfigs(ctx, req.(*GetConfigsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InitializationHandler_ServiceDesc is the grpc.ServiceDesc for InitializationHandler service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InitializationHandler_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "game.InitializationHandler",
+	HandlerType: (*InitializationHandlerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPlayerState",
+			Handler:    _InitializationHandler_GetPlayerState_Handler,
+		},
+		{
+			MethodName: "GetConfigs",
+			Handler:    _InitializationHandler_GetConfigs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "game.proto",
+}
+
+const (
+	CommandHandler_HandleCommand_FullMethodName  = "/game.CommandHandler/HandleCommand"
+	CommandHandler_HandleCommands_FullMethodName = "/game.CommandHandler/HandleCommands"
+)
+
+// CommandHandlerClient is the client API for CommandHandler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CommandHandlerClient interface {
+	HandleCommand(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	// HandleCommands executes a batch atomically. The index of the failing
+	// command is in the "index" metadata of the ErrorInfo detail.
+	HandleCommands(ctx context.Context, in *BatchCommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+}
+
+type commandHandlerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCommandHandlerClient(cc grpc.ClientConnInterface) CommandHandlerClient {
+	return &commandHandlerClient{cc}
+}
+
+func (c *commandHandlerClient) HandleCommand(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, CommandHandler_HandleCommand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandHandlerClient) HandleCommands(ctx context.Context, in *BatchCommandRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, CommandHandler_HandleCommands_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CommandHandlerServer is the server API for CommandHandler service.
+// All implementations must embed UnimplementedCommandHandlerServer
+// for forward compatibility.
+type CommandHandlerServer interface {
+	HandleCommand(context.Context, *CommandRequest) (*CommandResponse, error)
+	// HandleCommands executes a batch atomically. The index of the failing
+	// command is in the "index" metadata of the ErrorInfo detail.
+	HandleCommands(context.Context, *BatchCommandRequest) (*CommandResponse, error)
+	mustEmbedUnimplementedCommandHandlerServer()
+}
+
+// UnimplementedCommandHandlerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCommandHandlerServer struct{}
+
+func (UnimplementedCommandHandlerServer) HandleCommand(context.Context, *CommandRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleCommand not implemented")
+}
+func (UnimplementedCommandHandlerServer) HandleCommands(context.Context, *BatchCommandRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleCommands not implemented")
+}
+func (UnimplementedCommandHandlerServer) mustEmbedUnimplementedCommandHandlerServer() {}
+func (UnimplementedCommandHandlerServer) testEmbeddedByValue()                        {}
+
+// UnsafeCommandHandlerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CommandHandlerServer will
+// result in compilation errors.
+type UnsafeCommandHandlerServer interface {
+	mustEmbedUnimplementedCommandHandlerServer()
+}
+
+func RegisterCommandHandlerServer(s grpc.ServiceRegistrar, srv CommandHandlerServer) {
+	// If the following call pancis, it indicates UnimplementedCommandHandlerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CommandHandler_ServiceDesc, srv)
+}
+
+func _CommandHandler_HandleCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandHandlerServer).HandleCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandHandler_HandleCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandHandlerServer).HandleCommand(ctx, req.(*CommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandHandler_HandleCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandHandlerServer).HandleCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandHandler_HandleCommands_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandHandlerServer).HandleCommands(ctx, req.(*BatchCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CommandHandler_ServiceDesc is the grpc.ServiceDesc for CommandHandler service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CommandHandler_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "game.CommandHandler",
+	HandlerType: (*CommandHandlerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "HandleCommand",
+			Handler:    _CommandHandler_HandleCommand_Handler,
+		},
+		{
+			MethodName: "HandleCommands",
+			Handler:    _CommandHandler_HandleCommands_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "game.proto",
+}
+
+const (
+	HeartbeatHandler_Heartbeat_FullMethodName = "/game.HeartbeatHandler/Heartbeat"
+)
+
+// HeartbeatHandlerClient is the client API for HeartbeatHandler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HeartbeatHandlerClient interface {
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+}
+
+type heartbeatHandlerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHeartbeatHandlerClient(cc grpc.ClientConnInterface) HeartbeatHandlerClient {
+	return &heartbeatHandlerClient{cc}
+}
+
+func (c *heartbeatHandlerClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, HeartbeatHandler_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HeartbeatHandlerServer is the server API for HeartbeatHandler service.
+// All implementations must embed UnimplementedHeartbeatHandlerServer
+// for forward compatibility.
+type HeartbeatHandlerServer interface {
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	mustEmbedUnimplementedHeartbeatHandlerServer()
+}
+
+// UnimplementedHeartbeatHandlerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHeartbeatHandlerServer struct{}
+
+func (UnimplementedHeartbeatHandlerServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedHeartbeatHandlerServer) mustEmbedUnimplementedHeartbeatHandlerServer() {}
+func (UnimplementedHeartbeatHandlerServer) testEmbeddedByValue()                          {}
+
+// UnsafeHeartbeatHandlerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HeartbeatHandlerServer will
+// result in compilation errors.
+type UnsafeHeartbeatHandlerServer interface {
+	mustEmbedUnimplementedHeartbeatHandlerServer()
+}
+
+func RegisterHeartbeatHandlerServer(s grpc.ServiceRegistrar, srv HeartbeatHandlerServer) {
+	// If the following call pancis, it indicates UnimplementedHeartbeatHandlerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HeartbeatHandler_ServiceDesc, srv)
+}
+
+func _HeartbeatHandler_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeartbeatHandlerServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeartbeatHandler_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeartbeatHandlerServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HeartbeatHandler_ServiceDesc is the grpc.ServiceDesc for HeartbeatHandler service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HeartbeatHandler_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "game.HeartbeatHandler",
+	HandlerType: (*HeartbeatHandlerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Heartbeat",
+			Handler:    _HeartbeatHandler_Heartbeat_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "game.proto",
+}
@@ -0,0 +1,4 @@
+// Package gamepb holds the code generated from proto/game.proto.
+package gamepb
+
+//go:generate protoc --proto_path=../../../proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative game.proto
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+	"technical-test-backend/internal/metrics"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsInterceptor is the gRPC equivalent of the HTTP MetricsMiddleware. It
+// counts the calls per method and status code, and records their latency per
+// method.
+type MetricsInterceptor struct {
+	calls   *metrics.Counter
+	latency *metrics.Histogram
+}
+
+func NewMetricsInterceptor(registry *metrics.Registry) *MetricsInterceptor {
+	return &MetricsInterceptor{
+		calls:   registry.NewCounter("grpc_requests_total", "gRPC calls handled, by method and status code.", "method", "code"),
+		latency: registry.NewHistogram("grpc_request_duration_seconds", "Latency of the gRPC calls, by method.", metrics.DefaultBuckets, "method"),
+	}
+}
+
+func (i *MetricsInterceptor) Unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	res, err := handler(ctx, req)
+
+	i.calls.Inc(info.FullMethod, status.Code(err).String())
+	i.latency.Observe(time.Since(start).Seconds(), info.FullMethod)
+
+	return res, err
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var ErrInvalidAdminToken = errors.New("invalid admin token")
+
+// AdminMiddleware restricts the endpoint to the holders of the admin token,
+// sent as an "Authorization: Bearer" header.
+type AdminMiddleware struct {
+	token []byte
+}
+
+func NewAdminMiddleware(token string) *AdminMiddleware {
+	return &AdminMiddleware{
+		token: []byte(token),
+	}
+}
+
+func (m *AdminMiddleware) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || len(m.token) == 0 || subtle.ConstantTimeCompare([]byte(token), m.token) != 1 {
+			WriteError(w, http.StatusUnauthorized, ErrInvalidAdminToken.Error())
+			return
+		}
+
+		next(w, r)
+	}
+}
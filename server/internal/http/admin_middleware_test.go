@@ -0,0 +1,44 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "accepts the admin token", token: "admin-token", authorization: "Bearer admin-token", expectedStatus: http.StatusOK},
+		{name: "rejects another token", token: "admin-token", authorization: "Bearer other-token", expectedStatus: http.StatusUnauthorized},
+		{name: "rejects a missing header", token: "admin-token", authorization: "", expectedStatus: http.StatusUnauthorized},
+		{name: "rejects another scheme", token: "admin-token", authorization: "Basic admin-token", expectedStatus: http.StatusUnauthorized},
+		{name: "rejects everything without a token", token: "", authorization: "Bearer ", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAdminMiddleware(tt.token).Middleware(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -5,8 +5,6 @@
 	"log"
 	"net/http"
 	"technical-test-backend/internal/sessions"
-
-	"github.com/google/uuid"
 )
 
 var (
@@ -14,6 +12,15 @@
 	ErrInvalidOrExpiredSession = errors.New("invalid or expired session")
 )
 
+// endedSessionStatuses tell the client why its session ended, so it doesn't
+// log in again on its own when it was replaced or revoked.
+var endedSessionStatuses = StatusCodes{
+	sessions.ErrorCodeSessionExpired:   http.StatusUnauthorized,
+	sessions.ErrorCodeSessionReplaced:  http.StatusUnauthorized,
+	sessions.ErrorCodeSessionLoggedOut: http.StatusUnauthorized,
+	sessions.ErrorCodeSessionRevoked:   http.StatusUnauthorized,
+}
+
 type AuthMiddleware struct {
 	sessionPool sessions.Pool
 }
@@ -32,23 +39,41 @@ func (m *AuthMiddleware) Middleware(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		if _, err := uuid.Parse(sessionID); err != nil {
-			WriteError(w, http.StatusUnauthorized, ErrInvalidSessionID.Error())
-			return
-		}
-
-		accountID, authenticated := m.sessionPool.GetAccountID(sessionID)
+		accountID, authenticated := m.sessionPool.GetAccountID(r.Context(), sessionID)
 		if !authenticated {
-			WriteError(w, http.StatusUnauthorized, ErrInvalidOrExpiredSession.Error())
+			m.writeUnauthenticated(w, r, sessionID)
 			return
 		}
 
-		if err := m.sessionPool.UpdateActivity(sessionID); err != nil {
+		if err := m.sessionPool.UpdateActivity(r.Context(), sessionID); err != nil {
 			log.Printf("Warning: Failed to update session activity: %v", err)
 		}
 
 		r.Header.Set("X-Account-ID", accountID)
+		setLogAccountID(r.Context(), accountID)
 
 		next(w, r)
 	}
 }
+
+func (m *AuthMiddleware) writeUnauthenticated(w http.ResponseWriter, r *http.Request, sessionID string) {
+	tombstone, found := m.sessionPool.GetTombstone(r.Context(), sessionID)
+	// The pool reports sessions as missing once the request context is done.
+	if err := r.Context().Err(); err != nil {
+		WriteMappedError(w, err, nil)
+		return
+	}
+
+	if !found {
+		WriteError(w, http.StatusUnauthorized, ErrInvalidOrExpiredSession.Error())
+		return
+	}
+
+	endedSessionErr := tombstone.Err()
+	if endedSessionErr == nil {
+		WriteError(w, http.StatusUnauthorized, ErrInvalidOrExpiredSession.Error())
+		return
+	}
+
+	WriteMappedError(w, endedSessionErr, endedSessionStatuses)
+}
@@ -0,0 +1,98 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/sessions/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	sessionPool := memory.NewSessionPool(memory.SessionPoolConfig{TTL: time.Minute, TombstoneTTL: time.Minute})
+	middleware := NewAuthMiddleware(sessionPool)
+
+	handler := middleware.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, http.StatusOK, r.Header.Get("X-Account-ID"))
+	})
+
+	replaced, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	loggedOut, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	sessionPool.RemoveSession(context.Background(), loggedOut.ID)
+
+	revoked, err := sessionPool.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+	sessionPool.RevokeSessions(context.Background(), "account-2")
+
+	live, err := sessionPool.CreateSession(context.Background(), "account-3", nil)
+	require.NoError(t, err)
+
+	table := map[string]struct {
+		sessionID      string
+		expectedStatus int
+		expectedCode   string
+	}{
+		"live session":       {live.ID, http.StatusOK, ""},
+		"missing header":     {"", http.StatusUnauthorized, ""},
+		"unknown session":    {"unknown", http.StatusUnauthorized, ""},
+		"replaced session":   {replaced.ID, http.StatusUnauthorized, sessions.ErrorCodeSessionReplaced},
+		"logged out session": {loggedOut.ID, http.StatusUnauthorized, sessions.ErrorCodeSessionLoggedOut},
+		"revoked session":    {revoked.ID, http.StatusUnauthorized, sessions.ErrorCodeSessionRevoked},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/", nil)
+			request.Header.Set("X-Session-ID", row.sessionID)
+			recorder := httptest.NewRecorder()
+
+			handler(recorder, request)
+
+			assert.Equal(t, row.expectedStatus, recorder.Code)
+			if row.expectedStatus == http.StatusOK {
+				return
+			}
+
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, row.expectedCode, response.Code)
+			assert.NotEmpty(t, response.Message)
+		})
+	}
+}
+
+func TestAuthMiddleware_WithDoneContext_ShouldReportTimeout(t *testing.T) {
+	sessionPool := memory.NewSessionPool(memory.SessionPoolConfig{TTL: time.Minute})
+	handler := NewAuthMiddleware(sessionPool).Middleware(func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, http.StatusOK, r.Header.Get("X-Account-ID"))
+	})
+
+	session, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	request := httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx)
+	request.Header.Set("X-Session-ID", session.ID)
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, request)
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, ErrorCodeTimeout, response.Code)
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"technical-test-backend/internal/errors"
+)
+
+const ErrorCodeTimeout = "TIMEOUT"
+
+type ErrorResponse struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// StatusCodes maps error codes to the status code of their responses.
+type StatusCodes map[string]int
+
+// WriteMappedError writes the response of err, as mapped by MapError.
+func WriteMappedError(w http.ResponseWriter, err error, statusCodes StatusCodes) {
+	statusCode, response := MapError(err, statusCodes)
+	WriteJSON(w, statusCode, response)
+}
+
+// MapError returns the status code of the code carried by err, along with the
+// code and message of the coded error. Errors caused by the end of the
+// request context are reported as timeouts, and the others as a 500 with the
+// full error message.
+func MapError(err error, statusCodes StatusCodes) (int, ErrorResponse) {
+	if coded, ok := errors.AsCoded(err); ok {
+		if statusCode, mapped := statusCodes[coded.Code]; mapped {
+			return statusCode, ErrorResponse{Code: coded.Code, Message: coded.Error()}
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return http.StatusServiceUnavailable, ErrorResponse{Code: ErrorCodeTimeout, Message: err.Error()}
+	}
+
+	return http.StatusInternalServerError, ErrorResponse{Message: err.Error()}
+}
@@ -0,0 +1,74 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"technical-test-backend/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errTestCoded    = errors.NewCoded("CODED_FAILURE", "coded failure")
+	errTestUnmapped = errors.NewCoded("UNMAPPED_FAILURE", "unmapped failure")
+)
+
+func TestWriteMappedError(t *testing.T) {
+	statusCodes := StatusCodes{
+		"CODED_FAILURE": http.StatusUnprocessableEntity,
+	}
+
+	table := map[string]struct {
+		err              error
+		expectedStatus   int
+		expectedResponse ErrorResponse
+	}{
+		"coded error uses its own code and message": {
+			err:              errors.Wrap(errTestCoded, errors.New("wrapper")),
+			expectedStatus:   http.StatusUnprocessableEntity,
+			expectedResponse: ErrorResponse{Code: "CODED_FAILURE", Message: "coded failure"},
+		},
+		"detailed coded error keeps its code": {
+			err:              errTestCoded.Detailf("attempt %d", 2),
+			expectedStatus:   http.StatusUnprocessableEntity,
+			expectedResponse: ErrorResponse{Code: "CODED_FAILURE", Message: "attempt 2: coded failure"},
+		},
+		"coded error without status falls back to internal error": {
+			err:              errTestUnmapped,
+			expectedStatus:   http.StatusInternalServerError,
+			expectedResponse: ErrorResponse{Message: "unmapped failure"},
+		},
+		"expired context is reported as a timeout": {
+			err:              fmt.Errorf("failed to get account: %w", context.DeadlineExceeded),
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedResponse: ErrorResponse{Code: ErrorCodeTimeout, Message: "failed to get account: context deadline exceeded"},
+		},
+		"unmapped error falls back to internal error": {
+			err:              errors.New("unexpected failure"),
+			expectedStatus:   http.StatusInternalServerError,
+			expectedResponse: ErrorResponse{Message: "unexpected failure"},
+		},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			WriteMappedError(recorder, row.err, statusCodes)
+
+			assert.Equal(t, row.expectedStatus, recorder.Code)
+			var response ErrorResponse
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+			assert.Equal(t, row.expectedResponse, response)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// InFlightMiddleware tracks the requests being handled, so they can be waited
+// for once their connections are closed: http.Server.Close doesn't wait for
+// the handlers to return.
+type InFlightMiddleware struct {
+	mutex sync.Mutex
+	count int
+	// idle is closed once count drops back to zero.
+	idle chan struct{}
+}
+
+func NewInFlightMiddleware() *InFlightMiddleware {
+	return &InFlightMiddleware{}
+}
+
+func (m *InFlightMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.begin()
+		defer m.end()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Wait returns once no request is being handled, or with the error of ctx once
+// it's done.
+func (m *InFlightMiddleware) Wait(ctx context.Context) error {
+	m.mutex.Lock()
+	if m.count == 0 {
+		m.mutex.Unlock()
+		return nil
+	}
+	idle := m.idle
+	m.mutex.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *InFlightMiddleware) begin() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.count == 0 {
+		m.idle = make(chan struct{})
+	}
+	m.count++
+}
+
+func (m *InFlightMiddleware) end() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.count--
+	if m.count == 0 {
+		close(m.idle)
+	}
+}
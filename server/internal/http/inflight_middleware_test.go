@@ -0,0 +1,50 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInFlightMiddleware(t *testing.T) {
+	t.Run("waits for the requests being handled", func(t *testing.T) {
+		middleware := NewInFlightMiddleware()
+		started, release := make(chan struct{}), make(chan struct{})
+		handler := middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}))
+
+		done := make(chan struct{})
+		go func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+			close(done)
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, middleware.Wait(ctx), context.DeadlineExceeded)
+
+		close(release)
+		<-done
+		assert.NoError(t, middleware.Wait(context.Background()))
+	})
+
+	t.Run("returns at once without requests", func(t *testing.T) {
+		middleware := NewInFlightMiddleware()
+		handler := middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.NoError(t, middleware.Wait(ctx))
+	})
+}
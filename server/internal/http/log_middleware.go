@@ -2,51 +2,243 @@
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+// RequestIDHeader carries the ID of the request, taken from the client when it
+// sends one and generated otherwise.
+const RequestIDHeader = "X-Request-ID"
+
+const (
+	// maxRequestIDSize bounds the request IDs accepted from clients.
+	maxRequestIDSize = 128
+	// maxLoggedBodySize bounds the bodies kept for the logs. Larger ones are
+	// only logged by size, as they can't be redacted once truncated.
+	maxLoggedBodySize = 4096
+	redacted          = "<redacted>"
+)
+
+type LogConfig struct {
+	// Bodies adds the headers and JSON bodies of the requests and responses to
+	// the logs. Event streams are never logged.
+	Bodies bool
+	// Redact lists the headers and JSON fields logged as redacted, matched
+	// case-insensitively.
+	Redact []string
+}
+
+type requestIDContextKey struct{}
+
+// requestLog collects the attributes known only deeper in the handler chain.
+type requestLog struct {
+	accountID string
+}
+
+type requestLogContextKey struct{}
+
+// RequestID returns the ID of a request logged by LogMiddleware.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// setLogAccountID adds the account ID to the log of the request.
+func setLogAccountID(ctx context.Context, accountID string) {
+	if entry, ok := ctx.Value(requestLogContextKey{}).(*requestLog); ok {
+		entry.accountID = accountID
+	}
+}
+
+// LogMiddleware logs a line per request with its ID, route, status and
+// latency, along with the account ID of authenticated requests.
+type LogMiddleware struct {
+	logger *slog.Logger
+	config LogConfig
+	redact map[string]bool
+}
+
+func NewLogMiddleware(logger *slog.Logger, config LogConfig) *LogMiddleware {
+	redact := make(map[string]bool, len(config.Redact))
+	for _, name := range config.Redact {
+		redact[strings.ToLower(name)] = true
+	}
+
+	return &LogMiddleware{
+		logger: logger,
+		config: config,
+		redact: redact,
+	}
+}
+
+func (m *LogMiddleware) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDSize {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		entry := &requestLog{}
+		ctx := context.WithValue(r.Context(), requestIDContextKey{}, requestID)
+		ctx = context.WithValue(ctx, requestLogContextKey{}, entry)
+		r = r.WithContext(ctx)
+
+		recorder := &ResponseRecorder{ResponseWriter: w}
+		var requestBody *bodyBuffer
+		if m.config.Bodies {
+			requestBody = &bodyBuffer{}
+			r.Body = readCloser{Reader: io.TeeReader(r.Body, requestBody), Closer: r.Body}
+			recorder.body = &bodyBuffer{}
+		}
+
+		next(recorder, r)
+
+		attrs := []slog.Attr{
+			slog.String("request_id", requestID),
+			slog.String("method", r.Method),
+			slog.String("route", r.Pattern),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", recorder.StatusCode()),
+			slog.Duration("latency", time.Since(start)),
+		}
+		if entry.accountID != "" {
+			attrs = append(attrs, slog.String("account_id", entry.accountID))
+		}
+		if m.config.Bodies {
+			attrs = append(attrs,
+				slog.Any("request_headers", m.redactHeaders(r.Header)),
+				slog.Any("request_body", m.redactBody(requestBody)),
+				slog.Any("response_headers", m.redactHeaders(recorder.Header())),
+				slog.Any("response_body", m.redactBody(recorder.body)),
+			)
+		}
+
+		level := slog.LevelInfo
+		if recorder.StatusCode() >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		m.logger.LogAttrs(ctx, level, "request", attrs...)
+	}
+}
+
+func (m *LogMiddleware) redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if m.redact[strings.ToLower(name)] {
+			headers[name] = redacted
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// redactBody returns the body with the redacted fields replaced. Bodies that
+// aren't complete JSON documents are only logged by size.
+func (m *LogMiddleware) redactBody(body *bodyBuffer) any {
+	if body == nil || body.size == 0 {
+		return nil
+	}
+
+	var document any
+	if body.truncated || json.Unmarshal(body.Bytes(), &document) != nil {
+		return map[string]int{"size": body.size}
+	}
+
+	return m.redactValue(document)
+}
+
+func (m *LogMiddleware) redactValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if m.redact[strings.ToLower(key)] {
+				value[key] = redacted
+				continue
+			}
+			value[key] = m.redactValue(field)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = m.redactValue(item)
+		}
+	}
+	return value
+}
+
+// ResponseRecorder records the status of a response, and its body when body
+// is set. It unwraps to the original writer, so http.ResponseController can
+// still flush it.
 type ResponseRecorder struct {
 	http.ResponseWriter
 	statusCode int
-	body       *bytes.Buffer
+	body       *bodyBuffer
 }
 
 func (r *ResponseRecorder) WriteHeader(statusCode int) {
-	r.statusCode = statusCode
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+		// Streams never end, their body would only be logged by size anyway.
+		if strings.HasPrefix(r.Header().Get("Content-Type"), "text/event-stream") {
+			r.body = nil
+		}
+	}
 	r.ResponseWriter.WriteHeader(statusCode)
 }
 
 func (r *ResponseRecorder) Write(b []byte) (int, error) {
-	r.body.Write(b)
+	if r.statusCode == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if r.body != nil {
+		r.body.Write(b)
+	}
 	return r.ResponseWriter.Write(b)
 }
 
-func (r *ResponseRecorder) GetBody() string {
-	return r.body.String()
+func (r *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
 }
 
-func LogMiddleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("Request: %s %s", r.Method, r.URL.Path)
-		log.Printf("Request Headers: %v", r.Header)
-
-		var requestBody bytes.Buffer
-		teeReader := io.TeeReader(r.Body, &requestBody)
-		r.Body = io.NopCloser(teeReader)
-
-		recorder := &ResponseRecorder{
-			ResponseWriter: w,
-			body:           &bytes.Buffer{},
-		}
-
-		next(recorder, r)
+// StatusCode returns the status written, which is 200 when the handler wrote
+// nothing.
+func (r *ResponseRecorder) StatusCode() int {
+	if r.statusCode == 0 {
+		return http.StatusOK
+	}
+	return r.statusCode
+}
 
-		log.Printf("Request Body: %s", requestBody.String())
+// bodyBuffer keeps the first maxLoggedBodySize bytes written to it, and
+// counts the others.
+type bodyBuffer struct {
+	bytes.Buffer
+	size      int
+	truncated bool
+}
 
-		log.Printf("Response Status: %d", recorder.statusCode)
-		log.Printf("Response Headers: %v", recorder.Header())
-		log.Printf("Response Body: %s", recorder.GetBody())
+func (b *bodyBuffer) Write(p []byte) (int, error) {
+	b.size += len(p)
+	kept := p
+	if remaining := maxLoggedBodySize - b.Buffer.Len(); remaining < len(p) {
+		b.truncated = true
+		kept = p[:remaining]
 	}
+	b.Buffer.Write(kept)
+	return len(p), nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
 }
@@ -0,0 +1,103 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveLogged(t *testing.T, config LogConfig, request *http.Request, handler http.HandlerFunc) (*httptest.ResponseRecorder, map[string]any) {
+	var output bytes.Buffer
+	middleware := NewLogMiddleware(slog.New(slog.NewJSONHandler(&output, nil)), config)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /accounts/{id}", middleware.Middleware(handler))
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(output.Bytes(), &record))
+	return recorder, record
+}
+
+func TestLogMiddleware_ShouldLogRequest(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/accounts/1", strings.NewReader(`{"secret":"hunter2"}`))
+	request.Header.Set(RequestIDHeader, "request-1")
+	request.Header.Set("X-Session-ID", "session-1")
+
+	recorder, record := serveLogged(t, LogConfig{Redact: []string{"X-Session-ID", "secret"}}, request, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "request-1", RequestID(r.Context()))
+		setLogAccountID(r.Context(), "account-1")
+		WriteJSON(w, http.StatusCreated, map[string]string{"secret": "hunter2"})
+	})
+
+	assert.Equal(t, "request-1", recorder.Header().Get(RequestIDHeader))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "request-1", record["request_id"])
+	assert.Equal(t, "POST /accounts/{id}", record["route"])
+	assert.Equal(t, "/accounts/1", record["path"])
+	assert.Equal(t, float64(http.StatusCreated), record["status"])
+	assert.Equal(t, "account-1", record["account_id"])
+	assert.Contains(t, record, "latency")
+	assert.NotContains(t, record, "request_headers")
+	assert.NotContains(t, record, "request_body")
+	assert.NotContains(t, record, "response_body")
+}
+
+func TestLogMiddleware_WithBodies_ShouldRedactSecrets(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/accounts/1", strings.NewReader(`{"name":"player","secret":"hunter2"}`))
+	request.Header.Set("X-Session-ID", "session-1")
+
+	recorder, record := serveLogged(t, LogConfig{Bodies: true, Redact: []string{"x-session-id", "Secret", "token"}}, request, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "hunter2", body["secret"])
+		WriteJSON(w, http.StatusOK, map[string]any{"sessions": []map[string]string{{"id": "1", "token": "abc"}}})
+	})
+
+	assert.Equal(t, `{"sessions":[{"id":"1","token":"abc"}]}`, strings.TrimSpace(recorder.Body.String()))
+	assert.Equal(t, map[string]any{"X-Session-Id": redacted}, record["request_headers"])
+	assert.Equal(t, map[string]any{"name": "player", "secret": redacted}, record["request_body"])
+	assert.Equal(t, map[string]any{"sessions": []any{map[string]any{"id": "1", "token": redacted}}}, record["response_body"])
+	assert.Equal(t, "application/json", record["response_headers"].(map[string]any)["Content-Type"])
+}
+
+func TestLogMiddleware_WithBodies_ShouldOnlyLogSizeOfLargeOrRawBodies(t *testing.T) {
+	large := `{"secret":"` + strings.Repeat("a", maxLoggedBodySize) + `"}`
+	request := httptest.NewRequest(http.MethodPost, "/accounts/1", strings.NewReader(large))
+
+	_, record := serveLogged(t, LogConfig{Bodies: true, Redact: []string{"secret"}}, request, func(w http.ResponseWriter, r *http.Request) {
+		_, err := r.Body.Read(make([]byte, len(large)+1))
+		require.NoError(t, err)
+		w.Write([]byte("secret"))
+	})
+
+	assert.Equal(t, map[string]any{"size": float64(len(large))}, record["request_body"])
+	assert.Equal(t, map[string]any{"size": float64(len("secret"))}, record["response_body"])
+}
+
+func TestLogMiddleware_WithServerError_ShouldLogError(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/accounts/1", nil)
+	request.Header.Set(RequestIDHeader, strings.Repeat("a", maxRequestIDSize+1))
+
+	recorder, record := serveLogged(t, LogConfig{}, request, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, float64(http.StatusInternalServerError), record["status"])
+	assert.NotContains(t, record, "account_id")
+	requestID := recorder.Header().Get(RequestIDHeader)
+	assert.Len(t, requestID, 36)
+	assert.Equal(t, requestID, record["request_id"])
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"technical-test-backend/internal/metrics"
+	"time"
+)
+
+// MetricsMiddleware counts the requests per route and status, and records
+// their latency per route.
+type MetricsMiddleware struct {
+	requests *metrics.Counter
+	latency  *metrics.Histogram
+}
+
+func NewMetricsMiddleware(registry *metrics.Registry) *MetricsMiddleware {
+	return &MetricsMiddleware{
+		requests: registry.NewCounter("http_requests_total", "HTTP requests handled, by route and status.", "route", "status"),
+		latency:  registry.NewHistogram("http_request_duration_seconds", "Latency of the HTTP requests, by route.", metrics.DefaultBuckets, "route"),
+	}
+}
+
+func (m *MetricsMiddleware) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &ResponseRecorder{ResponseWriter: w}
+
+		next(recorder, r)
+
+		m.requests.Inc(r.Pattern, strconv.Itoa(recorder.StatusCode()))
+		m.latency.Observe(time.Since(start).Seconds(), r.Pattern)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"technical-test-backend/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware_ShouldCountRequestsPerRoute(t *testing.T) {
+	registry := metrics.NewRegistry()
+	middleware := NewMetricsMiddleware(registry)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /accounts/{id}", middleware.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	for _, path := range []string{"/accounts/1", "/accounts/2", "/accounts/missing"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var output strings.Builder
+	_, err := registry.WriteTo(&output)
+	require.NoError(t, err)
+
+	assert.Contains(t, output.String(), `http_requests_total{route="GET /accounts/{id}",status="200"} 2`)
+	assert.Contains(t, output.String(), `http_requests_total{route="GET /accounts/{id}",status="404"} 1`)
+	assert.Contains(t, output.String(), `http_request_duration_seconds_count{route="GET /accounts/{id}"} 3`)
+}
@@ -0,0 +1,24 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// TimeoutMiddleware bounds the request context of the endpoint, which the
+// handlers pass down to the storages. Zero disables it. Handlers report the
+// expired context as an ErrorCodeTimeout error, unlike http.TimeoutHandler it
+// doesn't buffer the response.
+func TimeoutMiddleware(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	if timeout <= 0 {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next(w, r.WithContext(ctx))
+	}
+}
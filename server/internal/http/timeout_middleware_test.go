@@ -0,0 +1,37 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	t.Run("bounds the request context", func(t *testing.T) {
+		var deadline time.Time
+		handler := TimeoutMiddleware(time.Minute, func(w http.ResponseWriter, r *http.Request) {
+			deadline, _ = r.Context().Deadline()
+		})
+
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	})
+
+	t.Run("zero disables it", func(t *testing.T) {
+		hasDeadline := true
+		handler := TimeoutMiddleware(0, func(w http.ResponseWriter, r *http.Request) {
+			_, hasDeadline = r.Context().Deadline()
+		})
+
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+		assert.False(t, hasDeadline)
+	})
+}
@@ -14,7 +14,7 @@ func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 func WriteError(w http.ResponseWriter, statusCode int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+	_ = json.NewEncoder(w).Encode(ErrorResponse{Message: message})
 }
 
 func DecodeJSON(r *http.Request, v interface{}) error {
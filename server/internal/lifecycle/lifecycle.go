@@ -0,0 +1,80 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"technical-test-backend/internal/errors"
+)
+
+// Hook starts and stops a component. Either function may be nil, e.g. for a
+// component that is ready once created but must be closed.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle starts the hooks in the order they were appended, and stops them
+// in reverse order, so a component is stopped before the ones it depends on.
+type Lifecycle struct {
+	mutex   sync.Mutex
+	hooks   []Hook
+	started int
+}
+
+func New() *Lifecycle {
+	return &Lifecycle{}
+}
+
+func (l *Lifecycle) Append(hook Hook) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.hooks = append(l.hooks, hook)
+}
+
+// Start runs the OnStart hooks not started yet. When one fails, the hooks
+// started so far are stopped and its error is returned.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for l.started < len(l.hooks) {
+		hook := l.hooks[l.started]
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				startErr := fmt.Errorf("failed to start %s: %w", hook.Name, err)
+				return errors.Join(startErr, l.stop(ctx))
+			}
+		}
+		l.started++
+	}
+
+	return nil
+}
+
+// Stop runs the OnStop hooks of the started components in reverse order. Every
+// hook is run even when some fail, and all their errors are returned.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.stop(ctx)
+}
+
+func (l *Lifecycle) stop(ctx context.Context) error {
+	var errs []error
+	for ; l.started > 0; l.started-- {
+		hook := l.hooks[l.started-1]
+		if hook.OnStop == nil {
+			continue
+		}
+
+		if err := hook.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", hook.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
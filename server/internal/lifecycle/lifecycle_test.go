@@ -0,0 +1,79 @@
+//go:build unit
+// +build unit
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"technical-test-backend/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestHook = errors.New("hook failure")
+
+// recordingHook appends its start and stop to calls, failing them when asked.
+func recordingHook(name string, calls *[]string, failStart bool, failStop bool) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			*calls = append(*calls, "start "+name)
+			if failStart {
+				return errTestHook
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			*calls = append(*calls, "stop "+name)
+			if failStop {
+				return errTestHook
+			}
+			return nil
+		},
+	}
+}
+
+func TestLifecycle_ShouldStopInReverseOrder(t *testing.T) {
+	var calls []string
+	lc := New()
+	lc.Append(recordingHook("storage", &calls, false, false))
+	lc.Append(Hook{Name: "no hooks"})
+	lc.Append(recordingHook("server", &calls, false, false))
+
+	require.NoError(t, lc.Start(context.Background()))
+	require.NoError(t, lc.Stop(context.Background()))
+	require.NoError(t, lc.Stop(context.Background()))
+
+	assert.Equal(t, []string{"start storage", "start server", "stop server", "stop storage"}, calls)
+}
+
+func TestLifecycle_WhenStartFails_ShouldStopStartedHooks(t *testing.T) {
+	var calls []string
+	lc := New()
+	lc.Append(recordingHook("storage", &calls, false, false))
+	lc.Append(recordingHook("server", &calls, true, false))
+	lc.Append(recordingHook("unreached", &calls, false, false))
+
+	err := lc.Start(context.Background())
+
+	assert.ErrorIs(t, err, errTestHook)
+	assert.ErrorContains(t, err, "failed to start server")
+	assert.Equal(t, []string{"start storage", "start server", "stop storage"}, calls)
+}
+
+func TestLifecycle_WhenStopFails_ShouldStopEveryHook(t *testing.T) {
+	var calls []string
+	lc := New()
+	lc.Append(recordingHook("storage", &calls, false, true))
+	lc.Append(recordingHook("server", &calls, false, true))
+	require.NoError(t, lc.Start(context.Background()))
+
+	err := lc.Stop(context.Background())
+
+	assert.ErrorContains(t, err, "failed to stop server")
+	assert.ErrorContains(t, err, "failed to stop storage")
+	assert.Equal(t, []string{"start storage", "start server", "stop server", "stop storage"}, calls)
+}
@@ -0,0 +1,325 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds of the histogram buckets suited to
+// request latencies, in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type family interface {
+	write(w io.Writer)
+}
+
+// ScrapeErrorsMetric counts the failures of the gauge funcs by metric, written
+// after the other metrics so it reflects the same scrape.
+const ScrapeErrorsMetric = "metrics_scrape_errors_total"
+
+// Registry holds metrics and serves them in the Prometheus text format.
+// Metrics are registered once at startup, registering a name twice panics.
+type Registry struct {
+	mutex        sync.Mutex
+	names        map[string]bool
+	families     []family
+	gaugeFuncs   int
+	scrapeErrors *Counter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		names: map[string]bool{ScrapeErrorsMetric: true},
+		scrapeErrors: &Counter{
+			series: newSeries[float64](ScrapeErrorsMetric, "Failures reading a metric on scrape.", "counter", []string{"metric"}),
+		},
+	}
+}
+
+// NewCounter registers a counter, with a series per set of label values.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	counter := &Counter{series: newSeries[float64](name, help, "counter", labels)}
+	r.register(name, counter)
+	return counter
+}
+
+// NewGauge registers a gauge, with a series per set of label values.
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	gauge := &Gauge{series: newSeries[float64](name, help, "gauge", labels)}
+	r.register(name, gauge)
+	return gauge
+}
+
+// NewGaugeFunc registers a gauge whose value is read from f on every scrape.
+// When f fails, the sample is left out rather than reported with a wrong
+// value, and the failure is counted in ScrapeErrorsMetric.
+func (r *Registry) NewGaugeFunc(name, help string, f func() (float64, error)) {
+	r.register(name, &gaugeFunc{name: name, help: help, f: f, errors: r.scrapeErrors})
+	r.scrapeErrors.Add(0, name)
+
+	r.mutex.Lock()
+	r.gaugeFuncs++
+	r.mutex.Unlock()
+}
+
+// NewHistogram registers a histogram counting observations in buckets of the
+// given upper bounds, with a series per set of label values.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	histogram := &Histogram{series: newSeries[histogramValue](name, help, "histogram", labels), buckets: buckets}
+	r.register(name, histogram)
+	return histogram
+}
+
+func (r *Registry) register(name string, f family) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.names[name] {
+		panic(fmt.Sprintf("metric %s already registered", name))
+	}
+
+	r.names[name] = true
+	r.families = append(r.families, f)
+}
+
+// WriteTo writes every metric in the Prometheus text format, in the order
+// they were registered.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mutex.Lock()
+	families := slices.Clone(r.families)
+	gaugeFuncs := r.gaugeFuncs
+	r.mutex.Unlock()
+
+	var buffer bytes.Buffer
+	for _, f := range families {
+		f.write(&buffer)
+	}
+	if gaugeFuncs > 0 {
+		r.scrapeErrors.write(&buffer)
+	}
+
+	return buffer.WriteTo(w)
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+type Counter struct {
+	series *series[float64]
+}
+
+// Inc adds one to the series of the label values, which must match the labels
+// of the counter.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *Counter) Add(value float64, labelValues ...string) {
+	c.series.update(labelValues, func(current *float64) {
+		*current += value
+	})
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.series.write(w, func(w io.Writer, labels string, value float64) {
+		writeSample(w, c.series.name, labels, value)
+	})
+}
+
+type Gauge struct {
+	series *series[float64]
+}
+
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.series.update(labelValues, func(current *float64) {
+		*current = value
+	})
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.series.write(w, func(w io.Writer, labels string, value float64) {
+		writeSample(w, g.series.name, labels, value)
+	})
+}
+
+type gaugeFunc struct {
+	name   string
+	help   string
+	f      func() (float64, error)
+	errors *Counter
+}
+
+func (g *gaugeFunc) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	value, err := g.f()
+	if err != nil {
+		g.errors.Inc(g.name)
+		return
+	}
+	writeSample(w, g.name, "", value)
+}
+
+type Histogram struct {
+	series  *series[histogramValue]
+	buckets []float64
+}
+
+type histogramValue struct {
+	// counts holds the number of observations per bucket, not cumulated.
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.series.update(labelValues, func(current *histogramValue) {
+		if current.counts == nil {
+			current.counts = make([]uint64, len(h.buckets))
+		}
+
+		if i, _ := slices.BinarySearch(h.buckets, value); i < len(h.buckets) {
+			current.counts[i]++
+		}
+		current.count++
+		current.sum += value
+	})
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.series.write(w, func(w io.Writer, labels string, value histogramValue) {
+		var cumulated uint64
+		for i, bound := range h.buckets {
+			if value.counts != nil {
+				cumulated += value.counts[i]
+			}
+			writeSample(w, h.series.name+"_bucket", joinLabels(labels, formatLabel("le", formatValue(bound))), float64(cumulated))
+		}
+		writeSample(w, h.series.name+"_bucket", joinLabels(labels, formatLabel("le", "+Inf")), float64(value.count))
+		writeSample(w, h.series.name+"_sum", labels, value.sum)
+		writeSample(w, h.series.name+"_count", labels, float64(value.count))
+	})
+}
+
+// series holds the values of a metric per set of label values. Metrics
+// without labels have a single series, reported even before being updated.
+type series[T any] struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mutex  sync.Mutex
+	values map[string]*T
+}
+
+func newSeries[T any](name, help, kind string, labels []string) *series[T] {
+	s := &series[T]{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		values: make(map[string]*T),
+	}
+
+	if len(labels) == 0 {
+		s.values[""] = new(T)
+	}
+
+	return s
+}
+
+func (s *series[T]) update(labelValues []string, f func(current *T)) {
+	if len(labelValues) != len(s.labels) {
+		panic(fmt.Sprintf("metric %s has %d labels, got %d values", s.name, len(s.labels), len(labelValues)))
+	}
+
+	key := s.formatLabels(labelValues)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current, found := s.values[key]
+	if !found {
+		current = new(T)
+		s.values[key] = current
+	}
+	f(current)
+}
+
+// write writes the series sorted by labels, so scrapes are stable.
+func (s *series[T]) write(w io.Writer, writeValue func(w io.Writer, labels string, value T)) {
+	s.mutex.Lock()
+	keys := make([]string, 0, len(s.values))
+	values := make(map[string]T, len(s.values))
+	for key, value := range s.values {
+		keys = append(keys, key)
+		values[key] = *value
+	}
+	s.mutex.Unlock()
+
+	slices.Sort(keys)
+
+	writeHeader(w, s.name, s.help, s.kind)
+	for _, key := range keys {
+		writeValue(w, key, values[key])
+	}
+}
+
+func (s *series[T]) formatLabels(labelValues []string) string {
+	pairs := make([]string, len(labelValues))
+	for i, value := range labelValues {
+		pairs[i] = formatLabel(s.labels[i], value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, helpReplacer.Replace(help), name, kind)
+}
+
+func writeSample(w io.Writer, name, labels string, value float64) {
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, formatValue(value))
+}
+
+func formatLabel(name, value string) string {
+	return name + `="` + labelReplacer.Replace(value) + `"`
+}
+
+func joinLabels(labels, label string) string {
+	if labels == "" {
+		return label
+	}
+	return labels + "," + label
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,113 @@
+//go:build unit
+// +build unit
+
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_ShouldWriteTextFormat(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("requests_total", "Requests handled.", "route", "status")
+	gauge := registry.NewGauge("up", "Whether it's up.")
+	registry.NewGaugeFunc("sessions", "Active sessions.", func() (float64, error) { return 3, nil })
+	histogram := registry.NewHistogram("latency_seconds", "Latency.", []float64{1, 0.1}, "route")
+
+	counter.Inc("b", "200")
+	counter.Add(2, "a", "500")
+	counter.Inc("b", "200")
+	counter.Inc(`"quoted"`+"\n", "200")
+	gauge.Set(1)
+	histogram.Observe(0.05, "a")
+	histogram.Observe(0.1, "a")
+	histogram.Observe(5, "a")
+
+	var output strings.Builder
+	_, err := registry.WriteTo(&output)
+
+	require.NoError(t, err)
+	assert.Equal(t, `# HELP requests_total Requests handled.
+# TYPE requests_total counter
+requests_total{route="\"quoted\"\n",status="200"} 1
+requests_total{route="a",status="500"} 2
+requests_total{route="b",status="200"} 2
+# HELP up Whether it's up.
+# TYPE up gauge
+up 1
+# HELP sessions Active sessions.
+# TYPE sessions gauge
+sessions 3
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="a",le="0.1"} 2
+latency_seconds_bucket{route="a",le="1"} 2
+latency_seconds_bucket{route="a",le="+Inf"} 3
+latency_seconds_sum{route="a"} 5.15
+latency_seconds_count{route="a"} 3
+# HELP metrics_scrape_errors_total Failures reading a metric on scrape.
+# TYPE metrics_scrape_errors_total counter
+metrics_scrape_errors_total{metric="sessions"} 0
+`, output.String())
+}
+
+func TestRegistry_WhenGaugeFuncFails_ShouldLeaveSampleOutAndCountError(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGaugeFunc("accounts", "Accounts.", func() (float64, error) { return 0, errors.New("unavailable") })
+
+	var output strings.Builder
+	_, err := registry.WriteTo(&output)
+
+	require.NoError(t, err)
+	assert.Equal(t, `# HELP accounts Accounts.
+# TYPE accounts gauge
+# HELP metrics_scrape_errors_total Failures reading a metric on scrape.
+# TYPE metrics_scrape_errors_total counter
+metrics_scrape_errors_total{metric="accounts"} 1
+`, output.String())
+}
+
+func TestRegistry_WithoutObservations_ShouldOnlyReportUnlabelledSeries(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("reloads_total", "Reloads.")
+	registry.NewCounter("failures_total", "Failures.", "reason")
+
+	var output strings.Builder
+	_, err := registry.WriteTo(&output)
+
+	require.NoError(t, err)
+	assert.Equal(t, `# HELP reloads_total Reloads.
+# TYPE reloads_total counter
+reloads_total 0
+# HELP failures_total Failures.
+# TYPE failures_total counter
+`, output.String())
+}
+
+func TestRegistry_ShouldRejectInvalidUse(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("requests_total", "Requests handled.", "route")
+
+	assert.Panics(t, func() { registry.NewGauge("requests_total", "Duplicate.") })
+	assert.Panics(t, func() { counter.Inc() })
+	assert.Panics(t, func() { counter.Inc("a", "b") })
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGauge("up", "Whether it's up.").Set(1)
+	recorder := httptest.NewRecorder()
+
+	registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "\nup 1\n")
+}
@@ -0,0 +1,293 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"technical-test-backend/internal/errors"
+	"time"
+)
+
+var (
+	ErrClientClosed = errors.New("client closed")
+)
+
+type ClientConfig struct {
+	Address  string
+	Password string
+	DB       int
+	// PoolSize is the number of idle connections kept around.
+	PoolSize int
+	// Timeout bounds dialing and every command.
+	Timeout time.Duration
+}
+
+// Client is a minimal client for Redis-compatible servers, keeping a pool of
+// connections.
+type Client struct {
+	config    ClientConfig
+	idle      chan *Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func NewClient(config ClientConfig) *Client {
+	if config.PoolSize <= 0 {
+		config.PoolSize = 4
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+
+	return &Client{
+		config: config,
+		idle:   make(chan *Conn, config.PoolSize),
+		closed: make(chan struct{}),
+	}
+}
+
+// Do runs a single command on any connection. Error replies are returned as
+// Error.
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	var reply any
+	err := c.WithConn(ctx, func(conn *Conn) error {
+		var err error
+		reply, err = conn.Do(args...)
+		return err
+	})
+	return reply, err
+}
+
+// WithConn runs f on a dedicated connection, for commands that depend on the
+// connection state such as WATCH and MULTI. The commands of f give up once
+// ctx is done, the connection being dropped then. It's reset before being
+// reused otherwise.
+func (c *Client) WithConn(ctx context.Context, f func(conn *Conn) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	conn, err := c.get()
+	if err != nil {
+		return err
+	}
+
+	// Unblocks the command in progress once ctx is done.
+	conn.ctx = ctx
+	stop := context.AfterFunc(ctx, func() {
+		conn.netConn.SetDeadline(time.Unix(1, 0))
+	})
+
+	err = f(conn)
+	if !stop() {
+		conn.broken = true
+	}
+
+	conn.ctx = context.Background()
+	if !conn.broken {
+		conn.reset()
+	}
+
+	c.put(conn)
+	return err
+}
+
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+
+	for {
+		select {
+		case conn := <-c.idle:
+			conn.netConn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get() (*Conn, error) {
+	select {
+	case <-c.closed:
+		return nil, ErrClientClosed
+	default:
+	}
+
+	select {
+	case conn := <-c.idle:
+		return conn, nil
+	default:
+		return c.dial()
+	}
+}
+
+func (c *Client) put(conn *Conn) {
+	if conn.broken {
+		conn.netConn.Close()
+		return
+	}
+
+	select {
+	case <-c.closed:
+		conn.netConn.Close()
+		return
+	default:
+	}
+
+	select {
+	case c.idle <- conn:
+	default:
+		conn.netConn.Close()
+	}
+}
+
+func (c *Client) dial() (*Conn, error) {
+	netConn, err := net.DialTimeout("tcp", c.config.Address, c.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &Conn{
+		netConn: netConn,
+		reader:  bufio.NewReader(netConn),
+		writer:  bufio.NewWriter(netConn),
+		timeout: c.config.Timeout,
+		ctx:     context.Background(),
+	}
+
+	if c.config.Password != "" {
+		if _, err := conn.Do("AUTH", c.config.Password); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+
+	if c.config.DB != 0 {
+		if _, err := conn.Do("SELECT", strconv.Itoa(c.config.DB)); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+type Conn struct {
+	netConn net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	timeout time.Duration
+	// ctx is the context of the caller of WithConn.
+	ctx      context.Context
+	watching bool
+	queuing  bool
+	broken   bool
+}
+
+func (c *Conn) Do(args ...string) (any, error) {
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := c.ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	if err := c.netConn.SetDeadline(deadline); err != nil {
+		c.broken = true
+		return nil, err
+	}
+
+	// Checked after setting the deadline, which would otherwise override the
+	// one set when ctx was done.
+	if err := c.ctx.Err(); err != nil {
+		c.broken = true
+		return nil, err
+	}
+
+	if err := WriteCommand(c.writer, args...); err != nil {
+		c.broken = true
+		return nil, c.ctxErr(err)
+	}
+
+	reply, err := ReadReply(c.reader)
+	if err != nil {
+		c.broken = true
+		return nil, c.ctxErr(err)
+	}
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "WATCH":
+			c.watching = true
+		case "MULTI":
+			c.queuing = true
+		case "EXEC", "DISCARD":
+			c.watching = false
+			c.queuing = false
+		case "UNWATCH":
+			c.watching = false
+		}
+	}
+
+	if replyErr, ok := reply.(Error); ok {
+		return nil, replyErr
+	}
+
+	return reply, nil
+}
+
+// Transaction runs the commands in MULTI/EXEC, returning false if a watched
+// key changed and nothing was applied.
+func (c *Conn) Transaction(commands [][]string) (bool, error) {
+	if _, err := c.Do("MULTI"); err != nil {
+		return false, err
+	}
+
+	for _, command := range commands {
+		if _, err := c.Do(command...); err != nil {
+			return false, err
+		}
+	}
+
+	reply, err := c.Do("EXEC")
+	if err != nil {
+		return false, err
+	}
+
+	replies, ok := reply.([]any)
+	if !ok || replies == nil {
+		return false, nil
+	}
+
+	for _, reply := range replies {
+		if replyErr, ok := reply.(Error); ok {
+			return false, replyErr
+		}
+	}
+
+	return true, nil
+}
+
+// ctxErr reports a failure caused by the end of ctx as such.
+func (c *Conn) ctxErr(err error) error {
+	if ctxErr := c.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+// reset discards a pending transaction and watched keys left by a caller.
+func (c *Conn) reset() {
+	var err error
+	if c.queuing {
+		_, err = c.Do("DISCARD")
+	} else if c.watching {
+		_, err = c.Do("UNWATCH")
+	}
+
+	if err != nil {
+		c.broken = true
+	}
+}
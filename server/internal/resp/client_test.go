@@ -0,0 +1,171 @@
+//go:build unit
+// +build unit
+
+package resp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/resp"
+	"technical-test-backend/internal/resp/fake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) (*resp.Client, *fake.Server) {
+	server, err := fake.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	client := resp.NewClient(resp.ClientConfig{Address: server.Addr(), Password: "secret", DB: 1})
+	t.Cleanup(func() { client.Close() })
+
+	return client, server
+}
+
+func TestDo(t *testing.T) {
+	client, server := newTestClient(t)
+
+	reply, err := resp.String(client.Do(context.Background(), "SET", "key", "value\r\nwith newline", "PX", "1000"))
+	require.NoError(t, err)
+	assert.Equal(t, "OK", reply)
+
+	value, err := resp.String(client.Do(context.Background(), "GET", "key"))
+	require.NoError(t, err)
+	assert.Equal(t, "value\r\nwith newline", value)
+
+	server.FastForward(time.Second)
+
+	_, err = resp.String(client.Do(context.Background(), "GET", "key"))
+	assert.ErrorIs(t, err, resp.ErrNil)
+
+	deleted, err := resp.Int(client.Do(context.Background(), "DEL", "key"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+}
+
+func TestDo_ErrorReply(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	_, err := client.Do(context.Background(), "UNKNOWN")
+
+	var replyErr resp.Error
+	assert.ErrorAs(t, err, &replyErr)
+
+	_, err = resp.String(client.Do(context.Background(), "PING"))
+	assert.NoError(t, err)
+}
+
+func TestWithConn_Transaction(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	t.Run("committed", func(t *testing.T) {
+		err := client.WithConn(context.Background(), func(conn *resp.Conn) error {
+			_, err := conn.Do("WATCH", "key")
+			require.NoError(t, err)
+			_, err = conn.Do("MULTI")
+			require.NoError(t, err)
+			_, err = conn.Do("SET", "key", "1")
+			require.NoError(t, err)
+
+			reply, err := conn.Do("EXEC")
+			require.NoError(t, err)
+			assert.Len(t, reply, 1)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("watched key changed", func(t *testing.T) {
+		err := client.WithConn(context.Background(), func(conn *resp.Conn) error {
+			_, err := conn.Do("WATCH", "key")
+			require.NoError(t, err)
+
+			_, err = client.Do(context.Background(), "SET", "key", "2")
+			require.NoError(t, err)
+
+			_, err = conn.Do("MULTI")
+			require.NoError(t, err)
+			_, err = conn.Do("SET", "key", "3")
+			require.NoError(t, err)
+
+			reply, err := conn.Do("EXEC")
+			require.NoError(t, err)
+			assert.Nil(t, reply)
+			return nil
+		})
+		require.NoError(t, err)
+
+		value, err := resp.String(client.Do(context.Background(), "GET", "key"))
+		require.NoError(t, err)
+		assert.Equal(t, "2", value)
+	})
+
+	t.Run("pending transaction is discarded", func(t *testing.T) {
+		err := client.WithConn(context.Background(), func(conn *resp.Conn) error {
+			_, err := conn.Do("MULTI")
+			require.NoError(t, err)
+			_, err = conn.Do("SET", "key", "4")
+			return err
+		})
+		require.NoError(t, err)
+
+		value, err := resp.String(client.Do(context.Background(), "GET", "key"))
+		require.NoError(t, err)
+		assert.Equal(t, "2", value)
+	})
+}
+
+func TestWithConn_ContextDone(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	t.Run("before the call", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.Do(ctx, "PING")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("between commands", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		err := client.WithConn(ctx, func(conn *resp.Conn) error {
+			_, err := conn.Do("MULTI")
+			require.NoError(t, err)
+
+			cancel()
+			_, err = conn.Do("SET", "key", "5")
+			return err
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = resp.String(client.Do(context.Background(), "GET", "key"))
+		assert.ErrorIs(t, err, resp.ErrNil)
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		<-ctx.Done()
+
+		_, err := client.Do(ctx, "PING")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestClose(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	_, err := client.Do(context.Background(), "PING")
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close())
+
+	_, err = client.Do(context.Background(), "PING")
+	assert.ErrorIs(t, err, resp.ErrClientClosed)
+}
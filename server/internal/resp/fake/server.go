@@ -0,0 +1,625 @@
+package fake
+
+import (
+	"bufio"
+	"math"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"technical-test-backend/internal/resp"
+	"time"
+)
+
+type entry struct {
+	value string
+	// zset holds the scores of the members of a sorted set, nil for a string.
+	zset      map[string]float64
+	expiresAt time.Time
+}
+
+// Server is an in-process Redis-compatible server, implementing the subset of
+// string, sorted set, expiry and transaction commands used by the server.
+type Server struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	entries  map[string]entry
+	// versions is bumped on every write to a key, for WATCH.
+	versions map[string]uint64
+	offset   time.Duration
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server on a random local port.
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		listener: listener,
+		entries:  make(map[string]entry),
+		versions: make(map[string]uint64),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// FastForward moves the clock of the server, expiring keys without waiting.
+func (s *Server) FastForward(d time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.offset += d
+}
+
+// Keys returns the live keys, sorted.
+func (s *Server) Keys() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := make([]string, 0, len(s.entries))
+	for key := range s.entries {
+		if _, exists := s.lookup(key); exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		netConn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(netConn)
+	}
+}
+
+type connState struct {
+	watched map[string]uint64
+	queue   [][]string
+	queuing bool
+}
+
+func (s *Server) handle(netConn net.Conn) {
+	defer netConn.Close()
+
+	reader := bufio.NewReader(netConn)
+	writer := bufio.NewWriter(netConn)
+	state := &connState{}
+
+	for {
+		request, err := resp.ReadReply(reader)
+		if err != nil {
+			return
+		}
+
+		args, ok := toArgs(request)
+		if !ok {
+			return
+		}
+
+		reply := s.dispatch(state, args)
+		if err := resp.WriteReply(writer, reply); err != nil {
+			return
+		}
+		if err := writer.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func toArgs(request any) ([]string, bool) {
+	items, ok := request.([]any)
+	if !ok || len(items) == 0 {
+		return nil, false
+	}
+
+	args := make([]string, len(items))
+	for i, item := range items {
+		if args[i], ok = item.(string); !ok {
+			return nil, false
+		}
+	}
+
+	args[0] = strings.ToUpper(args[0])
+	return args, true
+}
+
+func (s *Server) dispatch(state *connState, args []string) any {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch args[0] {
+	case "MULTI":
+		if state.queuing {
+			return resp.Error("ERR MULTI calls can not be nested")
+		}
+		state.queuing = true
+		return resp.SimpleString("OK")
+	case "EXEC":
+		if !state.queuing {
+			return resp.Error("ERR EXEC without MULTI")
+		}
+		return s.exec(state)
+	case "DISCARD":
+		if !state.queuing {
+			return resp.Error("ERR DISCARD without MULTI")
+		}
+		state.queuing = false
+		state.queue = nil
+		state.watched = nil
+		return resp.SimpleString("OK")
+	case "WATCH":
+		if state.queuing {
+			return resp.Error("ERR WATCH inside MULTI is not allowed")
+		}
+		if state.watched == nil {
+			state.watched = make(map[string]uint64)
+		}
+		for _, key := range args[1:] {
+			s.lookup(key)
+			state.watched[key] = s.versions[key]
+		}
+		return resp.SimpleString("OK")
+	case "UNWATCH":
+		state.watched = nil
+		return resp.SimpleString("OK")
+	}
+
+	if state.queuing {
+		state.queue = append(state.queue, args)
+		return resp.SimpleString("QUEUED")
+	}
+
+	return s.execute(args)
+}
+
+func (s *Server) exec(state *connState) any {
+	defer func() {
+		state.queuing = false
+		state.queue = nil
+		state.watched = nil
+	}()
+
+	for key, version := range state.watched {
+		s.lookup(key)
+		if s.versions[key] != version {
+			return []any(nil)
+		}
+	}
+
+	replies := make([]any, len(state.queue))
+	for i, args := range state.queue {
+		replies[i] = s.execute(args)
+	}
+	return replies
+}
+
+func (s *Server) execute(args []string) any {
+	switch args[0] {
+	case "PING":
+		return resp.SimpleString("PONG")
+	case "AUTH", "SELECT":
+		return resp.SimpleString("OK")
+	case "TIME":
+		now := s.now()
+		return []any{strconv.FormatInt(now.Unix(), 10), strconv.Itoa(now.Nanosecond() / 1000)}
+	case "GET":
+		if len(args) != 2 {
+			return wrongArgs(args[0])
+		}
+		if e, exists := s.lookup(args[1]); exists {
+			if e.zset != nil {
+				return wrongType()
+			}
+			return e.value
+		}
+		return nil
+	case "SET":
+		return s.set(args)
+	case "DEL":
+		deleted := int64(0)
+		for _, key := range args[1:] {
+			if _, exists := s.lookup(key); exists {
+				s.delete(key)
+				deleted++
+			}
+		}
+		return deleted
+	case "PEXPIRE":
+		if len(args) != 3 {
+			return wrongArgs(args[0])
+		}
+		ms, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return resp.Error("ERR value is not an integer or out of range")
+		}
+		e, exists := s.lookup(args[1])
+		if !exists {
+			return int64(0)
+		}
+		e.expiresAt = s.now().Add(time.Duration(ms) * time.Millisecond)
+		s.write(args[1], e)
+		return int64(1)
+	case "PTTL":
+		if len(args) != 2 {
+			return wrongArgs(args[0])
+		}
+		e, exists := s.lookup(args[1])
+		if !exists {
+			return int64(-2)
+		}
+		if e.expiresAt.IsZero() {
+			return int64(-1)
+		}
+		return e.expiresAt.Sub(s.now()).Milliseconds()
+	case "SCAN":
+		return s.scan(args)
+	case "ZADD":
+		return s.zadd(args)
+	case "ZREM":
+		return s.zrem(args)
+	case "ZRANGEBYSCORE":
+		return s.zrangeByScore(args)
+	case "ZCOUNT":
+		return s.zcount(args)
+	case "ZREMRANGEBYSCORE":
+		return s.zremRangeByScore(args)
+	default:
+		return resp.Error("ERR unknown command '" + args[0] + "'")
+	}
+}
+
+// set supports the NX, XX, PX, EX and KEEPTTL options.
+func (s *Server) set(args []string) any {
+	if len(args) < 3 {
+		return wrongArgs(args[0])
+	}
+
+	key, value := args[1], args[2]
+	current, exists := s.lookup(key)
+
+	var nx, xx, keepTTL bool
+	var expiresAt time.Time
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "KEEPTTL":
+			keepTTL = true
+		case "PX", "EX":
+			if i+1 >= len(args) {
+				return resp.Error("ERR syntax error")
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || n <= 0 {
+				return resp.Error("ERR invalid expire time in 'set' command")
+			}
+			unit := time.Millisecond
+			if strings.ToUpper(args[i]) == "EX" {
+				unit = time.Second
+			}
+			expiresAt = s.now().Add(time.Duration(n) * unit)
+			i++
+		default:
+			return resp.Error("ERR syntax error")
+		}
+	}
+
+	if (nx && exists) || (xx && !exists) {
+		return nil
+	}
+
+	if keepTTL && exists {
+		expiresAt = current.expiresAt
+	}
+
+	s.write(key, entry{value: value, expiresAt: expiresAt})
+	return resp.SimpleString("OK")
+}
+
+// scan returns every matching key at once, with a zero cursor.
+func (s *Server) scan(args []string) any {
+	pattern := "*"
+	for i := 2; i+1 < len(args); i += 2 {
+		if strings.ToUpper(args[i]) == "MATCH" {
+			pattern = args[i+1]
+		}
+	}
+
+	keys := []any{}
+	for key := range s.entries {
+		if _, exists := s.lookup(key); !exists {
+			continue
+		}
+		if matched, _ := path.Match(pattern, key); matched {
+			keys = append(keys, key)
+		}
+	}
+
+	return []any{"0", keys}
+}
+
+// zadd supports plain score and member pairs, without options.
+func (s *Server) zadd(args []string) any {
+	if len(args) < 4 || len(args)%2 != 0 {
+		return wrongArgs(args[0])
+	}
+
+	e, ok := s.lookupZSet(args[1])
+	if !ok {
+		return wrongType()
+	}
+
+	zset := make(map[string]float64, len(e.zset)+1)
+	for member, score := range e.zset {
+		zset[member] = score
+	}
+
+	added := int64(0)
+	for i := 2; i < len(args); i += 2 {
+		score, err := strconv.ParseFloat(args[i], 64)
+		if err != nil {
+			return resp.Error("ERR value is not a valid float")
+		}
+		if _, exists := zset[args[i+1]]; !exists {
+			added++
+		}
+		zset[args[i+1]] = score
+	}
+
+	e.zset = zset
+	s.write(args[1], e)
+	return added
+}
+
+func (s *Server) zrem(args []string) any {
+	if len(args) < 3 {
+		return wrongArgs(args[0])
+	}
+
+	e, ok := s.lookupZSet(args[1])
+	if !ok {
+		return wrongType()
+	}
+
+	zset := make(map[string]float64, len(e.zset))
+	for member, score := range e.zset {
+		zset[member] = score
+	}
+
+	removed := int64(0)
+	for _, member := range args[2:] {
+		if _, exists := zset[member]; exists {
+			delete(zset, member)
+			removed++
+		}
+	}
+
+	if removed == 0 {
+		return removed
+	}
+
+	if len(zset) == 0 {
+		s.delete(args[1])
+	} else {
+		e.zset = zset
+		s.write(args[1], e)
+	}
+	return removed
+}
+
+// zrangeByScore supports the LIMIT option, without scores.
+func (s *Server) zrangeByScore(args []string) any {
+	if len(args) != 4 && len(args) != 7 {
+		return wrongArgs(args[0])
+	}
+
+	e, ok := s.lookupZSet(args[1])
+	if !ok {
+		return wrongType()
+	}
+
+	inRange, ok := scoreRange(args[2], args[3])
+	if !ok {
+		return resp.Error("ERR min or max is not a float")
+	}
+
+	offset, count := 0, -1
+	if len(args) == 7 {
+		if strings.ToUpper(args[4]) != "LIMIT" {
+			return resp.Error("ERR syntax error")
+		}
+		var err1, err2 error
+		offset, err1 = strconv.Atoi(args[5])
+		count, err2 = strconv.Atoi(args[6])
+		if err1 != nil || err2 != nil {
+			return resp.Error("ERR value is not an integer or out of range")
+		}
+	}
+
+	members := make([]string, 0, len(e.zset))
+	for member, score := range e.zset {
+		if inRange(score) {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if e.zset[members[i]] != e.zset[members[j]] {
+			return e.zset[members[i]] < e.zset[members[j]]
+		}
+		return members[i] < members[j]
+	})
+
+	reply := []any{}
+	for i := offset; i < len(members) && (count < 0 || len(reply) < count); i++ {
+		reply = append(reply, members[i])
+	}
+	return reply
+}
+
+func (s *Server) zcount(args []string) any {
+	if len(args) != 4 {
+		return wrongArgs(args[0])
+	}
+
+	e, ok := s.lookupZSet(args[1])
+	if !ok {
+		return wrongType()
+	}
+
+	inRange, ok := scoreRange(args[2], args[3])
+	if !ok {
+		return resp.Error("ERR min or max is not a float")
+	}
+
+	count := int64(0)
+	for _, score := range e.zset {
+		if inRange(score) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Server) zremRangeByScore(args []string) any {
+	if len(args) != 4 {
+		return wrongArgs(args[0])
+	}
+
+	e, ok := s.lookupZSet(args[1])
+	if !ok {
+		return wrongType()
+	}
+
+	inRange, ok := scoreRange(args[2], args[3])
+	if !ok {
+		return resp.Error("ERR min or max is not a float")
+	}
+
+	zset := make(map[string]float64, len(e.zset))
+	for member, score := range e.zset {
+		if !inRange(score) {
+			zset[member] = score
+		}
+	}
+
+	removed := int64(len(e.zset) - len(zset))
+	if removed == 0 {
+		return removed
+	}
+
+	if len(zset) == 0 {
+		s.delete(args[1])
+	} else {
+		e.zset = zset
+		s.write(args[1], e)
+	}
+	return removed
+}
+
+// lookupZSet returns the sorted set at key, empty if missing, or false if the
+// key holds a string.
+func (s *Server) lookupZSet(key string) (entry, bool) {
+	e, exists := s.lookup(key)
+	if !exists {
+		return entry{}, true
+	}
+	return e, e.zset != nil
+}
+
+// scoreRange parses the min and max of a score range, each one either
+// inclusive, exclusive with a "(" prefix, or infinite.
+func scoreRange(min, max string) (func(score float64) bool, bool) {
+	minScore, minExclusive, ok := parseScoreBound(min)
+	if !ok {
+		return nil, false
+	}
+	maxScore, maxExclusive, ok := parseScoreBound(max)
+	if !ok {
+		return nil, false
+	}
+
+	return func(score float64) bool {
+		if score < minScore || (minExclusive && score == minScore) {
+			return false
+		}
+		return score < maxScore || (!maxExclusive && score == maxScore)
+	}, true
+}
+
+func parseScoreBound(bound string) (float64, bool, bool) {
+	exclusive := strings.HasPrefix(bound, "(")
+	bound = strings.TrimPrefix(bound, "(")
+
+	switch bound {
+	case "-inf":
+		return math.Inf(-1), exclusive, true
+	case "+inf", "inf":
+		return math.Inf(1), exclusive, true
+	}
+
+	score, err := strconv.ParseFloat(bound, 64)
+	return score, exclusive, err == nil
+}
+
+// lookup returns a live entry, deleting it if it expired.
+func (s *Server) lookup(key string) (entry, bool) {
+	e, exists := s.entries[key]
+	if !exists {
+		return entry{}, false
+	}
+
+	if !e.expiresAt.IsZero() && !s.now().Before(e.expiresAt) {
+		s.delete(key)
+		return entry{}, false
+	}
+
+	return e, true
+}
+
+func (s *Server) write(key string, e entry) {
+	s.entries[key] = e
+	s.versions[key]++
+}
+
+func (s *Server) delete(key string) {
+	delete(s.entries, key)
+	s.versions[key]++
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+func wrongType() any {
+	return resp.Error("WRONGTYPE Operation against a key holding the wrong kind of value")
+}
+
+func wrongArgs(command string) any {
+	return resp.Error("ERR wrong number of arguments for '" + strings.ToLower(command) + "' command")
+}
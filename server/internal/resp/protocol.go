@@ -0,0 +1,200 @@
+package resp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"technical-test-backend/internal/errors"
+)
+
+var (
+	ErrNil            = errors.New("nil reply")
+	ErrProtocol       = errors.New("protocol error")
+	ErrUnexpectedType = errors.New("unexpected reply type")
+)
+
+// Error is an error reply sent by the server.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// WriteCommand encodes a command as an array of bulk strings.
+func WriteCommand(w *bufio.Writer, args ...string) error {
+	if _, err := fmt.Fprintf(w, "*%d\r\n", len(args)); err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		if err := WriteBulkString(w, arg); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+func WriteBulkString(w *bufio.Writer, s string) error {
+	_, err := fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+	return err
+}
+
+// WriteReply encodes a reply, as read by ReadReply.
+func WriteReply(w *bufio.Writer, reply any) error {
+	var err error
+	switch v := reply.(type) {
+	case nil:
+		_, err = w.WriteString("$-1\r\n")
+	case SimpleString:
+		_, err = fmt.Fprintf(w, "+%s\r\n", v)
+	case Error:
+		_, err = fmt.Fprintf(w, "-%s\r\n", v)
+	case int64:
+		_, err = fmt.Fprintf(w, ":%d\r\n", v)
+	case string:
+		err = WriteBulkString(w, v)
+	case []any:
+		if v == nil {
+			_, err = w.WriteString("*-1\r\n")
+			break
+		}
+		if _, err = fmt.Fprintf(w, "*%d\r\n", len(v)); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err = WriteReply(w, item); err != nil {
+				return err
+			}
+		}
+	default:
+		err = errors.Wrapf(ErrUnexpectedType, "%T", reply)
+	}
+	return err
+}
+
+// SimpleString is a status reply, such as OK.
+type SimpleString string
+
+// ReadReply decodes a reply into nil, SimpleString, Error, int64, string or
+// []any. A nil array is returned as a nil []any.
+func ReadReply(r *bufio.Reader) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 {
+		return nil, ErrProtocol
+	}
+
+	switch line[0] {
+	case '+':
+		return SimpleString(line[1:]), nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(ErrProtocol, err)
+		}
+		return n, nil
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errors.Wrap(ErrProtocol, err)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errors.Wrap(ErrProtocol, err)
+		}
+		if size < 0 {
+			return []any(nil), nil
+		}
+		items := make([]any, size)
+		for i := range items {
+			if items[i], err = ReadReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, errors.Wrapf(ErrProtocol, "unknown reply type %q", line[0])
+	}
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", ErrProtocol
+	}
+
+	return line[:len(line)-2], nil
+}
+
+// String converts a bulk or status reply, returning ErrNil for a nil one.
+func String(reply any, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	switch v := reply.(type) {
+	case nil:
+		return "", ErrNil
+	case string:
+		return v, nil
+	case SimpleString:
+		return string(v), nil
+	default:
+		return "", errors.Wrapf(ErrUnexpectedType, "%T", reply)
+	}
+}
+
+// Int converts an integer reply.
+func Int(reply any, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, errors.Wrapf(ErrUnexpectedType, "%T", reply)
+	}
+
+	return n, nil
+}
+
+// Strings converts an array of bulk strings.
+func Strings(reply any, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := reply.([]any)
+	if !ok {
+		return nil, errors.Wrapf(ErrUnexpectedType, "%T", reply)
+	}
+
+	values := make([]string, len(items))
+	for i, item := range items {
+		if values[i], err = String(item, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
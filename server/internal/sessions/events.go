@@ -0,0 +1,91 @@
+package sessions
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	// EventReplaced is sent for a session kicked by a newer login.
+	EventReplaced EventType = "replaced"
+	EventExpired  EventType = "expired"
+	// EventRemoved is sent for a session removed explicitly.
+	EventRemoved EventType = "removed"
+	// EventRevoked is sent for a session ended by an administrator.
+	EventRevoked EventType = "revoked"
+)
+
+// Event describes a change in the lifecycle of a session. Data holds the
+// session data at that time, as it's gone once the session is removed. It's
+// left empty when the data is still shared by other sessions of the account.
+type Event struct {
+	Type    EventType
+	Session Session
+	Data    json.RawMessage
+}
+
+// DecodeData unmarshals the session data of the event.
+func (e Event) DecodeData(data interface{}) error {
+	if len(e.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(e.Data, data)
+}
+
+// Subscriber is called for every event, from the goroutine that caused it but
+// outside of any pool lock. Slow subscribers should hand events off.
+type Subscriber func(event Event)
+
+// Publisher keeps track of the subscribers of a pool.
+type Publisher struct {
+	mutex       sync.RWMutex
+	subscribers map[int]Subscriber
+	nextID      int
+}
+
+func NewPublisher() *Publisher {
+	return &Publisher{
+		subscribers: make(map[int]Subscriber),
+	}
+}
+
+// Subscribe registers a subscriber, and returns a function to unregister it.
+func (p *Publisher) Subscribe(subscriber Subscriber) func() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	id := p.nextID
+	p.nextID++
+	p.subscribers[id] = subscriber
+
+	return func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		delete(p.subscribers, id)
+	}
+}
+
+// Publish delivers the events in order to every subscriber. It must not be
+// called while holding a lock the subscribers could need.
+func (p *Publisher) Publish(events ...Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	p.mutex.RLock()
+	subscribers := make([]Subscriber, 0, len(p.subscribers))
+	for _, subscriber := range p.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	p.mutex.RUnlock()
+
+	for _, event := range events {
+		for _, subscriber := range subscribers {
+			subscriber(event)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+//go:build unit
+// +build unit
+
+package sessions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublisher(t *testing.T) {
+	publisher := NewPublisher()
+
+	var first, second []EventType
+	unsubscribeFirst := publisher.Subscribe(func(event Event) { first = append(first, event.Type) })
+	publisher.Subscribe(func(event Event) { second = append(second, event.Type) })
+
+	publisher.Publish(Event{Type: EventReplaced}, Event{Type: EventCreated})
+	unsubscribeFirst()
+	publisher.Publish(Event{Type: EventRemoved})
+
+	assert.Equal(t, []EventType{EventReplaced, EventCreated}, first)
+	assert.Equal(t, []EventType{EventReplaced, EventCreated, EventRemoved}, second)
+}
+
+func TestEvent_DecodeData(t *testing.T) {
+	t.Run("with data", func(t *testing.T) {
+		var data struct{ Level int }
+		require.NoError(t, Event{Data: []byte(`{"Level":3}`)}.DecodeData(&data))
+		assert.Equal(t, 3, data.Level)
+	})
+
+	t.Run("without data", func(t *testing.T) {
+		var data struct{ Level int }
+		assert.NoError(t, Event{}.DecodeData(&data))
+	})
+}
@@ -1,6 +1,16 @@
 package sessions
 
-import "time"
+import (
+	"context"
+	"technical-test-backend/internal/errors"
+	"time"
+)
+
+const ErrorCodeSessionDataMissing = "SESSION_DATA_MISSING"
+
+// ErrSessionDataMissing is matched by the errors of the Data methods when the
+// session has no data, such as when it ended or is unknown to the storage.
+var ErrSessionDataMissing = errors.NewCoded(ErrorCodeSessionDataMissing, "session data missing")
 
 type Session struct {
 	ID           string    `json:"id"`
@@ -9,17 +19,32 @@ type Session struct {
 	CreatedAt    time.Time `json:"createdAt"`
 }
 
+// Pool implementations stop waiting on the storage once ctx is done. The
+// methods returning an error return ctx.Err() then, the others report the
+// session as missing, so callers tell both apart with ctx.Err().
 type Pool interface {
 	Data
-	CreateSession(accountID string, data interface{}) (Session, error)
+	CreateSession(ctx context.Context, accountID string, data interface{}) (Session, error)
+
+	GetSession(ctx context.Context, sessionID string) (Session, bool)
+	UpdateActivity(ctx context.Context, sessionID string) error
+	RemoveSession(ctx context.Context, sessionID string)
+	GetAccountID(ctx context.Context, sessionID string) (string, bool)
+
+	// RevokeSessions ends every session of the account, for moderation.
+	RevokeSessions(ctx context.Context, accountID string)
+	// GetTombstone tells why a session that is no longer valid ended, if it
+	// ended recently enough to be remembered.
+	GetTombstone(ctx context.Context, sessionID string) (Tombstone, bool)
 
-	GetSession(sessionID string) (Session, bool)
-	UpdateActivity(sessionID string) error
-	RemoveSession(sessionID string)
-	GetAccountID(sessionID string) (string, bool)
+	// Subscribe registers a subscriber to the lifecycle events of the sessions,
+	// and returns a function to unregister it.
+	Subscribe(subscriber Subscriber) func()
 }
 
+// Data holds the data of each session, such as the level in progress and the
+// last command sequence, dropped along with the session.
 type Data interface {
-	GetSessionData(sessionID string, data interface{}) error
-	SetSessionData(sessionID string, data interface{}) error
+	GetSessionData(ctx context.Context, sessionID string, data interface{}) error
+	SetSessionData(ctx context.Context, sessionID string, data interface{}) error
 }
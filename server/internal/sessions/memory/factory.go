@@ -1,8 +1,14 @@
 package memory
 
-import "technical-test-backend/internal/worker"
+import (
+	"context"
+	"technical-test-backend/internal/lifecycle"
+	"technical-test-backend/internal/worker"
+)
 
-func CreateSessionPool(config SessionPoolConfig) (*SessionPool, func()) {
+// CreateSessionPool registers the worker cleaning up the expired sessions on
+// the lifecycle.
+func CreateSessionPool(lc *lifecycle.Lifecycle, config SessionPoolConfig) *SessionPool {
 	sp := NewSessionPool(config)
 	cleanupWorker := worker.New(worker.Config{
 		Interval: config.TTL,
@@ -10,9 +16,17 @@ func CreateSessionPool(config SessionPoolConfig) (*SessionPool, func()) {
 		sp.CleanupExpiredSessions()
 	})
 
-	cleanupWorker.Start()
+	lc.Append(lifecycle.Hook{
+		Name: "memory sessions cleanup",
+		OnStart: func(ctx context.Context) error {
+			cleanupWorker.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cleanupWorker.Stop()
+			return nil
+		},
+	})
 
-	return sp, func() {
-		cleanupWorker.Stop()
-	}
+	return sp
 }
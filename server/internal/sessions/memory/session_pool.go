@@ -1,8 +1,10 @@
 package memory
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"slices"
 	"sync"
 	"technical-test-backend/internal/errors"
 	"technical-test-backend/internal/sessions"
@@ -19,31 +21,53 @@
 )
 
 type SessionPoolConfig struct {
-	TTL time.Duration
+	TTL    time.Duration
+	Policy sessions.Policy
+	// MaxSessions is the number of sessions allowed per account by
+	// sessions.PolicyConcurrent.
+	MaxSessions int
+	// TombstoneTTL is how long the reason of the end of a session is kept.
+	// Zero disables tombstones.
+	TombstoneTTL time.Duration
 }
 
+// SessionPool keeps the sessions in memory, along with the data of each of
+// them.
 type SessionPool struct {
 	sessions              map[string]sessions.Session
 	sessionsData          map[string]json.RawMessage
 	accountIDsBySessionID map[string]string
-	sessionIDsByAccountID map[string]string
+	sessionIDsByAccountID map[string][]string
+	tombstones            map[string]sessions.Tombstone
 	mutex                 sync.RWMutex
 	config                SessionPoolConfig
+	publisher             *sessions.Publisher
 }
 
 func NewSessionPool(config SessionPoolConfig) *SessionPool {
 	sp := &SessionPool{
 		sessions:              make(map[string]sessions.Session),
 		accountIDsBySessionID: make(map[string]string),
-		sessionIDsByAccountID: make(map[string]string),
+		sessionIDsByAccountID: make(map[string][]string),
 		sessionsData:          make(map[string]json.RawMessage),
+		tombstones:            make(map[string]sessions.Tombstone),
 		config:                config,
+		publisher:             sessions.NewPublisher(),
 	}
 
 	return sp
 }
 
-func (sp *SessionPool) CreateSession(accountID string, data interface{}) (sessions.Session, error) {
+func (sp *SessionPool) CreateSession(ctx context.Context, accountID string, data interface{}) (sessions.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return sessions.Session{}, err
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return sessions.Session{}, errors.Wrap(err, ErrFailedToMarshalData)
+	}
+
 	sess := sessions.Session{
 		ID:           uuid.New().String(),
 		AccountID:    accountID,
@@ -51,31 +75,51 @@ func (sp *SessionPool) CreateSession(accountID string, data interface{}) (sessio
 		CreatedAt:    time.Now(),
 	}
 
+	var events []sessions.Event
+
 	sp.mutex.Lock()
-	defer sp.mutex.Unlock()
 
-	if existingSessionID, exists := sp.sessionIDsByAccountID[accountID]; exists {
+	existingSessionIDs := sp.sessionIDsByAccountID[accountID]
+	removedEventType := sessions.EventReplaced
+	switch sp.config.Policy {
+	case sessions.PolicyRejectNew:
+		if sp.hasLiveSession(existingSessionIDs) {
+			sp.mutex.Unlock()
+			return sessions.Session{}, sessions.ErrSessionLimitReached
+		}
+		existingSessionIDs = slices.Clone(existingSessionIDs)
+		removedEventType = sessions.EventExpired
+	case sessions.PolicyConcurrent:
+		existingSessionIDs = sp.leastRecentlyActive(existingSessionIDs, len(existingSessionIDs)+1-max(sp.config.MaxSessions, 1))
+	default:
+		existingSessionIDs = slices.Clone(existingSessionIDs)
+	}
+
+	for _, existingSessionID := range existingSessionIDs {
 		log.Printf("Removed existing session: %s", existingSessionID)
-		sp.removeSessionImpl(existingSessionID)
+		events = append(events, sp.removeSessionImpl(existingSessionID, removedEventType))
 	}
 
 	sp.sessions[sess.ID] = sess
 	sp.accountIDsBySessionID[sess.ID] = accountID
-	sp.sessionIDsByAccountID[accountID] = sess.ID
-
-	rawData, err := json.Marshal(data)
-	if err != nil {
-		return sessions.Session{}, errors.Wrap(err, ErrFailedToMarshalData)
-	}
-
+	sp.sessionIDsByAccountID[accountID] = append(sp.sessionIDsByAccountID[accountID], sess.ID)
 	sp.sessionsData[sess.ID] = rawData
 
+	sp.mutex.Unlock()
+
 	log.Printf("Created session: %s", sess.ID)
 
+	events = append(events, sessions.Event{Type: sessions.EventCreated, Session: sess, Data: rawData})
+	sp.publisher.Publish(events...)
+
 	return sess, nil
 }
 
-func (sp *SessionPool) GetSession(sessionID string) (sessions.Session, bool) {
+func (sp *SessionPool) GetSession(ctx context.Context, sessionID string) (sessions.Session, bool) {
+	if ctx.Err() != nil {
+		return sessions.Session{}, false
+	}
+
 	sp.mutex.RLock()
 	defer sp.mutex.RUnlock()
 
@@ -91,18 +135,17 @@ func (sp *SessionPool) GetSession(sessionID string) (sessions.Session, bool) {
 	return sess, true
 }
 
-func (sp *SessionPool) GetSessionData(accountID string, data interface{}) error {
+func (sp *SessionPool) GetSessionData(ctx context.Context, sessionID string, data interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	sp.mutex.RLock()
 	defer sp.mutex.RUnlock()
 
-	sessionID, exists := sp.sessionIDsByAccountID[accountID]
-	if !exists {
-		return ErrSessionNotFound
-	}
-
 	savedData, exists := sp.sessionsData[sessionID]
 	if !exists {
-		return ErrSessionNotFound
+		return errors.Wrap(ErrSessionNotFound, sessions.ErrSessionDataMissing)
 	}
 
 	err := json.Unmarshal(savedData, data)
@@ -113,7 +156,11 @@ func (sp *SessionPool) GetSessionData(accountID string, data interface{}) error
 	return nil
 }
 
-func (sp *SessionPool) SetSessionData(accountID string, data interface{}) error {
+func (sp *SessionPool) SetSessionData(ctx context.Context, sessionID string, data interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	sp.mutex.Lock()
 	defer sp.mutex.Unlock()
 
@@ -122,16 +169,19 @@ func (sp *SessionPool) SetSessionData(accountID string, data interface{}) error
 		return errors.Wrap(err, ErrFailedToMarshalData)
 	}
 
-	sessionID, exists := sp.sessionIDsByAccountID[accountID]
-	if !exists {
-		return ErrSessionNotFound
+	if _, exists := sp.sessionsData[sessionID]; !exists {
+		return errors.Wrap(ErrSessionNotFound, sessions.ErrSessionDataMissing)
 	}
 
 	sp.sessionsData[sessionID] = rawData
 	return nil
 }
 
-func (sp *SessionPool) UpdateActivity(sessionID string) error {
+func (sp *SessionPool) UpdateActivity(ctx context.Context, sessionID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	sp.mutex.Lock()
 	defer sp.mutex.Unlock()
 
@@ -149,18 +199,29 @@ func (sp *SessionPool) UpdateActivity(sessionID string) error {
 	return nil
 }
 
-func (sp *SessionPool) RemoveSession(sessionID string) {
+func (sp *SessionPool) RemoveSession(ctx context.Context, sessionID string) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	sp.mutex.Lock()
-	defer sp.mutex.Unlock()
 
 	if _, exists := sp.sessions[sessionID]; !exists {
+		sp.mutex.Unlock()
 		return
 	}
 
-	sp.removeSessionImpl(sessionID)
+	event := sp.removeSessionImpl(sessionID, sessions.EventRemoved)
+	sp.mutex.Unlock()
+
+	sp.publisher.Publish(event)
 }
 
-func (sp *SessionPool) GetAccountID(sessionID string) (string, bool) {
+func (sp *SessionPool) GetAccountID(ctx context.Context, sessionID string) (string, bool) {
+	if ctx.Err() != nil {
+		return "", false
+	}
+
 	sp.mutex.RLock()
 	defer sp.mutex.RUnlock()
 
@@ -176,35 +237,156 @@ func (sp *SessionPool) GetAccountID(sessionID string) (string, bool) {
 	return sess.AccountID, true
 }
 
+// RevokeSessions removes every session of the account.
+func (sp *SessionPool) RevokeSessions(ctx context.Context, accountID string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	sp.mutex.Lock()
+
+	sessionIDs := slices.Clone(sp.sessionIDsByAccountID[accountID])
+	events := make([]sessions.Event, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		events = append(events, sp.removeSessionImpl(sessionID, sessions.EventRevoked))
+		log.Printf("Revoked session: %s", sessionID)
+	}
+
+	sp.mutex.Unlock()
+
+	sp.publisher.Publish(events...)
+}
+
+// GetTombstone also reports sessions that expired but weren't cleaned up yet.
+func (sp *SessionPool) GetTombstone(ctx context.Context, sessionID string) (sessions.Tombstone, bool) {
+	if ctx.Err() != nil {
+		return sessions.Tombstone{}, false
+	}
+
+	if sp.config.TombstoneTTL <= 0 {
+		return sessions.Tombstone{}, false
+	}
+
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+
+	if tombstone, exists := sp.tombstones[sessionID]; exists {
+		return tombstone, true
+	}
+
+	sess, exists := sp.sessions[sessionID]
+	if !exists {
+		return sessions.Tombstone{}, false
+	}
+
+	expiresAt := sess.LastActivity.Add(sp.config.TTL)
+	if !time.Now().After(expiresAt) {
+		return sessions.Tombstone{}, false
+	}
+
+	return sessions.Tombstone{
+		SessionID: sessionID,
+		AccountID: sess.AccountID,
+		Reason:    sessions.EndReasonExpired,
+		EndedAt:   expiresAt,
+	}, true
+}
+
+// CleanupExpiredSessions removes the expired sessions, and forgets the old
+// tombstones.
 func (sp *SessionPool) CleanupExpiredSessions() {
 	sp.mutex.Lock()
-	defer sp.mutex.Unlock()
 
 	now := time.Now()
 	var expiredSessions []string
 
+	for sessionID, tombstone := range sp.tombstones {
+		if now.After(tombstone.EndedAt.Add(sp.config.TombstoneTTL)) {
+			delete(sp.tombstones, sessionID)
+		}
+	}
+
 	for sessionID, session := range sp.sessions {
 		if now.After(session.LastActivity.Add(sp.config.TTL)) {
 			expiredSessions = append(expiredSessions, sessionID)
 		}
 	}
 
+	events := make([]sessions.Event, 0, len(expiredSessions))
 	for _, sessionID := range expiredSessions {
-		sp.removeSessionImpl(sessionID)
+		events = append(events, sp.removeSessionImpl(sessionID, sessions.EventExpired))
 		log.Printf("Removed expired session: %s", sessionID)
 	}
+
+	sp.mutex.Unlock()
+
+	sp.publisher.Publish(events...)
+}
+
+func (sp *SessionPool) Subscribe(subscriber sessions.Subscriber) func() {
+	return sp.publisher.Subscribe(subscriber)
 }
 
-func (sp *SessionPool) removeSessionImpl(sessionID string) {
+// removeSessionImpl removes a session and its data and leaves its tombstone,
+// returning the event to publish once the mutex is released.
+func (sp *SessionPool) removeSessionImpl(sessionID string, eventType sessions.EventType) sessions.Event {
 	session := sp.sessions[sessionID]
+	event := sessions.Event{Type: eventType, Session: session}
+
+	if sp.config.TombstoneTTL > 0 {
+		endedAt := time.Now()
+		if eventType == sessions.EventExpired {
+			endedAt = session.LastActivity.Add(sp.config.TTL)
+		}
+		if tombstone, ended := sessions.NewTombstone(event, endedAt); ended {
+			sp.tombstones[sessionID] = tombstone
+		}
+	}
+
+	event.Data = sp.sessionsData[sessionID]
 	delete(sp.sessions, sessionID)
 	delete(sp.sessionsData, sessionID)
-	delete(sp.sessionIDsByAccountID, session.AccountID)
 	delete(sp.accountIDsBySessionID, sessionID)
+
+	remainingSessionIDs := slices.DeleteFunc(sp.sessionIDsByAccountID[session.AccountID], func(id string) bool {
+		return id == sessionID
+	})
+	if len(remainingSessionIDs) > 0 {
+		sp.sessionIDsByAccountID[session.AccountID] = remainingSessionIDs
+	} else {
+		delete(sp.sessionIDsByAccountID, session.AccountID)
+	}
+
+	return event
+}
+
+func (sp *SessionPool) hasLiveSession(sessionIDs []string) bool {
+	now := time.Now()
+	for _, sessionID := range sessionIDs {
+		if !now.After(sp.sessions[sessionID].LastActivity.Add(sp.config.TTL)) {
+			return true
+		}
+	}
+	return false
+}
+
+// leastRecentlyActive returns the count sessions that were active the longest
+// time ago.
+func (sp *SessionPool) leastRecentlyActive(sessionIDs []string, count int) []string {
+	if count <= 0 {
+		return nil
+	}
+
+	sorted := slices.Clone(sessionIDs)
+	slices.SortFunc(sorted, func(a, b string) int {
+		return sp.sessions[a].LastActivity.Compare(sp.sessions[b].LastActivity)
+	})
+
+	return sorted[:min(count, len(sorted))]
 }
 
-func (sp *SessionPool) GetSessionCount() int {
+func (sp *SessionPool) GetSessionCount() (int, error) {
 	sp.mutex.RLock()
 	defer sp.mutex.RUnlock()
-	return len(sp.sessions)
+	return len(sp.sessions), nil
 }
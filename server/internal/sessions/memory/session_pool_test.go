@@ -4,8 +4,11 @@
 package memory
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"sync"
+	"technical-test-backend/internal/sessions"
 	"testing"
 	"time"
 
@@ -29,7 +32,7 @@ func TestCreateSession(t *testing.T) {
 		accountID := "test-account-123"
 		testData := map[string]interface{}{"level": 1}
 
-		session, err := sp.CreateSession(accountID, testData)
+		session, err := sp.CreateSession(context.Background(), accountID, testData)
 
 		require.NoError(t, err)
 		assert.NotEmpty(t, session.ID)
@@ -42,14 +45,14 @@ func TestCreateSession(t *testing.T) {
 		accountID := "test-account-456"
 		invalidData := make(chan int)
 
-		session, err := sp.CreateSession(accountID, invalidData)
+		session, err := sp.CreateSession(context.Background(), accountID, invalidData)
 
 		assert.ErrorIs(t, err, ErrFailedToMarshalData)
 		assert.Empty(t, session.ID)
 	})
 
 	t.Run("empty account ID", func(t *testing.T) {
-		_, err := sp.CreateSession("", map[string]interface{}{"level": 1})
+		_, err := sp.CreateSession(context.Background(), "", map[string]interface{}{"level": 1})
 
 		require.NoError(t, err)
 	})
@@ -65,10 +68,10 @@ func TestGetSession(t *testing.T) {
 		accountID := "test-account-123"
 		testData := map[string]interface{}{"level": 1}
 
-		session, err := sp.CreateSession(accountID, testData)
+		session, err := sp.CreateSession(context.Background(), accountID, testData)
 		require.NoError(t, err)
 
-		retrievedSession, exists := sp.GetSession(session.ID)
+		retrievedSession, exists := sp.GetSession(context.Background(), session.ID)
 
 		assert.True(t, exists)
 		assert.Equal(t, session, retrievedSession)
@@ -77,7 +80,7 @@ func TestGetSession(t *testing.T) {
 	t.Run("non-existent session", func(t *testing.T) {
 		nonExistentID := uuid.New().String()
 
-		session, exists := sp.GetSession(nonExistentID)
+		session, exists := sp.GetSession(context.Background(), nonExistentID)
 
 		assert.False(t, exists)
 		assert.Empty(t, session.ID)
@@ -87,12 +90,12 @@ func TestGetSession(t *testing.T) {
 		accountID := "test-account-expired"
 		testData := map[string]interface{}{"level": 1}
 
-		session, err := sp.CreateSession(accountID, testData)
+		session, err := sp.CreateSession(context.Background(), accountID, testData)
 		require.NoError(t, err)
 
 		time.Sleep(150 * time.Millisecond)
 
-		retrievedSession, exists := sp.GetSession(session.ID)
+		retrievedSession, exists := sp.GetSession(context.Background(), session.ID)
 
 		assert.False(t, exists)
 		assert.Empty(t, retrievedSession.ID)
@@ -100,13 +103,13 @@ func TestGetSession(t *testing.T) {
 
 	t.Run("with nil data", func(t *testing.T) {
 		accountID := "test-account-nil"
-		session, err := sp.CreateSession(accountID, nil)
+		session, err := sp.CreateSession(context.Background(), accountID, nil)
 
 		require.NoError(t, err)
 		assert.NotEmpty(t, session.ID)
 
 		var retrievedData TestData
-		err = sp.GetSessionData(accountID, &retrievedData)
+		err = sp.GetSessionData(context.Background(), session.ID, &retrievedData)
 		require.NoError(t, err)
 		assert.NotNil(t, retrievedData)
 	})
@@ -125,21 +128,19 @@ func TestGetSessionData(t *testing.T) {
 			Score: 2500,
 		}
 
-		_, err := sp.CreateSession(accountID, expectedData)
+		session, err := sp.CreateSession(context.Background(), accountID, expectedData)
 		require.NoError(t, err)
 
 		var retrievedData TestData
-		err = sp.GetSessionData(accountID, &retrievedData)
+		err = sp.GetSessionData(context.Background(), session.ID, &retrievedData)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedData, retrievedData)
 	})
 
-	t.Run("non-existent account", func(t *testing.T) {
-		nonExistentAccountID := "non-existent-account"
-
+	t.Run("non-existent session", func(t *testing.T) {
 		var data map[string]interface{}
-		err := sp.GetSessionData(nonExistentAccountID, &data)
+		err := sp.GetSessionData(context.Background(), "non-existent-session", &data)
 
 		assert.ErrorIs(t, err, ErrSessionNotFound)
 	})
@@ -148,11 +149,11 @@ func TestGetSessionData(t *testing.T) {
 		accountID := "test-account-unmarshal"
 		testData := map[string]interface{}{"level": 1}
 
-		_, err := sp.CreateSession(accountID, testData)
+		session, err := sp.CreateSession(context.Background(), accountID, testData)
 		require.NoError(t, err)
 
 		var wrongType int
-		err = sp.GetSessionData(accountID, &wrongType)
+		err = sp.GetSessionData(context.Background(), session.ID, &wrongType)
 
 		assert.ErrorIs(t, err, ErrFailedToUnmarshalData)
 	})
@@ -175,34 +176,33 @@ func TestSetSessionData(t *testing.T) {
 			Score: 100,
 		}
 
-		_, err := sp.CreateSession(accountID, initialData)
+		session, err := sp.CreateSession(context.Background(), accountID, initialData)
 		require.NoError(t, err)
 
-		err = sp.SetSessionData(accountID, expectedData)
+		err = sp.SetSessionData(context.Background(), session.ID, expectedData)
 		require.NoError(t, err)
 
 		var retrievedData TestData
-		err = sp.GetSessionData(accountID, &retrievedData)
+		err = sp.GetSessionData(context.Background(), session.ID, &retrievedData)
 		require.NoError(t, err)
 		assert.Equal(t, expectedData, retrievedData)
 	})
 
-	t.Run("non-existent account", func(t *testing.T) {
-		nonExistentAccountID := "non-existent-account"
+	t.Run("non-existent session", func(t *testing.T) {
 		testData := map[string]interface{}{"level": 1}
 
-		err := sp.SetSessionData(nonExistentAccountID, testData)
+		err := sp.SetSessionData(context.Background(), "non-existent-session", testData)
 
 		assert.ErrorIs(t, err, ErrSessionNotFound)
 	})
 
 	t.Run("marshal error", func(t *testing.T) {
 		accountID := "test-account-marshal"
-		_, err := sp.CreateSession(accountID, map[string]interface{}{"level": 1})
+		session, err := sp.CreateSession(context.Background(), accountID, map[string]interface{}{"level": 1})
 		require.NoError(t, err)
 
 		invalidData := make(chan int)
-		err = sp.SetSessionData(accountID, invalidData)
+		err = sp.SetSessionData(context.Background(), session.ID, invalidData)
 
 		assert.ErrorIs(t, err, ErrFailedToMarshalData)
 	})
@@ -218,17 +218,17 @@ func TestUpdateActivity(t *testing.T) {
 		accountID := "test-account-123"
 		testData := map[string]interface{}{"level": 1}
 
-		session, err := sp.CreateSession(accountID, testData)
+		session, err := sp.CreateSession(context.Background(), accountID, testData)
 		require.NoError(t, err)
 
 		originalActivity := session.LastActivity
 
 		time.Sleep(10 * time.Millisecond)
 
-		err = sp.UpdateActivity(session.ID)
+		err = sp.UpdateActivity(context.Background(), session.ID)
 		require.NoError(t, err)
 
-		updatedSession, exists := sp.GetSession(session.ID)
+		updatedSession, exists := sp.GetSession(context.Background(), session.ID)
 		require.True(t, exists)
 		assert.True(t, updatedSession.LastActivity.After(originalActivity))
 	})
@@ -236,7 +236,7 @@ func TestUpdateActivity(t *testing.T) {
 	t.Run("non-existent session", func(t *testing.T) {
 		nonExistentID := uuid.New().String()
 
-		err := sp.UpdateActivity(nonExistentID)
+		err := sp.UpdateActivity(context.Background(), nonExistentID)
 
 		assert.ErrorIs(t, err, ErrSessionNotFound)
 	})
@@ -245,12 +245,12 @@ func TestUpdateActivity(t *testing.T) {
 		accountID := "test-account-expired"
 		testData := map[string]interface{}{"level": 1}
 
-		session, err := sp.CreateSession(accountID, testData)
+		session, err := sp.CreateSession(context.Background(), accountID, testData)
 		require.NoError(t, err)
 
 		time.Sleep(150 * time.Millisecond)
 
-		err = sp.UpdateActivity(session.ID)
+		err = sp.UpdateActivity(context.Background(), session.ID)
 
 		assert.ErrorIs(t, err, ErrSessionExpired)
 	})
@@ -266,21 +266,21 @@ func TestRemoveSession(t *testing.T) {
 		accountID := "test-account-123"
 		testData := map[string]interface{}{"level": 1}
 
-		session, err := sp.CreateSession(accountID, testData)
+		session, err := sp.CreateSession(context.Background(), accountID, testData)
 		require.NoError(t, err)
 
-		_, exists := sp.GetSession(session.ID)
+		_, exists := sp.GetSession(context.Background(), session.ID)
 		assert.True(t, exists)
 
-		sp.RemoveSession(session.ID)
+		sp.RemoveSession(context.Background(), session.ID)
 
-		_, exists = sp.GetSession(session.ID)
+		_, exists = sp.GetSession(context.Background(), session.ID)
 		assert.False(t, exists)
 
-		err = sp.GetSessionData(accountID, &testData)
+		err = sp.GetSessionData(context.Background(), session.ID, &testData)
 		assert.ErrorIs(t, err, ErrSessionNotFound)
 
-		_, exists = sp.GetAccountID(session.ID)
+		_, exists = sp.GetAccountID(context.Background(), session.ID)
 		assert.False(t, exists)
 	})
 
@@ -288,7 +288,7 @@ func TestRemoveSession(t *testing.T) {
 		nonExistentID := uuid.New().String()
 
 		assert.NotPanics(t, func() {
-			sp.RemoveSession(nonExistentID)
+			sp.RemoveSession(context.Background(), nonExistentID)
 		})
 	})
 }
@@ -303,10 +303,10 @@ func TestGetAccountID(t *testing.T) {
 		accountID := "test-account-123"
 		testData := map[string]interface{}{"level": 1}
 
-		session, err := sp.CreateSession(accountID, testData)
+		session, err := sp.CreateSession(context.Background(), accountID, testData)
 		require.NoError(t, err)
 
-		retrievedAccountID, exists := sp.GetAccountID(session.ID)
+		retrievedAccountID, exists := sp.GetAccountID(context.Background(), session.ID)
 
 		assert.True(t, exists)
 		assert.Equal(t, accountID, retrievedAccountID)
@@ -315,7 +315,7 @@ func TestGetAccountID(t *testing.T) {
 	t.Run("non-existent session", func(t *testing.T) {
 		nonExistentID := uuid.New().String()
 
-		accountID, exists := sp.GetAccountID(nonExistentID)
+		accountID, exists := sp.GetAccountID(context.Background(), nonExistentID)
 
 		assert.False(t, exists)
 		assert.Empty(t, accountID)
@@ -325,12 +325,12 @@ func TestGetAccountID(t *testing.T) {
 		accountID := "test-account-expired"
 		testData := map[string]interface{}{"level": 1}
 
-		session, err := sp.CreateSession(accountID, testData)
+		session, err := sp.CreateSession(context.Background(), accountID, testData)
 		require.NoError(t, err)
 
 		time.Sleep(150 * time.Millisecond)
 
-		retrievedAccountID, exists := sp.GetAccountID(session.ID)
+		retrievedAccountID, exists := sp.GetAccountID(context.Background(), session.ID)
 
 		assert.False(t, exists)
 		assert.Empty(t, retrievedAccountID)
@@ -347,28 +347,28 @@ func TestCleanupExpiredSessions(t *testing.T) {
 	accountID2 := "test-account-2"
 	accountID3 := "test-account-3"
 
-	session1, err := sp.CreateSession(accountID1, map[string]interface{}{"level": 1})
+	session1, err := sp.CreateSession(context.Background(), accountID1, map[string]interface{}{"level": 1})
 	require.NoError(t, err)
 
-	session2, err := sp.CreateSession(accountID2, map[string]interface{}{"level": 2})
+	session2, err := sp.CreateSession(context.Background(), accountID2, map[string]interface{}{"level": 2})
 	require.NoError(t, err)
 
 	time.Sleep(100 * time.Millisecond)
 
-	session3, err := sp.CreateSession(accountID3, map[string]interface{}{"level": 3})
+	session3, err := sp.CreateSession(context.Background(), accountID3, map[string]interface{}{"level": 3})
 	require.NoError(t, err)
 
 	sp.CleanupExpiredSessions()
 
-	assert.Equal(t, 1, sp.GetSessionCount())
+	assert.Equal(t, 1, sessionCount(t, sp))
 
-	_, exists := sp.GetSession(session1.ID)
+	_, exists := sp.GetSession(context.Background(), session1.ID)
 	assert.False(t, exists)
 
-	_, exists = sp.GetSession(session2.ID)
+	_, exists = sp.GetSession(context.Background(), session2.ID)
 	assert.False(t, exists)
 
-	_, exists = sp.GetSession(session3.ID)
+	_, exists = sp.GetSession(context.Background(), session3.ID)
 	assert.True(t, exists)
 }
 
@@ -379,31 +379,31 @@ func TestGetSessionCount(t *testing.T) {
 	sp := NewSessionPool(config)
 
 	t.Run("empty pool", func(t *testing.T) {
-		count := sp.GetSessionCount()
+		count := sessionCount(t, sp)
 		assert.Equal(t, 0, count)
 	})
 
 	t.Run("with sessions", func(t *testing.T) {
 		accountIDs := []string{"account-1", "account-2", "account-3"}
 		for _, accountID := range accountIDs {
-			_, err := sp.CreateSession(accountID, map[string]interface{}{"level": 1})
+			_, err := sp.CreateSession(context.Background(), accountID, map[string]interface{}{"level": 1})
 			require.NoError(t, err)
 		}
 
-		count := sp.GetSessionCount()
+		count := sessionCount(t, sp)
 		assert.Equal(t, len(accountIDs), count)
 	})
 
 	t.Run("after removal", func(t *testing.T) {
 		accountID := "test-account-removal"
-		session, err := sp.CreateSession(accountID, map[string]interface{}{"level": 1})
+		session, err := sp.CreateSession(context.Background(), accountID, map[string]interface{}{"level": 1})
 		require.NoError(t, err)
 
-		initialCount := sp.GetSessionCount()
+		initialCount := sessionCount(t, sp)
 
-		sp.RemoveSession(session.ID)
+		sp.RemoveSession(context.Background(), session.ID)
 
-		finalCount := sp.GetSessionCount()
+		finalCount := sessionCount(t, sp)
 		assert.Equal(t, initialCount-1, finalCount)
 	})
 }
@@ -423,7 +423,7 @@ func TestConcurrentAccess(t *testing.T) {
 			wg.Add(1)
 			go func(accountID string) {
 				defer wg.Done()
-				_, err := sp.CreateSession(accountID, map[string]interface{}{"level": 1})
+				_, err := sp.CreateSession(context.Background(), accountID, map[string]interface{}{"level": 1})
 				errors <- err
 			}(fmt.Sprintf("account-%d", i))
 		}
@@ -435,12 +435,12 @@ func TestConcurrentAccess(t *testing.T) {
 			assert.NoError(t, err)
 		}
 
-		assert.Equal(t, numGoroutines, sp.GetSessionCount())
+		assert.Equal(t, numGoroutines, sessionCount(t, sp))
 	})
 
 	t.Run("concurrent read and write operations", func(t *testing.T) {
 		accountID := "concurrent-test-account"
-		session, err := sp.CreateSession(accountID, map[string]interface{}{"level": 1})
+		session, err := sp.CreateSession(context.Background(), accountID, map[string]interface{}{"level": 1})
 		require.NoError(t, err)
 
 		const numOperations = 50
@@ -451,7 +451,7 @@ func TestConcurrentAccess(t *testing.T) {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				_, exists := sp.GetSession(session.ID)
+				_, exists := sp.GetSession(context.Background(), session.ID)
 				if !exists {
 					errors <- fmt.Errorf("session not found during read")
 				} else {
@@ -464,7 +464,7 @@ func TestConcurrentAccess(t *testing.T) {
 			wg.Add(1)
 			go func(level int) {
 				defer wg.Done()
-				err := sp.SetSessionData(accountID, map[string]interface{}{"level": level})
+				err := sp.SetSessionData(context.Background(), session.ID, map[string]interface{}{"level": level})
 				errors <- err
 			}(i)
 		}
@@ -477,3 +477,289 @@ func TestConcurrentAccess(t *testing.T) {
 		}
 	})
 }
+
+func TestSubscribe(t *testing.T) {
+	sp := NewSessionPool(SessionPoolConfig{TTL: 100 * time.Millisecond})
+
+	var events []sessions.Event
+	unsubscribe := sp.Subscribe(func(event sessions.Event) {
+		// Subscribers run outside of the mutex, so they can use the pool.
+		sp.GetSessionCount()
+		events = append(events, event)
+	})
+	defer unsubscribe()
+
+	first, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+	second, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 2})
+	require.NoError(t, err)
+	sp.RemoveSession(context.Background(), second.ID)
+
+	third, err := sp.CreateSession(context.Background(), "account-2", TestData{Level: 3})
+	require.NoError(t, err)
+	time.Sleep(150 * time.Millisecond)
+	sp.CleanupExpiredSessions()
+
+	require.Len(t, events, 6)
+
+	expected := []struct {
+		eventType sessions.EventType
+		sessionID string
+		level     int
+	}{
+		{sessions.EventCreated, first.ID, 1},
+		{sessions.EventReplaced, first.ID, 1},
+		{sessions.EventCreated, second.ID, 2},
+		{sessions.EventRemoved, second.ID, 2},
+		{sessions.EventCreated, third.ID, 3},
+		{sessions.EventExpired, third.ID, 3},
+	}
+	for i, row := range expected {
+		assert.Equal(t, row.eventType, events[i].Type)
+		assert.Equal(t, row.sessionID, events[i].Session.ID)
+
+		var data TestData
+		require.NoError(t, events[i].DecodeData(&data))
+		assert.Equal(t, row.level, data.Level)
+	}
+}
+
+func TestCreateSession_WithRejectNewPolicy(t *testing.T) {
+	sp := NewSessionPool(SessionPoolConfig{TTL: 50 * time.Millisecond, Policy: sessions.PolicyRejectNew})
+
+	first, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+
+	_, err = sp.CreateSession(context.Background(), "account-1", TestData{Level: 2})
+	assert.ErrorIs(t, err, sessions.ErrSessionLimitReached)
+
+	_, exists := sp.GetSession(context.Background(), first.ID)
+	assert.True(t, exists)
+
+	time.Sleep(100 * time.Millisecond)
+
+	second, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 2})
+	require.NoError(t, err)
+
+	_, exists = sp.GetSession(context.Background(), second.ID)
+	assert.True(t, exists)
+	assert.Equal(t, 1, sessionCount(t, sp))
+}
+
+func TestCreateSession_WithConcurrentPolicy(t *testing.T) {
+	sp := NewSessionPool(SessionPoolConfig{TTL: time.Minute, Policy: sessions.PolicyConcurrent, MaxSessions: 2})
+
+	var events []sessions.Event
+	sp.Subscribe(func(event sessions.Event) {
+		if event.Type != sessions.EventCreated {
+			events = append(events, event)
+		}
+	})
+
+	first, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	second, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 2})
+	require.NoError(t, err)
+
+	t.Run("sessions have their own data", func(t *testing.T) {
+		require.NoError(t, sp.SetSessionData(context.Background(), second.ID, TestData{Level: 3}))
+
+		var data TestData
+		require.NoError(t, sp.GetSessionData(context.Background(), first.ID, &data))
+		assert.Equal(t, TestData{Level: 1}, data)
+		require.NoError(t, sp.GetSessionData(context.Background(), second.ID, &data))
+		assert.Equal(t, TestData{Level: 3}, data)
+		assert.Equal(t, 2, sessionCount(t, sp))
+	})
+
+	t.Run("least recently active session is replaced past the limit", func(t *testing.T) {
+		time.Sleep(time.Millisecond)
+		require.NoError(t, sp.UpdateActivity(context.Background(), first.ID))
+
+		third, err := sp.CreateSession(context.Background(), "account-1", nil)
+		require.NoError(t, err)
+
+		_, exists := sp.GetSession(context.Background(), second.ID)
+		assert.False(t, exists)
+		_, exists = sp.GetSession(context.Background(), first.ID)
+		assert.True(t, exists)
+		_, exists = sp.GetSession(context.Background(), third.ID)
+		assert.True(t, exists)
+
+		require.Len(t, events, 1)
+		assert.Equal(t, sessions.EventReplaced, events[0].Type)
+		var data TestData
+		require.NoError(t, events[0].DecodeData(&data))
+		assert.Equal(t, TestData{Level: 3}, data)
+	})
+
+	t.Run("data is dropped with its session", func(t *testing.T) {
+		events = nil
+		sp.RemoveSession(context.Background(), first.ID)
+
+		require.Len(t, events, 1)
+		var data TestData
+		require.NoError(t, events[0].DecodeData(&data))
+		assert.Equal(t, TestData{Level: 1}, data)
+		assert.ErrorIs(t, sp.GetSessionData(context.Background(), first.ID, &data), ErrSessionNotFound)
+		assert.Len(t, sp.sessionIDsByAccountID["account-1"], 1)
+
+		for _, sessionID := range slices.Clone(sp.sessionIDsByAccountID["account-1"]) {
+			sp.RemoveSession(context.Background(), sessionID)
+		}
+		assert.NotContains(t, sp.sessionIDsByAccountID, "account-1")
+		assert.Empty(t, sp.sessionsData)
+	})
+}
+
+func TestGetTombstone(t *testing.T) {
+	sp := NewSessionPool(SessionPoolConfig{TTL: 50 * time.Millisecond, TombstoneTTL: 200 * time.Millisecond})
+
+	replaced, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	loggedOut, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	sp.RemoveSession(context.Background(), loggedOut.ID)
+
+	revoked, err := sp.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+	sp.RevokeSessions(context.Background(), "account-2")
+
+	expired, err := sp.CreateSession(context.Background(), "account-3", nil)
+	require.NoError(t, err)
+
+	_, found := sp.GetTombstone(context.Background(), expired.ID)
+	assert.False(t, found)
+
+	time.Sleep(100 * time.Millisecond)
+
+	table := map[string]struct {
+		sessionID string
+		accountID string
+		reason    sessions.EndReason
+	}{
+		"replaced":   {replaced.ID, "account-1", sessions.EndReasonReplaced},
+		"logged out": {loggedOut.ID, "account-1", sessions.EndReasonLoggedOut},
+		"revoked":    {revoked.ID, "account-2", sessions.EndReasonRevoked},
+		"expired":    {expired.ID, "account-3", sessions.EndReasonExpired},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			tombstone, found := sp.GetTombstone(context.Background(), row.sessionID)
+			require.True(t, found)
+			assert.Equal(t, row.sessionID, tombstone.SessionID)
+			assert.Equal(t, row.accountID, tombstone.AccountID)
+			assert.Equal(t, row.reason, tombstone.Reason)
+		})
+	}
+
+	t.Run("expired session keeps its tombstone once cleaned up", func(t *testing.T) {
+		sp.CleanupExpiredSessions()
+
+		tombstone, found := sp.GetTombstone(context.Background(), expired.ID)
+		require.True(t, found)
+		assert.Equal(t, sessions.EndReasonExpired, tombstone.Reason)
+		assert.Equal(t, expired.LastActivity.Add(50*time.Millisecond), tombstone.EndedAt)
+	})
+
+	t.Run("old tombstones are forgotten", func(t *testing.T) {
+		time.Sleep(250 * time.Millisecond)
+		sp.CleanupExpiredSessions()
+
+		for _, row := range table {
+			_, found := sp.GetTombstone(context.Background(), row.sessionID)
+			assert.False(t, found)
+		}
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		_, found := sp.GetTombstone(context.Background(), uuid.New().String())
+		assert.False(t, found)
+	})
+}
+
+func TestGetTombstone_Disabled(t *testing.T) {
+	sp := NewSessionPool(SessionPoolConfig{TTL: time.Minute})
+
+	first, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	_, err = sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	_, found := sp.GetTombstone(context.Background(), first.ID)
+	assert.False(t, found)
+	assert.Empty(t, sp.tombstones)
+}
+
+func TestRevokeSessions(t *testing.T) {
+	sp := NewSessionPool(SessionPoolConfig{TTL: time.Minute, Policy: sessions.PolicyConcurrent, MaxSessions: 2})
+
+	var events []sessions.Event
+	sp.Subscribe(func(event sessions.Event) {
+		if event.Type == sessions.EventRevoked {
+			events = append(events, event)
+		}
+	})
+
+	first, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+	second, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	other, err := sp.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+
+	sp.RevokeSessions(context.Background(), "account-1")
+
+	_, exists := sp.GetSession(context.Background(), first.ID)
+	assert.False(t, exists)
+	_, exists = sp.GetSession(context.Background(), second.ID)
+	assert.False(t, exists)
+	_, exists = sp.GetSession(context.Background(), other.ID)
+	assert.True(t, exists)
+
+	require.Len(t, events, 2)
+	var data TestData
+	require.NoError(t, events[0].DecodeData(&data))
+	assert.Equal(t, TestData{Level: 1}, data)
+	assert.Equal(t, second.ID, events[1].Session.ID)
+}
+
+func TestSessionPool_WithDoneContext(t *testing.T) {
+	sp := NewSessionPool(SessionPoolConfig{TTL: time.Minute, TombstoneTTL: time.Minute})
+	session, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = sp.CreateSession(ctx, "account-1", TestData{Level: 2})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, found := sp.GetAccountID(ctx, session.ID)
+	assert.False(t, found)
+
+	assert.ErrorIs(t, sp.UpdateActivity(ctx, session.ID), context.Canceled)
+	assert.ErrorIs(t, sp.SetSessionData(ctx, session.ID, TestData{Level: 3}), context.Canceled)
+
+	var data TestData
+	assert.ErrorIs(t, sp.GetSessionData(ctx, session.ID, &data), context.Canceled)
+
+	sp.RemoveSession(ctx, session.ID)
+	sp.RevokeSessions(ctx, "account-1")
+
+	// Nothing was changed by the calls with the done context.
+	accountID, found := sp.GetAccountID(context.Background(), session.ID)
+	assert.True(t, found)
+	assert.Equal(t, "account-1", accountID)
+	require.NoError(t, sp.GetSessionData(context.Background(), session.ID, &data))
+	assert.Equal(t, TestData{Level: 1}, data)
+}
+
+func sessionCount(t *testing.T, sp *SessionPool) int {
+	t.Helper()
+	count, err := sp.GetSessionCount()
+	require.NoError(t, err)
+	return count
+}
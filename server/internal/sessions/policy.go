@@ -0,0 +1,24 @@
+package sessions
+
+import "technical-test-backend/internal/errors"
+
+const ErrorCodeSessionLimitReached = "SESSION_LIMIT_REACHED"
+
+var (
+	ErrSessionLimitReached = errors.NewCoded(ErrorCodeSessionLimitReached, "session limit reached")
+)
+
+// Policy decides what happens when an account that already has sessions logs
+// in again.
+type Policy string
+
+const (
+	// PolicyKickOld replaces the existing session, it's the default.
+	PolicyKickOld Policy = "kick-old"
+	// PolicyRejectNew fails the new login with ErrSessionLimitReached while a
+	// session is alive.
+	PolicyRejectNew Policy = "reject-new"
+	// PolicyConcurrent allows several sessions, each with its own session data,
+	// replacing the least recently active one past the limit.
+	PolicyConcurrent Policy = "concurrent"
+)
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"technical-test-backend/internal/lifecycle"
+	"technical-test-backend/internal/resp"
+	"technical-test-backend/internal/worker"
+)
+
+// CreateSessionPool registers the closing of its connections on the
+// lifecycle, and the worker sweeping the expired sessions when enabled.
+func CreateSessionPool(lc *lifecycle.Lifecycle, config SessionPoolConfig) *SessionPool {
+	client := resp.NewClient(config.Client)
+	sp := NewSessionPool(client, config)
+
+	lc.Append(lifecycle.Hook{
+		Name: "redis sessions client",
+		OnStop: func(ctx context.Context) error {
+			return client.Close()
+		},
+	})
+
+	if config.SweepInterval > 0 {
+		sweepWorker := worker.New(worker.Config{
+			Interval: config.SweepInterval,
+		}, func() {
+			sp.SweepExpiredSessions()
+		})
+
+		lc.Append(lifecycle.Hook{
+			Name: "redis sessions sweep",
+			OnStart: func(ctx context.Context) error {
+				sweepWorker.Start()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				sweepWorker.Stop()
+				return nil
+			},
+		})
+	}
+
+	return sp
+}
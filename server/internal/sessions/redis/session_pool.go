@@ -0,0 +1,663 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/resp"
+	"technical-test-backend/internal/sessions"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionNotFound       = errors.New("session not found")
+	ErrFailedToMarshalData   = errors.New("failed to marshal data")
+	ErrFailedToUnmarshalData = errors.New("failed to unmarshal data")
+	ErrTooManyConflicts      = errors.New("too many conflicting session updates")
+)
+
+// maxConflictRetries bounds the retries of a transaction whose watched keys
+// were changed concurrently. Retries wait up to conflictBackoff times the
+// attempt, to spread concurrent logins apart.
+const (
+	maxConflictRetries = 10
+	conflictBackoff    = 10 * time.Millisecond
+)
+
+type SessionPoolConfig struct {
+	Client resp.ClientConfig
+	// KeyPrefix namespaces the keys, to share a database.
+	KeyPrefix string
+	TTL       time.Duration
+	// TombstoneTTL is how long the reason of the end of a session is kept.
+	// Zero disables tombstones.
+	TombstoneTTL time.Duration
+	// SweepInterval is how often the sessions expired by the server are looked
+	// for in the expiry index, to report them to the subscribers. Their data is
+	// kept for two intervals past their expiry until then. Zero disables it,
+	// the index being trimmed by the logins instead.
+	SweepInterval time.Duration
+}
+
+// SessionPool stores sessions in a Redis-compatible server, so every instance
+// sees the same sessions. Every key expires after TTL unless refreshed by some
+// activity, so no cleanup worker is needed. The keys are:
+//   - session:<session id>: the JSON encoded session
+//   - data:<session id>: the session data
+//   - account:<account id>: the ID of the account's session
+//   - tombstone:<session id>: why the session ended. It's written as expired
+//     along with the session but outlives it by TombstoneTTL, so sessions
+//     expired by the server get one too.
+//   - expiry:<session id>: the JSON encoded session, written when sweeps are
+//     enabled. It outlives the session along with its data, until
+//     SweepExpiredSessions reports its expiry.
+//   - expiries: the IDs of the sessions by expiry, in milliseconds, for the
+//     sweeps to find the expired sessions without scanning the keys.
+type SessionPool struct {
+	client    *resp.Client
+	config    SessionPoolConfig
+	publisher *sessions.Publisher
+}
+
+func NewSessionPool(client *resp.Client, config SessionPoolConfig) *SessionPool {
+	return &SessionPool{
+		client:    client,
+		config:    config,
+		publisher: sessions.NewPublisher(),
+	}
+}
+
+// CreateSession replaces the account's session atomically: the session ID of
+// the account is watched, and the transaction retried if another login
+// changed it in between.
+func (sp *SessionPool) CreateSession(ctx context.Context, accountID string, data interface{}) (sessions.Session, error) {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return sessions.Session{}, errors.Wrap(err, ErrFailedToMarshalData)
+	}
+
+	sess := sessions.Session{
+		ID:           uuid.New().String(),
+		AccountID:    accountID,
+		LastActivity: time.Now(),
+		CreatedAt:    time.Now(),
+	}
+
+	rawSession, err := json.Marshal(sess)
+	if err != nil {
+		return sessions.Session{}, errors.Wrap(err, ErrFailedToMarshalData)
+	}
+
+	ttl := sp.ttl()
+	accountKey := sp.accountKey(accountID)
+
+	for attempt := range maxConflictRetries {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(rand.Int64N(int64(attempt) * int64(conflictBackoff)))):
+			case <-ctx.Done():
+				return sessions.Session{}, ctx.Err()
+			}
+		}
+
+		var replaced *sessions.Event
+		committed := false
+
+		err := sp.client.WithConn(ctx, func(conn *resp.Conn) error {
+			if _, err := conn.Do("WATCH", accountKey); err != nil {
+				return err
+			}
+
+			existingSessionID, err := resp.String(conn.Do("GET", accountKey))
+			if err != nil && !errors.Is(err, resp.ErrNil) {
+				return err
+			}
+
+			commands := [][]string{}
+			if existingSessionID != "" {
+				replaced, err = sp.watchRemovedSession(conn, existingSessionID, sessions.EventReplaced)
+				if err != nil {
+					return err
+				}
+				// An expired session is left to the sweep, to report it.
+				if replaced != nil {
+					commands = append(commands, sp.deleteSessionCommands(existingSessionID)...)
+					commands, err = sp.appendTombstone(commands, *replaced)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			commands = append(commands,
+				[]string{"SET", sp.sessionKey(sess.ID), string(rawSession), "PX", ttl},
+				[]string{"SET", sp.dataKey(sess.ID), string(rawData), "PX", sp.dataTTL()},
+				[]string{"SET", accountKey, sess.ID, "PX", ttl},
+				sp.indexExpiryCommand(sess.ID),
+			)
+			if sp.config.SweepInterval > 0 {
+				commands = append(commands, []string{"SET", sp.expiryKey(sess.ID), string(rawSession), "PX", sp.dataTTL()})
+			} else {
+				// Nothing sweeps the index, so the expired sessions are dropped
+				// from it here.
+				commands = append(commands, []string{"ZREMRANGEBYSCORE", sp.expiriesKey(), "-inf", "(" + strconv.FormatInt(time.Now().UnixMilli(), 10)})
+			}
+			commands, err = sp.appendTombstone(commands, sessions.Event{Type: sessions.EventExpired, Session: sess})
+			if err != nil {
+				return err
+			}
+
+			committed, err = conn.Transaction(commands)
+			return err
+		})
+		if err != nil {
+			return sessions.Session{}, err
+		}
+
+		if committed {
+			var events []sessions.Event
+			if replaced != nil {
+				log.Printf("Removed existing session: %s", replaced.Session.ID)
+				events = append(events, *replaced)
+			}
+			log.Printf("Created session: %s", sess.ID)
+
+			events = append(events, sessions.Event{Type: sessions.EventCreated, Session: sess, Data: rawData})
+			sp.publisher.Publish(events...)
+			return sess, nil
+		}
+	}
+
+	return sessions.Session{}, ErrTooManyConflicts
+}
+
+func (sp *SessionPool) GetSession(ctx context.Context, sessionID string) (sessions.Session, bool) {
+	sess, err := sp.getSession(ctx, sessionID)
+	if err != nil {
+		return sessions.Session{}, false
+	}
+
+	return sess, true
+}
+
+func (sp *SessionPool) GetSessionData(ctx context.Context, sessionID string, data interface{}) error {
+	// The data outlives its session until it's swept.
+	if sp.config.SweepInterval > 0 {
+		if _, err := sp.getSession(ctx, sessionID); errors.Is(err, ErrSessionNotFound) {
+			return errors.Wrap(err, sessions.ErrSessionDataMissing)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	rawData, err := resp.String(sp.client.Do(ctx, "GET", sp.dataKey(sessionID)))
+	if errors.Is(err, resp.ErrNil) {
+		return errors.Wrap(ErrSessionNotFound, sessions.ErrSessionDataMissing)
+	} else if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(rawData), data); err != nil {
+		return errors.Wrap(err, ErrFailedToUnmarshalData)
+	}
+
+	return nil
+}
+
+// SetSessionData only overwrites existing data, so it can't bring back the
+// data of a session removed in the meantime.
+func (sp *SessionPool) SetSessionData(ctx context.Context, sessionID string, data interface{}) error {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToMarshalData)
+	}
+
+	reply, err := sp.client.Do(ctx, "SET", sp.dataKey(sessionID), string(rawData), "XX", "KEEPTTL")
+	if err != nil {
+		return err
+	}
+
+	if reply == nil {
+		return errors.Wrap(ErrSessionNotFound, sessions.ErrSessionDataMissing)
+	}
+
+	return nil
+}
+
+// UpdateActivity refreshes the session and extends the expiry of its keys.
+func (sp *SessionPool) UpdateActivity(ctx context.Context, sessionID string) error {
+	sess, err := sp.getSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	sess.LastActivity = time.Now()
+	rawSession, err := json.Marshal(sess)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToMarshalData)
+	}
+
+	ttl := sp.ttl()
+	return sp.client.WithConn(ctx, func(conn *resp.Conn) error {
+		accountKey := sp.accountKey(sess.AccountID)
+		if _, err := conn.Do("WATCH", accountKey); err != nil {
+			return err
+		}
+
+		currentSessionID, err := resp.String(conn.Do("GET", accountKey))
+		if err != nil && !errors.Is(err, resp.ErrNil) {
+			return err
+		}
+
+		if currentSessionID != sessionID {
+			return ErrSessionNotFound
+		}
+
+		commands := [][]string{
+			{"SET", sp.sessionKey(sessionID), string(rawSession), "XX", "PX", ttl},
+			{"PEXPIRE", sp.dataKey(sessionID), sp.dataTTL()},
+			{"PEXPIRE", accountKey, ttl},
+			sp.indexExpiryCommand(sessionID),
+		}
+		if sp.config.SweepInterval > 0 {
+			commands = append(commands, []string{"PEXPIRE", sp.expiryKey(sessionID), sp.dataTTL()})
+		}
+		if sp.config.TombstoneTTL > 0 {
+			commands = append(commands, []string{"PEXPIRE", sp.tombstoneKey(sessionID), sp.expiredTombstoneTTL()})
+		}
+
+		committed, err := conn.Transaction(commands)
+		if err != nil {
+			return err
+		}
+
+		if !committed {
+			return ErrSessionNotFound
+		}
+
+		return nil
+	})
+}
+
+func (sp *SessionPool) RemoveSession(ctx context.Context, sessionID string) {
+	sess, err := sp.getSession(ctx, sessionID)
+	if err != nil {
+		return
+	}
+
+	sp.removeSession(ctx, sess.AccountID, sessionID, sessions.EventRemoved)
+}
+
+// RevokeSessions removes the session of the account.
+func (sp *SessionPool) RevokeSessions(ctx context.Context, accountID string) {
+	sessionID, err := sp.getAccountSessionID(ctx, accountID)
+	if err != nil {
+		if !errors.Is(err, ErrSessionNotFound) {
+			log.Printf("Failed to revoke sessions of account %s: %v", accountID, err)
+		}
+		return
+	}
+
+	sp.removeSession(ctx, accountID, sessionID, sessions.EventRevoked)
+	log.Printf("Revoked session: %s", sessionID)
+}
+
+// GetTombstone reads the tombstone of the session. An expired tombstone whose
+// session is still alive is ignored.
+func (sp *SessionPool) GetTombstone(ctx context.Context, sessionID string) (sessions.Tombstone, bool) {
+	if sp.config.TombstoneTTL <= 0 {
+		return sessions.Tombstone{}, false
+	}
+
+	tombstoneKey := sp.tombstoneKey(sessionID)
+	rawTombstone, err := resp.String(sp.client.Do(ctx, "GET", tombstoneKey))
+	if err != nil {
+		if !errors.Is(err, resp.ErrNil) {
+			log.Printf("Failed to get tombstone of session %s: %v", sessionID, err)
+		}
+		return sessions.Tombstone{}, false
+	}
+
+	var tombstone sessions.Tombstone
+	if err := json.Unmarshal([]byte(rawTombstone), &tombstone); err != nil {
+		log.Printf("Failed to get tombstone of session %s: %v", sessionID, errors.Wrap(err, ErrFailedToUnmarshalData))
+		return sessions.Tombstone{}, false
+	}
+
+	if tombstone.Reason != sessions.EndReasonExpired {
+		return tombstone, true
+	}
+
+	remaining, err := resp.Int(sp.client.Do(ctx, "PTTL", tombstoneKey))
+	if err != nil || remaining < 0 {
+		return sessions.Tombstone{}, false
+	}
+
+	tombstone.EndedAt = time.Now().Add(time.Duration(remaining)*time.Millisecond - sp.config.TombstoneTTL)
+	if tombstone.EndedAt.After(time.Now()) {
+		return sessions.Tombstone{}, false
+	}
+
+	return tombstone, true
+}
+
+// removeSession removes a session of the account, retrying on conflicts.
+func (sp *SessionPool) removeSession(ctx context.Context, accountID string, sessionID string, eventType sessions.EventType) {
+	accountKey := sp.accountKey(accountID)
+
+	for range maxConflictRetries {
+		var removed *sessions.Event
+		committed := false
+
+		err := sp.client.WithConn(ctx, func(conn *resp.Conn) error {
+			if _, err := conn.Do("WATCH", accountKey); err != nil {
+				return err
+			}
+
+			currentSessionID, err := resp.String(conn.Do("GET", accountKey))
+			if err != nil && !errors.Is(err, resp.ErrNil) {
+				return err
+			}
+
+			removed, err = sp.watchRemovedSession(conn, sessionID, eventType)
+			if err != nil {
+				return err
+			}
+
+			commands := [][]string{}
+			if currentSessionID == sessionID {
+				commands = append(commands, []string{"DEL", accountKey})
+			}
+			// An expired session is left to the sweep, to report it.
+			if removed != nil {
+				commands = append(commands, sp.deleteSessionCommands(sessionID)...)
+				commands, err = sp.appendTombstone(commands, *removed)
+				if err != nil {
+					return err
+				}
+			}
+
+			committed, err = conn.Transaction(commands)
+			return err
+		})
+		if err != nil {
+			log.Printf("Failed to remove session %s: %v", sessionID, err)
+			return
+		}
+
+		if committed {
+			if removed != nil {
+				sp.publisher.Publish(*removed)
+			}
+			return
+		}
+	}
+
+	log.Printf("Failed to remove session %s: %v", sessionID, ErrTooManyConflicts)
+}
+
+// Subscribe registers a subscriber to the sessions created, replaced and
+// removed by this instance, and to the expired ones it swept.
+func (sp *SessionPool) Subscribe(subscriber sessions.Subscriber) func() {
+	return sp.publisher.Subscribe(subscriber)
+}
+
+// SweepExpiredSessions reports the sessions expired by the server since the
+// last sweep, along with their last data, looking them up in the expiry index
+// by the clock of the server, which expires their keys. Every instance sweeps,
+// the expiry of a session being reported by the one removing it from the
+// index.
+func (sp *SessionPool) SweepExpiredSessions() {
+	ctx := context.Background()
+	now, err := sp.serverTime(ctx)
+	if err != nil {
+		log.Printf("Failed to sweep expired sessions: %v", err)
+		return
+	}
+
+	sessionIDs, err := resp.Strings(sp.client.Do(ctx, "ZRANGEBYSCORE", sp.expiriesKey(), "-inf", strconv.FormatInt(now.UnixMilli(), 10)))
+	if err != nil {
+		log.Printf("Failed to sweep expired sessions: %v", err)
+		return
+	}
+
+	for _, sessionID := range sessionIDs {
+		if err := sp.sweepSession(ctx, sessionID); err != nil {
+			log.Printf("Failed to sweep session %s: %v", sessionID, err)
+		}
+	}
+}
+
+// sweepSession deletes the keys left by a session expired by the server, and
+// reports its expiry. It's removed from the index in a transaction watching
+// its keys, so nothing is done if another instance swept it or if it was
+// refreshed in between.
+func (sp *SessionPool) sweepSession(ctx context.Context, sessionID string) error {
+	var expired *sessions.Event
+	committed := false
+
+	err := sp.client.WithConn(ctx, func(conn *resp.Conn) error {
+		sessionKey, dataKey, expiryKey := sp.sessionKey(sessionID), sp.dataKey(sessionID), sp.expiryKey(sessionID)
+		if _, err := conn.Do("WATCH", sessionKey, dataKey, expiryKey); err != nil {
+			return err
+		}
+
+		if _, err := resp.String(conn.Do("GET", sessionKey)); !errors.Is(err, resp.ErrNil) {
+			return err
+		}
+
+		claim := []string{"ZREM", sp.expiriesKey(), sessionID}
+		rawSession, err := resp.String(conn.Do("GET", expiryKey))
+		if errors.Is(err, resp.ErrNil) {
+			// Left by a session swept past its data, or when sweeps were
+			// disabled: there's nothing to report.
+			_, err = conn.Transaction([][]string{claim})
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		expired = &sessions.Event{Type: sessions.EventExpired}
+		if err := json.Unmarshal([]byte(rawSession), &expired.Session); err != nil {
+			return errors.Wrap(err, ErrFailedToUnmarshalData)
+		}
+
+		rawData, err := resp.String(conn.Do("GET", dataKey))
+		if err != nil && !errors.Is(err, resp.ErrNil) {
+			return err
+		}
+		if rawData != "" {
+			expired.Data = json.RawMessage(rawData)
+		}
+
+		committed, err = conn.Transaction([][]string{{"DEL", dataKey, expiryKey}, claim})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if committed {
+		log.Printf("Removed expired session: %s", sessionID)
+		sp.publisher.Publish(*expired)
+	}
+
+	return nil
+}
+
+// serverTime returns the clock of the server.
+func (sp *SessionPool) serverTime(ctx context.Context) (time.Time, error) {
+	reply, err := resp.Strings(sp.client.Do(ctx, "TIME"))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(reply) != 2 {
+		return time.Time{}, errors.Wrapf(resp.ErrUnexpectedType, "TIME reply of %d items", len(reply))
+	}
+
+	seconds, err := strconv.ParseInt(reply[0], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	micros, err := strconv.ParseInt(reply[1], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(seconds, micros*int64(time.Microsecond)), nil
+}
+
+// watchRemovedSession reads a session about to be removed in a transaction,
+// watching it so the transaction fails if it changes in between. It returns
+// nil if the session is already gone.
+func (sp *SessionPool) watchRemovedSession(conn *resp.Conn, sessionID string, eventType sessions.EventType) (*sessions.Event, error) {
+	sessionKey, dataKey := sp.sessionKey(sessionID), sp.dataKey(sessionID)
+	if _, err := conn.Do("WATCH", sessionKey, dataKey); err != nil {
+		return nil, err
+	}
+
+	rawSession, err := resp.String(conn.Do("GET", sessionKey))
+	if errors.Is(err, resp.ErrNil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	event := &sessions.Event{Type: eventType}
+	if err := json.Unmarshal([]byte(rawSession), &event.Session); err != nil {
+		return nil, errors.Wrap(err, ErrFailedToUnmarshalData)
+	}
+
+	rawData, err := resp.String(conn.Do("GET", dataKey))
+	if err != nil && !errors.Is(err, resp.ErrNil) {
+		return nil, err
+	}
+	if rawData != "" {
+		event.Data = json.RawMessage(rawData)
+	}
+
+	return event, nil
+}
+
+func (sp *SessionPool) GetAccountID(ctx context.Context, sessionID string) (string, bool) {
+	sess, err := sp.getSession(ctx, sessionID)
+	if err != nil {
+		return "", false
+	}
+
+	return sess.AccountID, true
+}
+
+// GetSessionCount returns the number of live sessions across all instances,
+// counted in the expiry index.
+func (sp *SessionPool) GetSessionCount() (int, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	count, err := resp.Int(sp.client.Do(context.Background(), "ZCOUNT", sp.expiriesKey(), "("+now, "+inf"))
+	return int(count), err
+}
+
+func (sp *SessionPool) getSession(ctx context.Context, sessionID string) (sessions.Session, error) {
+	rawSession, err := resp.String(sp.client.Do(ctx, "GET", sp.sessionKey(sessionID)))
+	if errors.Is(err, resp.ErrNil) {
+		return sessions.Session{}, ErrSessionNotFound
+	} else if err != nil {
+		return sessions.Session{}, err
+	}
+
+	var sess sessions.Session
+	if err := json.Unmarshal([]byte(rawSession), &sess); err != nil {
+		return sessions.Session{}, errors.Wrap(err, ErrFailedToUnmarshalData)
+	}
+
+	return sess, nil
+}
+
+func (sp *SessionPool) getAccountSessionID(ctx context.Context, accountID string) (string, error) {
+	sessionID, err := resp.String(sp.client.Do(ctx, "GET", sp.accountKey(accountID)))
+	if errors.Is(err, resp.ErrNil) {
+		return "", ErrSessionNotFound
+	}
+
+	return sessionID, err
+}
+
+// appendTombstone appends the command writing the tombstone of the session
+// ended by the event. The tombstone of a session that may expire is written
+// ahead of time, without its end.
+func (sp *SessionPool) appendTombstone(commands [][]string, event sessions.Event) ([][]string, error) {
+	if sp.config.TombstoneTTL <= 0 {
+		return commands, nil
+	}
+
+	ttl := strconv.FormatInt(sp.config.TombstoneTTL.Milliseconds(), 10)
+	endedAt := time.Now()
+	if event.Type == sessions.EventExpired {
+		ttl = sp.expiredTombstoneTTL()
+		endedAt = time.Time{}
+	}
+
+	tombstone, _ := sessions.NewTombstone(event, endedAt)
+	rawTombstone, err := json.Marshal(tombstone)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrFailedToMarshalData)
+	}
+
+	return append(commands, []string{"SET", sp.tombstoneKey(event.Session.ID), string(rawTombstone), "PX", ttl}), nil
+}
+
+func (sp *SessionPool) expiredTombstoneTTL() string {
+	return strconv.FormatInt((sp.config.TTL + sp.config.TombstoneTTL).Milliseconds(), 10)
+}
+
+func (sp *SessionPool) ttl() string {
+	return strconv.FormatInt(sp.config.TTL.Milliseconds(), 10)
+}
+
+// dataTTL is the expiry of the data of a session, which outlives the session
+// until it's swept when sweeps are enabled.
+func (sp *SessionPool) dataTTL() string {
+	return strconv.FormatInt((sp.config.TTL + 2*sp.config.SweepInterval).Milliseconds(), 10)
+}
+
+func (sp *SessionPool) deleteSessionCommands(sessionID string) [][]string {
+	return [][]string{
+		{"DEL", sp.sessionKey(sessionID), sp.dataKey(sessionID), sp.expiryKey(sessionID)},
+		{"ZREM", sp.expiriesKey(), sessionID},
+	}
+}
+
+// indexExpiryCommand sets the expiry of the session in the index, a TTL from
+// now.
+func (sp *SessionPool) indexExpiryCommand(sessionID string) []string {
+	expiresAt := time.Now().Add(sp.config.TTL).UnixMilli()
+	return []string{"ZADD", sp.expiriesKey(), strconv.FormatInt(expiresAt, 10), sessionID}
+}
+
+func (sp *SessionPool) sessionKey(sessionID string) string {
+	return sp.config.KeyPrefix + "session:" + sessionID
+}
+
+func (sp *SessionPool) dataKey(sessionID string) string {
+	return sp.config.KeyPrefix + "data:" + sessionID
+}
+
+func (sp *SessionPool) accountKey(accountID string) string {
+	return sp.config.KeyPrefix + "account:" + accountID
+}
+
+func (sp *SessionPool) tombstoneKey(sessionID string) string {
+	return sp.config.KeyPrefix + "tombstone:" + sessionID
+}
+
+func (sp *SessionPool) expiryKey(sessionID string) string {
+	return sp.config.KeyPrefix + "expiry:" + sessionID
+}
+
+func (sp *SessionPool) expiriesKey() string {
+	return sp.config.KeyPrefix + "expiries"
+}
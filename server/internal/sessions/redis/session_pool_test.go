@@ -0,0 +1,443 @@
+//go:build unit
+// +build unit
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/lifecycle"
+	"technical-test-backend/internal/resp"
+	"technical-test-backend/internal/resp/fake"
+	"technical-test-backend/internal/sessions"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestData struct {
+	Level int
+	Score float64
+}
+
+func newTestSessionPool(t *testing.T, ttl time.Duration) (*SessionPool, *fake.Server) {
+	return newTestSessionPoolWithTombstones(t, ttl, 0)
+}
+
+func newTestSessionPoolWithTombstones(t *testing.T, ttl time.Duration, tombstoneTTL time.Duration) (*SessionPool, *fake.Server) {
+	server, err := fake.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	return newTestSessionPoolOnServer(t, server, SessionPoolConfig{TTL: ttl, TombstoneTTL: tombstoneTTL}), server
+}
+
+func newTestSessionPoolOnServer(t *testing.T, server *fake.Server, config SessionPoolConfig) *SessionPool {
+	config.Client = resp.ClientConfig{Address: server.Addr()}
+	config.KeyPrefix = "test:"
+
+	lc := lifecycle.New()
+	sp := CreateSessionPool(lc, config)
+	require.NoError(t, lc.Start(context.Background()))
+	t.Cleanup(func() { lc.Stop(context.Background()) })
+
+	return sp
+}
+
+func TestCreateSession(t *testing.T) {
+	sp, server := newTestSessionPool(t, time.Minute)
+
+	t.Run("successful creation", func(t *testing.T) {
+		session, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, session.ID)
+		assert.Equal(t, "account-1", session.AccountID)
+
+		var data TestData
+		require.NoError(t, sp.GetSessionData(context.Background(), session.ID, &data))
+		assert.Equal(t, TestData{Level: 1}, data)
+	})
+
+	t.Run("replaces existing session", func(t *testing.T) {
+		first, err := sp.CreateSession(context.Background(), "account-2", TestData{Level: 1})
+		require.NoError(t, err)
+		second, err := sp.CreateSession(context.Background(), "account-2", TestData{Level: 2})
+		require.NoError(t, err)
+
+		_, exists := sp.GetSession(context.Background(), first.ID)
+		assert.False(t, exists)
+		_, exists = sp.GetSession(context.Background(), second.ID)
+		assert.True(t, exists)
+
+		var data TestData
+		require.NoError(t, sp.GetSessionData(context.Background(), second.ID, &data))
+		assert.Equal(t, TestData{Level: 2}, data)
+		assert.NotContains(t, server.Keys(), "test:data:"+first.ID)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		session, err := sp.CreateSession(context.Background(), "account-3", make(chan int))
+
+		assert.ErrorIs(t, err, ErrFailedToMarshalData)
+		assert.Empty(t, session.ID)
+	})
+}
+
+func TestCreateSession_Concurrent_ShouldKeepOneSessionPerAccount(t *testing.T) {
+	sp, server := newTestSessionPool(t, time.Minute)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := sp.CreateSession(context.Background(), "account-1", nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, sessionCount(t, sp))
+	assert.Len(t, server.Keys(), 4)
+}
+
+func TestGetSession_Expired(t *testing.T) {
+	sp, server := newTestSessionPool(t, time.Minute)
+
+	session, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	server.FastForward(time.Minute)
+
+	_, exists := sp.GetSession(context.Background(), session.ID)
+	assert.False(t, exists)
+	_, exists = sp.GetAccountID(context.Background(), session.ID)
+	assert.False(t, exists)
+	assert.ErrorIs(t, sp.GetSessionData(context.Background(), session.ID, &TestData{}), ErrSessionNotFound)
+	// The expiry index is trimmed by the next login, without sweeps.
+	assert.Equal(t, []string{"test:expiries"}, server.Keys())
+}
+
+func TestSetSessionData(t *testing.T) {
+	sp, _ := newTestSessionPool(t, time.Minute)
+
+	t.Run("successful update", func(t *testing.T) {
+		session, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+		require.NoError(t, err)
+
+		require.NoError(t, sp.SetSessionData(context.Background(), session.ID, TestData{Level: 2, Score: 3}))
+
+		var data TestData
+		require.NoError(t, sp.GetSessionData(context.Background(), session.ID, &data))
+		assert.Equal(t, TestData{Level: 2, Score: 3}, data)
+	})
+
+	t.Run("non-existent session", func(t *testing.T) {
+		err := sp.SetSessionData(context.Background(), "non-existent-session", TestData{})
+
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
+
+func TestUpdateActivity(t *testing.T) {
+	sp, server := newTestSessionPool(t, time.Minute)
+
+	t.Run("extends expiry", func(t *testing.T) {
+		session, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+		require.NoError(t, err)
+
+		server.FastForward(40 * time.Second)
+		require.NoError(t, sp.UpdateActivity(context.Background(), session.ID))
+		server.FastForward(40 * time.Second)
+
+		retrieved, exists := sp.GetSession(context.Background(), session.ID)
+		assert.True(t, exists)
+		assert.True(t, retrieved.LastActivity.After(session.LastActivity))
+
+		var data TestData
+		assert.NoError(t, sp.GetSessionData(context.Background(), session.ID, &data))
+	})
+
+	t.Run("non-existent session", func(t *testing.T) {
+		err := sp.UpdateActivity(context.Background(), "non-existent-session")
+
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
+
+func TestRemoveSession(t *testing.T) {
+	sp, server := newTestSessionPool(t, time.Minute)
+
+	session, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	sp.RemoveSession(context.Background(), session.ID)
+
+	_, exists := sp.GetSession(context.Background(), session.ID)
+	assert.False(t, exists)
+	assert.Empty(t, server.Keys())
+
+	sp.RemoveSession(context.Background(), "non-existent-session")
+}
+
+func TestGetSessionCount(t *testing.T) {
+	sp, _ := newTestSessionPool(t, time.Minute)
+
+	assert.Equal(t, 0, sessionCount(t, sp))
+
+	for i := range 3 {
+		_, err := sp.CreateSession(context.Background(), fmt.Sprintf("account-%d", i), nil)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, sessionCount(t, sp))
+}
+
+func TestSubscribe(t *testing.T) {
+	sp, server := newTestSessionPool(t, time.Minute)
+
+	var events []sessions.Event
+	sp.Subscribe(func(event sessions.Event) {
+		events = append(events, event)
+	})
+
+	first, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+	require.NoError(t, sp.SetSessionData(context.Background(), first.ID, TestData{Level: 4}))
+	second, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 2})
+	require.NoError(t, err)
+	sp.RemoveSession(context.Background(), second.ID)
+	sp.RemoveSession(context.Background(), second.ID)
+
+	_, err = sp.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+	server.FastForward(time.Minute)
+
+	types := make([]sessions.EventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	assert.Equal(t, []sessions.EventType{
+		sessions.EventCreated, sessions.EventReplaced, sessions.EventCreated,
+		sessions.EventRemoved, sessions.EventCreated,
+	}, types)
+
+	assert.Equal(t, first.ID, events[1].Session.ID)
+	assert.Equal(t, "account-1", events[1].Session.AccountID)
+	assert.Equal(t, second.ID, events[3].Session.ID)
+
+	var data TestData
+	require.NoError(t, events[1].DecodeData(&data))
+	assert.Equal(t, 4, data.Level)
+}
+
+func TestSweepExpiredSessions(t *testing.T) {
+	server, err := fake.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	// The workers never run during the test, sweeps are done by hand.
+	config := SessionPoolConfig{TTL: time.Minute, SweepInterval: time.Hour}
+	sp := newTestSessionPoolOnServer(t, server, config)
+	other := newTestSessionPoolOnServer(t, server, config)
+
+	var mutex sync.Mutex
+	var events []sessions.Event
+	for _, pool := range []*SessionPool{sp, other} {
+		pool.Subscribe(func(event sessions.Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if event.Type == sessions.EventExpired {
+				events = append(events, event)
+			}
+		})
+	}
+
+	expired, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+	require.NoError(t, sp.SetSessionData(context.Background(), expired.ID, TestData{Level: 3}))
+	removed, err := sp.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+	sp.RemoveSession(context.Background(), removed.ID)
+
+	server.FastForward(30 * time.Second)
+	alive, err := sp.CreateSession(context.Background(), "account-3", nil)
+	require.NoError(t, err)
+	server.FastForward(40 * time.Second)
+
+	assert.ErrorIs(t, sp.GetSessionData(context.Background(), expired.ID, &TestData{}), ErrSessionNotFound)
+
+	var wg sync.WaitGroup
+	for _, pool := range []*SessionPool{sp, other} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.SweepExpiredSessions()
+		}()
+	}
+	wg.Wait()
+	sp.SweepExpiredSessions()
+
+	require.Len(t, events, 1)
+	assert.Equal(t, expired.ID, events[0].Session.ID)
+	assert.Equal(t, "account-1", events[0].Session.AccountID)
+	var data TestData
+	require.NoError(t, events[0].DecodeData(&data))
+	assert.Equal(t, TestData{Level: 3}, data)
+
+	_, exists := sp.GetSession(context.Background(), alive.ID)
+	assert.True(t, exists)
+	assert.Equal(t, []string{
+		"test:account:account-3", "test:data:" + alive.ID, "test:expiries", "test:expiry:" + alive.ID, "test:session:" + alive.ID,
+	}, server.Keys())
+}
+
+func TestSweepExpiredSessions_WhenReplacedAfterExpiry_ShouldReportExpiry(t *testing.T) {
+	server, err := fake.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+	sp := newTestSessionPoolOnServer(t, server, SessionPoolConfig{TTL: time.Minute, SweepInterval: time.Hour})
+
+	var events []sessions.Event
+	sp.Subscribe(func(event sessions.Event) {
+		events = append(events, event)
+	})
+
+	first, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+	server.FastForward(time.Minute)
+	_, err = sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	sp.SweepExpiredSessions()
+
+	types := make([]sessions.EventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	assert.Equal(t, []sessions.EventType{sessions.EventCreated, sessions.EventCreated, sessions.EventExpired}, types)
+	assert.Equal(t, first.ID, events[2].Session.ID)
+}
+
+func TestSweepExpiredSessions_WhenRefreshed_ShouldKeepSession(t *testing.T) {
+	server, err := fake.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+	sp := newTestSessionPoolOnServer(t, server, SessionPoolConfig{TTL: time.Minute, SweepInterval: time.Hour})
+
+	var events []sessions.Event
+	sp.Subscribe(func(event sessions.Event) {
+		events = append(events, event)
+	})
+
+	session, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	server.FastForward(40 * time.Second)
+	require.NoError(t, sp.UpdateActivity(context.Background(), session.ID))
+	server.FastForward(40 * time.Second)
+
+	sp.SweepExpiredSessions()
+
+	require.Len(t, events, 1)
+	assert.Equal(t, sessions.EventCreated, events[0].Type)
+	_, exists := sp.GetSession(context.Background(), session.ID)
+	assert.True(t, exists)
+}
+
+func TestCreateSession_WithoutSweeps_ShouldTrimExpiryIndex(t *testing.T) {
+	sp, _ := newTestSessionPool(t, 50*time.Millisecond)
+
+	_, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	session, err := sp.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+
+	sessionIDs, err := resp.Strings(sp.client.Do(context.Background(), "ZRANGEBYSCORE", "test:expiries", "-inf", "+inf"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{session.ID}, sessionIDs)
+}
+
+func TestGetTombstone(t *testing.T) {
+	sp, server := newTestSessionPoolWithTombstones(t, time.Minute, 10*time.Minute)
+
+	replaced, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	loggedOut, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	_, found := sp.GetTombstone(context.Background(), loggedOut.ID)
+	assert.False(t, found)
+
+	sp.RemoveSession(context.Background(), loggedOut.ID)
+
+	revoked, err := sp.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+	sp.RevokeSessions(context.Background(), "account-2")
+
+	table := map[string]struct {
+		sessionID string
+		accountID string
+		reason    sessions.EndReason
+	}{
+		"replaced":   {replaced.ID, "account-1", sessions.EndReasonReplaced},
+		"logged out": {loggedOut.ID, "account-1", sessions.EndReasonLoggedOut},
+		"revoked":    {revoked.ID, "account-2", sessions.EndReasonRevoked},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			tombstone, found := sp.GetTombstone(context.Background(), row.sessionID)
+			require.True(t, found)
+			assert.Equal(t, row.sessionID, tombstone.SessionID)
+			assert.Equal(t, row.accountID, tombstone.AccountID)
+			assert.Equal(t, row.reason, tombstone.Reason)
+			assert.WithinDuration(t, time.Now(), tombstone.EndedAt, time.Second)
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		session, err := sp.CreateSession(context.Background(), "account-3", nil)
+		require.NoError(t, err)
+
+		server.FastForward(30 * time.Second)
+		require.NoError(t, sp.UpdateActivity(context.Background(), session.ID))
+		server.FastForward(30 * time.Second)
+
+		_, found := sp.GetTombstone(context.Background(), session.ID)
+		assert.False(t, found)
+
+		server.FastForward(30 * time.Second)
+
+		_, exists := sp.GetSession(context.Background(), session.ID)
+		assert.False(t, exists)
+
+		tombstone, found := sp.GetTombstone(context.Background(), session.ID)
+		require.True(t, found)
+		assert.Equal(t, sessions.EndReasonExpired, tombstone.Reason)
+		assert.Equal(t, "account-3", tombstone.AccountID)
+	})
+
+	t.Run("old tombstones are forgotten", func(t *testing.T) {
+		server.FastForward(11 * time.Minute)
+
+		for _, row := range table {
+			_, found := sp.GetTombstone(context.Background(), row.sessionID)
+			assert.False(t, found)
+		}
+		// The expiry index is trimmed by the next login, without sweeps.
+		assert.Equal(t, []string{"test:expiries"}, server.Keys())
+	})
+}
+
+func sessionCount(t *testing.T, sp *SessionPool) int {
+	t.Helper()
+	count, err := sp.GetSessionCount()
+	require.NoError(t, err)
+	return count
+}
@@ -0,0 +1,52 @@
+package token
+
+import (
+	"context"
+	"technical-test-backend/internal/lifecycle"
+	"technical-test-backend/internal/resp"
+	"technical-test-backend/internal/worker"
+)
+
+// CreateSessionPool registers the worker cleaning up the expired revocations
+// on the lifecycle, and the closing of the connections of the shared store
+// when configured.
+func CreateSessionPool(lc *lifecycle.Lifecycle, config SessionPoolConfig) (*SessionPool, error) {
+	var sp *SessionPool
+	var err error
+	if config.Client.Address != "" {
+		client := resp.NewClient(config.Client)
+		if sp, err = NewSharedSessionPool(client, config); err != nil {
+			client.Close()
+			return nil, err
+		}
+
+		lc.Append(lifecycle.Hook{
+			Name: "token sessions client",
+			OnStop: func(ctx context.Context) error {
+				return client.Close()
+			},
+		})
+	} else if sp, err = NewSessionPool(config); err != nil {
+		return nil, err
+	}
+
+	cleanupWorker := worker.New(worker.Config{
+		Interval: config.TTL,
+	}, func() {
+		sp.CleanupExpiredSessions()
+	})
+
+	lc.Append(lifecycle.Hook{
+		Name: "token sessions cleanup",
+		OnStart: func(ctx context.Context) error {
+			cleanupWorker.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cleanupWorker.Stop()
+			return nil
+		},
+	})
+
+	return sp, nil
+}
@@ -0,0 +1,437 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/resp"
+	"technical-test-backend/internal/sessions"
+	"time"
+)
+
+var (
+	ErrInvalidToken          = errors.New("invalid session token")
+	ErrSessionExpired        = errors.New("session expired")
+	ErrSessionRevoked        = errors.New("session revoked")
+	ErrUnknownSession        = errors.New("session unknown to the storage")
+	ErrNoSigningKey          = errors.New("no signing key")
+	ErrInvalidKey            = errors.New("invalid signing key")
+	ErrFailedToMarshalData   = errors.New("failed to marshal data")
+	ErrFailedToUnmarshalData = errors.New("failed to unmarshal data")
+)
+
+// MinKeySize is the minimum length of a key secret, in bytes.
+const MinKeySize = 32
+
+type SessionPoolConfig struct {
+	// TTL is the lifetime of a token. It is not extended by activity.
+	TTL time.Duration
+	// Keys verify tokens, the first one also signs new ones. A key is rotated
+	// by appending the new one, moving it first once all instances know it, and
+	// dropping the old one after a TTL.
+	Keys []Key
+	// Client is the Redis-compatible server sharing the revocations and the
+	// session data between the instances. Without an address, each instance
+	// keeps them in memory, and only knows about the sessions it created.
+	Client resp.ClientConfig
+	// KeyPrefix namespaces the keys, to share a database.
+	KeyPrefix string
+}
+
+// SessionPool issues signed tokens holding the account ID, so any instance
+// sharing the keys can authenticate a session. Only the revocations and the
+// session data are kept, per account, in a store that may be shared by the
+// instances.
+type SessionPool struct {
+	config     SessionPoolConfig
+	keys       map[string]Key
+	signingKey Key
+	store      store
+	mutex      sync.RWMutex
+	publisher  *sessions.Publisher
+}
+
+// NewSessionPool creates a pool keeping the revocations and the session data
+// in memory.
+func NewSessionPool(config SessionPoolConfig) (*SessionPool, error) {
+	return newSessionPool(config, newMemoryStore())
+}
+
+// NewSharedSessionPool creates a pool keeping the revocations and the session
+// data in a Redis-compatible server, so they're seen by every instance. They
+// outlive their expiry by two TTLs, until CleanupExpiredSessions reports it.
+func NewSharedSessionPool(client *resp.Client, config SessionPoolConfig) (*SessionPool, error) {
+	return newSessionPool(config, newRedisStore(client, config.KeyPrefix, 2*config.TTL))
+}
+
+func newSessionPool(config SessionPoolConfig, store store) (*SessionPool, error) {
+	sp := &SessionPool{
+		config:    config,
+		store:     store,
+		publisher: sessions.NewPublisher(),
+	}
+
+	if err := sp.SetKeys(config.Keys); err != nil {
+		return nil, err
+	}
+
+	return sp, nil
+}
+
+// SetKeys replaces the keys, to rotate them without a restart.
+func (sp *SessionPool) SetKeys(keys []Key) error {
+	if len(keys) == 0 {
+		return ErrNoSigningKey
+	}
+
+	keysByID := make(map[string]Key, len(keys))
+	for _, key := range keys {
+		if key.ID == "" || len(key.Secret) < MinKeySize {
+			return errors.Wrapf(ErrInvalidKey, "key %q", key.ID)
+		}
+		if _, exists := keysByID[key.ID]; exists {
+			return errors.Wrapf(ErrInvalidKey, "duplicated key %q", key.ID)
+		}
+		keysByID[key.ID] = key
+	}
+
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	sp.keys = keysByID
+	sp.signingKey = keys[0]
+	return nil
+}
+
+func (sp *SessionPool) CreateSession(ctx context.Context, accountID string, data interface{}) (sessions.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return sessions.Session{}, err
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return sessions.Session{}, errors.Wrap(err, ErrFailedToMarshalData)
+	}
+
+	sp.mutex.RLock()
+	signingKey := sp.signingKey
+	sp.mutex.RUnlock()
+
+	var sess sessions.Session
+	var events []sessions.Event
+	var signErr error
+
+	err = sp.store.update(ctx, accountID, func(entry accountEntry, exists bool) (accountEntry, bool) {
+		events, signErr = nil, nil
+
+		issuedAt := time.Now().UnixNano()
+		endReason, endedAt := entry.EndReason, entry.EndedAt
+		if entry.Active && issuedAt < entry.ExpiresAt {
+			events = append(events, sessions.Event{Type: sessions.EventReplaced, Session: entry.Session, Data: entry.Data})
+			endReason, endedAt = sessions.EndReasonReplaced, issuedAt
+		}
+		// Keeps the new token strictly after the revoked ones.
+		if issuedAt <= entry.NotBefore {
+			issuedAt = entry.NotBefore + 1
+		}
+
+		claims := Claims{
+			KeyID:     signingKey.ID,
+			AccountID: accountID,
+			IssuedAt:  issuedAt,
+			ExpiresAt: issuedAt + sp.config.TTL.Nanoseconds(),
+		}
+
+		token, err := sign(claims, signingKey)
+		if err != nil {
+			signErr = err
+			return entry, false
+		}
+
+		sess = sessionOf(token, claims)
+		return accountEntry{
+			NotBefore: issuedAt,
+			EndReason: endReason,
+			EndedAt:   endedAt,
+			ExpiresAt: claims.ExpiresAt,
+			Active:    true,
+			Data:      rawData,
+			Session:   sess,
+		}, true
+	})
+	if err != nil {
+		return sessions.Session{}, err
+	}
+	if signErr != nil {
+		return sessions.Session{}, signErr
+	}
+
+	if len(events) > 0 {
+		log.Printf("Revoked existing session of account: %s", accountID)
+	}
+	log.Printf("Created session for account: %s", accountID)
+
+	events = append(events, sessions.Event{Type: sessions.EventCreated, Session: sess, Data: rawData})
+	sp.publisher.Publish(events...)
+
+	return sess, nil
+}
+
+func (sp *SessionPool) GetSession(ctx context.Context, sessionID string) (sessions.Session, bool) {
+	claims, err := sp.validate(ctx, sessionID)
+	if err != nil {
+		return sessions.Session{}, false
+	}
+
+	return sessionOf(sessionID, claims), true
+}
+
+// GetSessionData reads the data of the session. A valid session without data,
+// created by another instance when the store isn't shared, is reported as
+// ErrUnknownSession rather than as empty data, so its command sequence doesn't
+// start over.
+func (sp *SessionPool) GetSessionData(ctx context.Context, sessionID string, data interface{}) error {
+	claims, err := sp.validate(ctx, sessionID)
+	if err != nil {
+		return dataErr(err)
+	}
+
+	entry, exists, err := sp.store.get(ctx, claims.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if !exists || entry.Session.ID != sessionID {
+		return errors.Wrap(ErrUnknownSession, sessions.ErrSessionDataMissing)
+	}
+
+	if err := json.Unmarshal(entry.Data, data); err != nil {
+		return errors.Wrap(err, ErrFailedToUnmarshalData)
+	}
+
+	return nil
+}
+
+// SetSessionData stores the data of a session known to the store.
+func (sp *SessionPool) SetSessionData(ctx context.Context, sessionID string, data interface{}) error {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToMarshalData)
+	}
+
+	claims, err := sp.validate(ctx, sessionID)
+	if err != nil {
+		return dataErr(err)
+	}
+
+	known := false
+	err = sp.store.update(ctx, claims.AccountID, func(entry accountEntry, exists bool) (accountEntry, bool) {
+		known = exists && entry.Session.ID == sessionID
+		if !known {
+			return entry, false
+		}
+
+		entry.Data = rawData
+		return entry, true
+	})
+	if err != nil {
+		return err
+	}
+
+	if !known {
+		return errors.Wrap(ErrUnknownSession, sessions.ErrSessionDataMissing)
+	}
+
+	return nil
+}
+
+// UpdateActivity only checks the token, as its expiry is part of it.
+func (sp *SessionPool) UpdateActivity(ctx context.Context, sessionID string) error {
+	_, err := sp.validate(ctx, sessionID)
+	return err
+}
+
+// RemoveSession revokes the token until it expires, and drops the data of its
+// session.
+func (sp *SessionPool) RemoveSession(ctx context.Context, sessionID string) {
+	claims, err := sp.validate(ctx, sessionID)
+	if err != nil {
+		return
+	}
+
+	var event sessions.Event
+	err = sp.store.update(ctx, claims.AccountID, func(entry accountEntry, exists bool) (accountEntry, bool) {
+		event = sessions.Event{Type: sessions.EventRemoved, Session: sessionOf(sessionID, claims), Data: entry.Data}
+
+		entry.NotBefore = max(entry.NotBefore, claims.IssuedAt+1)
+		entry.EndReason, entry.EndedAt = sessions.EndReasonLoggedOut, time.Now().UnixNano()
+		entry.ExpiresAt = max(entry.ExpiresAt, claims.ExpiresAt)
+		entry.Active = false
+		entry.Data = nil
+		return entry, true
+	})
+	if err != nil {
+		log.Printf("Failed to remove session of account %s: %v", claims.AccountID, err)
+		return
+	}
+
+	sp.publisher.Publish(event)
+}
+
+// RevokeSessions revokes every token of the account issued so far.
+func (sp *SessionPool) RevokeSessions(ctx context.Context, accountID string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	var events []sessions.Event
+	err := sp.store.update(ctx, accountID, func(entry accountEntry, exists bool) (accountEntry, bool) {
+		events = nil
+
+		now := time.Now().UnixNano()
+		if entry.Active && now <= entry.ExpiresAt {
+			events = append(events, sessions.Event{Type: sessions.EventRevoked, Session: entry.Session, Data: entry.Data})
+		}
+
+		entry.NotBefore = max(entry.NotBefore+1, now)
+		entry.EndReason, entry.EndedAt = sessions.EndReasonRevoked, now
+		entry.ExpiresAt = max(entry.ExpiresAt, now+sp.config.TTL.Nanoseconds())
+		entry.Active = false
+		entry.Data = nil
+		return entry, true
+	})
+	if err != nil {
+		log.Printf("Failed to revoke sessions of account %s: %v", accountID, err)
+		return
+	}
+
+	log.Printf("Revoked sessions of account: %s", accountID)
+
+	sp.publisher.Publish(events...)
+}
+
+// GetTombstone tells why a token signed by a known key is no longer valid.
+// Tokens revoked before the last revocation of their account share its
+// reason.
+func (sp *SessionPool) GetTombstone(ctx context.Context, sessionID string) (sessions.Tombstone, bool) {
+	if ctx.Err() != nil {
+		return sessions.Tombstone{}, false
+	}
+
+	sp.mutex.RLock()
+	claims, err := verify(sessionID, sp.keys)
+	sp.mutex.RUnlock()
+	if err != nil {
+		return sessions.Tombstone{}, false
+	}
+
+	entry, exists, err := sp.store.get(ctx, claims.AccountID)
+	if err != nil {
+		log.Printf("Failed to get tombstone of account %s: %v", claims.AccountID, err)
+		return sessions.Tombstone{}, false
+	}
+
+	tombstone := sessions.Tombstone{SessionID: sessionID, AccountID: claims.AccountID}
+
+	if exists && claims.IssuedAt < entry.NotBefore && entry.EndReason != "" {
+		tombstone.Reason = entry.EndReason
+		tombstone.EndedAt = time.Unix(0, entry.EndedAt)
+		return tombstone, true
+	}
+
+	if time.Now().UnixNano() > claims.ExpiresAt {
+		tombstone.Reason = sessions.EndReasonExpired
+		tombstone.EndedAt = time.Unix(0, claims.ExpiresAt)
+		return tombstone, true
+	}
+
+	return sessions.Tombstone{}, false
+}
+
+func (sp *SessionPool) GetAccountID(ctx context.Context, sessionID string) (string, bool) {
+	claims, err := sp.validate(ctx, sessionID)
+	if err != nil {
+		return "", false
+	}
+
+	return claims.AccountID, true
+}
+
+// CleanupExpiredSessions forgets the accounts whose tokens all expired, along
+// with their revocations. With a shared store, the expiry of a session is
+// reported by the first instance cleaning it up.
+func (sp *SessionPool) CleanupExpiredSessions() {
+	var events []sessions.Event
+
+	err := sp.store.removeExpired(context.Background(), time.Now().UnixNano(), func(accountID string, entry accountEntry) {
+		if entry.Active {
+			events = append(events, sessions.Event{Type: sessions.EventExpired, Session: entry.Session, Data: entry.Data})
+		}
+		log.Printf("Removed expired session of account: %s", accountID)
+	})
+	if err != nil {
+		log.Printf("Failed to clean up expired sessions: %v", err)
+	}
+
+	sp.publisher.Publish(events...)
+}
+
+func (sp *SessionPool) Subscribe(subscriber sessions.Subscriber) func() {
+	return sp.publisher.Subscribe(subscriber)
+}
+
+// GetSessionCount returns the number of live sessions known to the store.
+func (sp *SessionPool) GetSessionCount() (int, error) {
+	return sp.store.countActive(context.Background(), time.Now().UnixNano())
+}
+
+// validate checks the signature and the expiry of the token, then whether its
+// account revoked it.
+func (sp *SessionPool) validate(ctx context.Context, token string) (Claims, error) {
+	if err := ctx.Err(); err != nil {
+		return Claims{}, err
+	}
+
+	sp.mutex.RLock()
+	claims, err := verify(token, sp.keys)
+	sp.mutex.RUnlock()
+	if err != nil {
+		return Claims{}, errors.Wrap(ErrInvalidToken, err)
+	}
+
+	if time.Now().UnixNano() > claims.ExpiresAt {
+		return Claims{}, ErrSessionExpired
+	}
+
+	entry, exists, err := sp.store.get(ctx, claims.AccountID)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if exists && claims.IssuedAt < entry.NotBefore {
+		return Claims{}, ErrSessionRevoked
+	}
+
+	return claims, nil
+}
+
+// dataErr reports an invalid session as missing data, for the callers of the
+// Data methods.
+func dataErr(err error) error {
+	if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrSessionExpired) || errors.Is(err, ErrSessionRevoked) {
+		return errors.Wrap(err, sessions.ErrSessionDataMissing)
+	}
+	return err
+}
+
+func sessionOf(token string, claims Claims) sessions.Session {
+	issuedAt := time.Unix(0, claims.IssuedAt)
+	return sessions.Session{
+		ID:           token,
+		AccountID:    claims.AccountID,
+		LastActivity: issuedAt,
+		CreatedAt:    issuedAt,
+	}
+}
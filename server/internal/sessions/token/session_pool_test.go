@@ -0,0 +1,463 @@
+//go:build unit
+// +build unit
+
+package token
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"technical-test-backend/internal/resp"
+	"technical-test-backend/internal/resp/fake"
+	"technical-test-backend/internal/sessions"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKey      = Key{ID: "key-1", Secret: []byte("0123456789abcdef0123456789abcdef")}
+	testOtherKey = Key{ID: "key-2", Secret: []byte("fedcba9876543210fedcba9876543210")}
+)
+
+type TestData struct {
+	Level int
+	Score float64
+}
+
+// newTestSharedSessionPools creates pools sharing their store, as instances
+// would.
+func newTestSharedSessionPools(t *testing.T, ttl time.Duration) (*SessionPool, *SessionPool, *fake.Server) {
+	server, err := fake.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	config := SessionPoolConfig{
+		TTL:       ttl,
+		Keys:      []Key{testKey},
+		Client:    resp.ClientConfig{Address: server.Addr()},
+		KeyPrefix: "test:",
+	}
+
+	pools := make([]*SessionPool, 2)
+	for i := range pools {
+		client := resp.NewClient(config.Client)
+		t.Cleanup(func() { client.Close() })
+
+		pools[i], err = NewSharedSessionPool(client, config)
+		require.NoError(t, err)
+	}
+
+	return pools[0], pools[1], server
+}
+
+func newTestSessionPool(t *testing.T, ttl time.Duration, keys ...Key) *SessionPool {
+	if len(keys) == 0 {
+		keys = []Key{testKey}
+	}
+
+	sp, err := NewSessionPool(SessionPoolConfig{TTL: ttl, Keys: keys})
+	require.NoError(t, err)
+	return sp
+}
+
+func TestNewSessionPool_InvalidKeys(t *testing.T) {
+	table := map[string]struct {
+		keys          []Key
+		expectedError error
+	}{
+		"no keys":       {nil, ErrNoSigningKey},
+		"missing id":    {[]Key{{Secret: testKey.Secret}}, ErrInvalidKey},
+		"short secret":  {[]Key{{ID: "key", Secret: []byte("short")}}, ErrInvalidKey},
+		"duplicated id": {[]Key{testKey, {ID: testKey.ID, Secret: testOtherKey.Secret}}, ErrInvalidKey},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSessionPool(SessionPoolConfig{TTL: time.Minute, Keys: row.keys})
+
+			assert.ErrorIs(t, err, row.expectedError)
+		})
+	}
+}
+
+func TestCreateSession(t *testing.T) {
+	sp := newTestSessionPool(t, time.Minute)
+
+	session, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+
+	accountID, exists := sp.GetAccountID(context.Background(), session.ID)
+	assert.True(t, exists)
+	assert.Equal(t, "account-1", accountID)
+	assert.Equal(t, 1, sessionCount(t, sp))
+
+	var data TestData
+	require.NoError(t, sp.GetSessionData(context.Background(), session.ID, &data))
+	assert.Equal(t, TestData{Level: 1}, data)
+}
+
+func TestCreateSession_ShouldRevokeOlderSession(t *testing.T) {
+	sp := newTestSessionPool(t, time.Minute)
+
+	first, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+	second, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 2})
+	require.NoError(t, err)
+
+	_, exists := sp.GetSession(context.Background(), first.ID)
+	assert.False(t, exists)
+	assert.ErrorIs(t, sp.UpdateActivity(context.Background(), first.ID), ErrSessionRevoked)
+
+	_, exists = sp.GetSession(context.Background(), second.ID)
+	assert.True(t, exists)
+	assert.Equal(t, 1, sessionCount(t, sp))
+
+	var data TestData
+	require.NoError(t, sp.GetSessionData(context.Background(), second.ID, &data))
+	assert.Equal(t, TestData{Level: 2}, data)
+}
+
+func TestSetSessionData(t *testing.T) {
+	sp := newTestSessionPool(t, time.Minute)
+
+	session, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, sp.SetSessionData(context.Background(), session.ID, TestData{Level: 2}))
+
+	var data TestData
+	require.NoError(t, sp.GetSessionData(context.Background(), session.ID, &data))
+	assert.Equal(t, TestData{Level: 2}, data)
+}
+
+func TestGetSession_ShouldRejectTamperedTokens(t *testing.T) {
+	sp := newTestSessionPool(t, time.Minute)
+
+	session, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	forged, err := sign(Claims{KeyID: testKey.ID, AccountID: "account-2", IssuedAt: time.Now().UnixNano(), ExpiresAt: time.Now().Add(time.Minute).UnixNano()}, testOtherKey)
+	require.NoError(t, err)
+	payload, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(session.ID, ".")
+
+	table := map[string]string{
+		"empty":             "",
+		"not a token":       "not-a-token",
+		"invalid signature": forged,
+		"swapped payload":   payload + "." + signature,
+	}
+
+	for name, token := range table {
+		t.Run(name, func(t *testing.T) {
+			_, exists := sp.GetAccountID(context.Background(), token)
+			assert.False(t, exists)
+			assert.ErrorIs(t, sp.UpdateActivity(context.Background(), token), ErrInvalidToken)
+		})
+	}
+}
+
+func TestGetSession_Expired(t *testing.T) {
+	sp := newTestSessionPool(t, 50*time.Millisecond)
+
+	session, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, exists := sp.GetSession(context.Background(), session.ID)
+	assert.False(t, exists)
+	assert.ErrorIs(t, sp.UpdateActivity(context.Background(), session.ID), ErrSessionExpired)
+	assert.Equal(t, 0, sessionCount(t, sp))
+}
+
+func TestGetSession_ShouldNotRequireLookup(t *testing.T) {
+	issuer := newTestSessionPool(t, time.Minute)
+	verifier := newTestSessionPool(t, time.Minute)
+
+	session, err := issuer.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	accountID, exists := verifier.GetAccountID(context.Background(), session.ID)
+	assert.True(t, exists)
+	assert.Equal(t, "account-1", accountID)
+
+	var data TestData
+	assert.ErrorIs(t, verifier.GetSessionData(context.Background(), session.ID, &data), ErrUnknownSession)
+	assert.ErrorIs(t, verifier.SetSessionData(context.Background(), session.ID, TestData{Level: 1}), sessions.ErrSessionDataMissing)
+}
+
+func TestSharedSessionPool_ShouldShareDataAndRevocations(t *testing.T) {
+	issuer, other, _ := newTestSharedSessionPools(t, time.Minute)
+
+	var events []sessions.Event
+	other.Subscribe(func(event sessions.Event) {
+		events = append(events, event)
+	})
+
+	first, err := issuer.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, other.SetSessionData(context.Background(), first.ID, TestData{Level: 2}))
+	var data TestData
+	require.NoError(t, issuer.GetSessionData(context.Background(), first.ID, &data))
+	assert.Equal(t, TestData{Level: 2}, data)
+
+	second, err := other.CreateSession(context.Background(), "account-1", TestData{Level: 3})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, issuer.UpdateActivity(context.Background(), first.ID), ErrSessionRevoked)
+	tombstone, exists := issuer.GetTombstone(context.Background(), first.ID)
+	require.True(t, exists)
+	assert.Equal(t, sessions.EndReasonReplaced, tombstone.Reason)
+	require.Len(t, events, 2)
+	assert.Equal(t, sessions.EventReplaced, events[0].Type)
+	require.NoError(t, events[0].DecodeData(&data))
+	assert.Equal(t, TestData{Level: 2}, data)
+
+	other.RevokeSessions(context.Background(), "account-1")
+
+	_, exists = issuer.GetSession(context.Background(), second.ID)
+	assert.False(t, exists)
+	assert.ErrorIs(t, issuer.GetSessionData(context.Background(), second.ID, &data), sessions.ErrSessionDataMissing)
+	count, err := issuer.store.countActive(context.Background(), time.Now().UnixNano())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestSharedSessionPool_CleanupExpiredSessions_ShouldReportOnce(t *testing.T) {
+	sp, other, server := newTestSharedSessionPools(t, 50*time.Millisecond)
+
+	var mutex sync.Mutex
+	var events []sessions.Event
+	for _, pool := range []*SessionPool{sp, other} {
+		pool.Subscribe(func(event sessions.Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			events = append(events, event)
+		})
+	}
+
+	expired, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+	removed, err := sp.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+	sp.RemoveSession(context.Background(), removed.ID)
+	assert.Equal(t, 1, sessionCount(t, other))
+
+	time.Sleep(100 * time.Millisecond)
+	events = nil
+
+	var wg sync.WaitGroup
+	for _, pool := range []*SessionPool{sp, other} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.CleanupExpiredSessions()
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, events, 1)
+	assert.Equal(t, sessions.EventExpired, events[0].Type)
+	assert.Equal(t, expired.ID, events[0].Session.ID)
+	var data TestData
+	require.NoError(t, events[0].DecodeData(&data))
+	assert.Equal(t, TestData{Level: 1}, data)
+	assert.Empty(t, server.Keys())
+}
+
+func TestSetKeys_ShouldRotateKeys(t *testing.T) {
+	sp := newTestSessionPool(t, time.Minute, testKey)
+
+	oldSession, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, sp.SetKeys([]Key{testOtherKey, testKey}))
+
+	newSession, err := sp.CreateSession(context.Background(), "account-2", nil)
+	require.NoError(t, err)
+
+	_, exists := sp.GetSession(context.Background(), oldSession.ID)
+	assert.True(t, exists)
+	_, exists = sp.GetSession(context.Background(), newSession.ID)
+	assert.True(t, exists)
+
+	require.NoError(t, sp.SetKeys([]Key{testOtherKey}))
+
+	assert.ErrorIs(t, sp.UpdateActivity(context.Background(), oldSession.ID), ErrUnknownKey)
+	_, exists = sp.GetSession(context.Background(), newSession.ID)
+	assert.True(t, exists)
+}
+
+func TestRemoveSession(t *testing.T) {
+	sp := newTestSessionPool(t, time.Minute)
+
+	session, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+
+	sp.RemoveSession(context.Background(), session.ID)
+
+	_, exists := sp.GetSession(context.Background(), session.ID)
+	assert.False(t, exists)
+	assert.Equal(t, 0, sessionCount(t, sp))
+
+	var data TestData
+	assert.ErrorIs(t, sp.GetSessionData(context.Background(), session.ID, &data), ErrSessionRevoked)
+
+	newSession, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	_, exists = sp.GetSession(context.Background(), newSession.ID)
+	assert.True(t, exists)
+}
+
+func TestCleanupExpiredSessions(t *testing.T) {
+	sp := newTestSessionPool(t, 50*time.Millisecond)
+
+	_, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	sp.CleanupExpiredSessions()
+
+	store := sp.store.(*memoryStore)
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	assert.Empty(t, store.entries)
+}
+
+func TestSubscribe(t *testing.T) {
+	sp := newTestSessionPool(t, 50*time.Millisecond)
+
+	var events []sessions.Event
+	sp.Subscribe(func(event sessions.Event) {
+		events = append(events, event)
+	})
+
+	first, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+	second, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 2})
+	require.NoError(t, err)
+	sp.RemoveSession(context.Background(), second.ID)
+
+	third, err := sp.CreateSession(context.Background(), "account-2", TestData{Level: 3})
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	sp.CleanupExpiredSessions()
+
+	types := make([]sessions.EventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	assert.Equal(t, []sessions.EventType{
+		sessions.EventCreated, sessions.EventReplaced, sessions.EventCreated,
+		sessions.EventRemoved, sessions.EventCreated, sessions.EventExpired,
+	}, types)
+
+	assert.Equal(t, first.ID, events[1].Session.ID)
+	assert.Equal(t, second.ID, events[3].Session.ID)
+	assert.Equal(t, third.ID, events[5].Session.ID)
+
+	var data TestData
+	require.NoError(t, events[1].DecodeData(&data))
+	assert.Equal(t, 1, data.Level)
+}
+
+func TestGetTombstone(t *testing.T) {
+	sp := newTestSessionPool(t, time.Minute)
+
+	replaced, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	loggedOut, err := sp.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	tombstone, found := sp.GetTombstone(context.Background(), replaced.ID)
+	require.True(t, found)
+	assert.Equal(t, sessions.EndReasonReplaced, tombstone.Reason)
+	assert.Equal(t, "account-1", tombstone.AccountID)
+	assert.Equal(t, loggedOut.CreatedAt, tombstone.EndedAt)
+
+	_, found = sp.GetTombstone(context.Background(), loggedOut.ID)
+	assert.False(t, found)
+
+	sp.RemoveSession(context.Background(), loggedOut.ID)
+
+	tombstone, found = sp.GetTombstone(context.Background(), loggedOut.ID)
+	require.True(t, found)
+	assert.Equal(t, sessions.EndReasonLoggedOut, tombstone.Reason)
+
+	t.Run("revoked", func(t *testing.T) {
+		session, err := sp.CreateSession(context.Background(), "account-2", nil)
+		require.NoError(t, err)
+
+		sp.RevokeSessions(context.Background(), "account-2")
+
+		_, exists := sp.GetSession(context.Background(), session.ID)
+		assert.False(t, exists)
+
+		tombstone, found := sp.GetTombstone(context.Background(), session.ID)
+		require.True(t, found)
+		assert.Equal(t, sessions.EndReasonRevoked, tombstone.Reason)
+
+		newSession, err := sp.CreateSession(context.Background(), "account-2", nil)
+		require.NoError(t, err)
+		_, exists = sp.GetSession(context.Background(), newSession.ID)
+		assert.True(t, exists)
+		_, found = sp.GetTombstone(context.Background(), session.ID)
+		assert.True(t, found)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		sp := newTestSessionPool(t, 50*time.Millisecond)
+
+		session, err := sp.CreateSession(context.Background(), "account-1", nil)
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		sp.CleanupExpiredSessions()
+
+		tombstone, found := sp.GetTombstone(context.Background(), session.ID)
+		require.True(t, found)
+		assert.Equal(t, sessions.EndReasonExpired, tombstone.Reason)
+		assert.Equal(t, session.CreatedAt.Add(50*time.Millisecond), tombstone.EndedAt)
+	})
+
+	t.Run("tampered token", func(t *testing.T) {
+		_, found := sp.GetTombstone(context.Background(), replaced.ID+"x")
+		assert.False(t, found)
+	})
+}
+
+func TestRevokeSessions_ShouldPublishEvent(t *testing.T) {
+	sp := newTestSessionPool(t, time.Minute)
+
+	var events []sessions.Event
+	sp.Subscribe(func(event sessions.Event) {
+		events = append(events, event)
+	})
+
+	session, err := sp.CreateSession(context.Background(), "account-1", TestData{Level: 1})
+	require.NoError(t, err)
+
+	sp.RevokeSessions(context.Background(), "account-1")
+	sp.RevokeSessions(context.Background(), "account-1")
+
+	require.Len(t, events, 2)
+	assert.Equal(t, sessions.EventRevoked, events[1].Type)
+	assert.Equal(t, session.ID, events[1].Session.ID)
+
+	var data TestData
+	require.NoError(t, events[1].DecodeData(&data))
+	assert.Equal(t, 1, data.Level)
+	assert.Equal(t, 0, sessionCount(t, sp))
+}
+
+func sessionCount(t *testing.T, sp *SessionPool) int {
+	t.Helper()
+	count, err := sp.GetSessionCount()
+	require.NoError(t, err)
+	return count
+}
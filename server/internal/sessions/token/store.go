@@ -0,0 +1,293 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/resp"
+	"technical-test-backend/internal/sessions"
+	"time"
+)
+
+var ErrTooManyConflicts = errors.New("too many conflicting session updates")
+
+// maxConflictRetries bounds the retries of an update whose entry was changed
+// concurrently. Retries wait up to conflictBackoff times the attempt, to
+// spread concurrent logins apart.
+const (
+	maxConflictRetries = 10
+	conflictBackoff    = 10 * time.Millisecond
+)
+
+// accountEntry is what the pool knows about the sessions of an account.
+type accountEntry struct {
+	// NotBefore revokes the tokens issued before it, which is how older
+	// sessions get kicked. EndReason and EndedAt describe the last revocation.
+	NotBefore int64              `json:"notBefore"`
+	EndReason sessions.EndReason `json:"endReason,omitempty"`
+	EndedAt   int64              `json:"endedAt,omitempty"`
+	// ExpiresAt is when every token the entry tells about expired.
+	ExpiresAt int64           `json:"expiresAt"`
+	Active    bool            `json:"active"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	// Session is the last one created, for its events.
+	Session sessions.Session `json:"session"`
+}
+
+// store keeps the entries of the accounts.
+type store interface {
+	get(ctx context.Context, accountID string) (accountEntry, bool, error)
+	// update replaces the entry of the account with the one returned by fn,
+	// atomically. Nothing is written if fn returns false. fn is called again
+	// when a concurrent update wins, so it must not have side effects.
+	update(ctx context.Context, accountID string, fn func(entry accountEntry, exists bool) (accountEntry, bool)) error
+	// removeExpired removes the entries expired at now, calling fn with each
+	// of them. An entry is removed and passed to fn by a single instance.
+	removeExpired(ctx context.Context, now int64, fn func(accountID string, entry accountEntry)) error
+	// countActive returns the number of active sessions alive at now.
+	countActive(ctx context.Context, now int64) (int, error)
+}
+
+// memoryStore keeps the entries in memory, so each instance only knows about
+// the sessions it created.
+type memoryStore struct {
+	entries map[string]accountEntry
+	mutex   sync.RWMutex
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		entries: make(map[string]accountEntry),
+	}
+}
+
+func (s *memoryStore) get(ctx context.Context, accountID string) (accountEntry, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entry, exists := s.entries[accountID]
+	return entry, exists, nil
+}
+
+func (s *memoryStore) update(ctx context.Context, accountID string, fn func(entry accountEntry, exists bool) (accountEntry, bool)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, exists := s.entries[accountID]
+	if entry, changed := fn(entry, exists); changed {
+		s.entries[accountID] = entry
+	}
+	return nil
+}
+
+func (s *memoryStore) removeExpired(ctx context.Context, now int64, fn func(accountID string, entry accountEntry)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for accountID, entry := range s.entries {
+		if now > entry.ExpiresAt {
+			delete(s.entries, accountID)
+			fn(accountID, entry)
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) countActive(ctx context.Context, now int64) (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	count := 0
+	for _, entry := range s.entries {
+		if entry.Active && now <= entry.ExpiresAt {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// redisStore keeps the entries in a Redis-compatible server, shared by the
+// instances. The keys are:
+//   - token:account:<account id>: the JSON encoded entry. It outlives the
+//     entry by retention, until removeExpired claims it.
+//   - token:expiries: the account IDs by expiry of their entry, in
+//     milliseconds, for removeExpired to find the expired ones.
+//   - token:active: the account IDs by expiry of their active session, to
+//     count them.
+type redisStore struct {
+	client    *resp.Client
+	keyPrefix string
+	retention time.Duration
+}
+
+func newRedisStore(client *resp.Client, keyPrefix string, retention time.Duration) *redisStore {
+	return &redisStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+		retention: retention,
+	}
+}
+
+func (s *redisStore) get(ctx context.Context, accountID string) (accountEntry, bool, error) {
+	rawEntry, err := resp.String(s.client.Do(ctx, "GET", s.accountKey(accountID)))
+	if errors.Is(err, resp.ErrNil) {
+		return accountEntry{}, false, nil
+	} else if err != nil {
+		return accountEntry{}, false, err
+	}
+
+	var entry accountEntry
+	if err := json.Unmarshal([]byte(rawEntry), &entry); err != nil {
+		return accountEntry{}, false, errors.Wrap(err, ErrFailedToUnmarshalData)
+	}
+
+	return entry, true, nil
+}
+
+// update watches the entry, and retries the transaction if another instance
+// changed it in between.
+func (s *redisStore) update(ctx context.Context, accountID string, fn func(entry accountEntry, exists bool) (accountEntry, bool)) error {
+	accountKey := s.accountKey(accountID)
+
+	for attempt := range maxConflictRetries {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(rand.Int64N(int64(attempt) * int64(conflictBackoff)))):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		committed := false
+		err := s.client.WithConn(ctx, func(conn *resp.Conn) error {
+			if _, err := conn.Do("WATCH", accountKey); err != nil {
+				return err
+			}
+
+			rawEntry, err := resp.String(conn.Do("GET", accountKey))
+			if err != nil && !errors.Is(err, resp.ErrNil) {
+				return err
+			}
+
+			var entry accountEntry
+			exists := rawEntry != ""
+			if exists {
+				if err := json.Unmarshal([]byte(rawEntry), &entry); err != nil {
+					return errors.Wrap(err, ErrFailedToUnmarshalData)
+				}
+			}
+
+			entry, changed := fn(entry, exists)
+			if !changed {
+				committed = true
+				return nil
+			}
+
+			newRawEntry, err := json.Marshal(entry)
+			if err != nil {
+				return errors.Wrap(err, ErrFailedToMarshalData)
+			}
+
+			ttl := time.Duration(entry.ExpiresAt-time.Now().UnixNano()) + s.retention
+			expiresAt := strconv.FormatInt(time.Unix(0, entry.ExpiresAt).UnixMilli(), 10)
+			commands := [][]string{
+				{"SET", accountKey, string(newRawEntry), "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10)},
+				{"ZADD", s.expiriesKey(), expiresAt, accountID},
+			}
+			if entry.Active {
+				commands = append(commands, []string{"ZADD", s.activeKey(), expiresAt, accountID})
+			} else {
+				commands = append(commands, []string{"ZREM", s.activeKey(), accountID})
+			}
+
+			committed, err = conn.Transaction(commands)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if committed {
+			return nil
+		}
+	}
+
+	return ErrTooManyConflicts
+}
+
+// removeExpired claims each expired entry by deleting it in a transaction
+// watching it, so concurrent sweeps of other instances skip it.
+func (s *redisStore) removeExpired(ctx context.Context, now int64, fn func(accountID string, entry accountEntry)) error {
+	nowMilli := strconv.FormatInt(time.Unix(0, now).UnixMilli()-1, 10)
+	accountIDs, err := resp.Strings(s.client.Do(ctx, "ZRANGEBYSCORE", s.expiriesKey(), "-inf", nowMilli))
+	if err != nil {
+		return err
+	}
+
+	for _, accountID := range accountIDs {
+		var removed *accountEntry
+
+		err := s.client.WithConn(ctx, func(conn *resp.Conn) error {
+			accountKey := s.accountKey(accountID)
+			if _, err := conn.Do("WATCH", accountKey); err != nil {
+				return err
+			}
+
+			rawEntry, err := resp.String(conn.Do("GET", accountKey))
+			if err != nil && !errors.Is(err, resp.ErrNil) {
+				return err
+			}
+
+			var entry accountEntry
+			if rawEntry != "" {
+				if err := json.Unmarshal([]byte(rawEntry), &entry); err != nil {
+					return errors.Wrap(err, ErrFailedToUnmarshalData)
+				}
+				if now <= entry.ExpiresAt {
+					return nil
+				}
+			}
+
+			committed, err := conn.Transaction([][]string{
+				{"DEL", accountKey},
+				{"ZREM", s.expiriesKey(), accountID},
+				{"ZREM", s.activeKey(), accountID},
+			})
+			// An entry gone past its retention has nothing left to report.
+			if committed && rawEntry != "" {
+				removed = &entry
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if removed != nil {
+			fn(accountID, *removed)
+		}
+	}
+
+	return nil
+}
+
+func (s *redisStore) countActive(ctx context.Context, now int64) (int, error) {
+	nowMilli := strconv.FormatInt(time.Unix(0, now).UnixMilli(), 10)
+	count, err := resp.Int(s.client.Do(ctx, "ZCOUNT", s.activeKey(), nowMilli, "+inf"))
+	return int(count), err
+}
+
+func (s *redisStore) accountKey(accountID string) string {
+	return s.keyPrefix + "token:account:" + accountID
+}
+
+func (s *redisStore) expiriesKey() string {
+	return s.keyPrefix + "token:expiries"
+}
+
+func (s *redisStore) activeKey() string {
+	return s.keyPrefix + "token:active"
+}
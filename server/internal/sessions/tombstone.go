@@ -0,0 +1,83 @@
+package sessions
+
+import (
+	"technical-test-backend/internal/errors"
+	"time"
+)
+
+// EndReason tells why a session ended, so its client can tell the player.
+type EndReason string
+
+const (
+	EndReasonExpired EndReason = "expired"
+	// EndReasonReplaced is for a session kicked by a login on another device.
+	EndReasonReplaced  EndReason = "replaced"
+	EndReasonLoggedOut EndReason = "logged_out"
+	EndReasonRevoked   EndReason = "revoked"
+)
+
+const (
+	ErrorCodeSessionExpired   = "SESSION_EXPIRED"
+	ErrorCodeSessionReplaced  = "SESSION_REPLACED"
+	ErrorCodeSessionLoggedOut = "SESSION_LOGGED_OUT"
+	ErrorCodeSessionRevoked   = "SESSION_REVOKED"
+)
+
+var (
+	ErrSessionExpired   = errors.NewCoded(ErrorCodeSessionExpired, "session expired")
+	ErrSessionReplaced  = errors.NewCoded(ErrorCodeSessionReplaced, "session replaced by a login on another device")
+	ErrSessionLoggedOut = errors.NewCoded(ErrorCodeSessionLoggedOut, "session logged out")
+	ErrSessionRevoked   = errors.NewCoded(ErrorCodeSessionRevoked, "session revoked")
+)
+
+// endedSessionErrors tells the client why its session ended, so it doesn't log
+// in again on its own when it was replaced or revoked.
+var endedSessionErrors = map[EndReason]error{
+	EndReasonExpired:   ErrSessionExpired,
+	EndReasonReplaced:  ErrSessionReplaced,
+	EndReasonLoggedOut: ErrSessionLoggedOut,
+	EndReasonRevoked:   ErrSessionRevoked,
+}
+
+// Tombstone is what a pool remembers of a session that ended recently.
+type Tombstone struct {
+	SessionID string    `json:"sessionId"`
+	AccountID string    `json:"accountId"`
+	Reason    EndReason `json:"reason"`
+	EndedAt   time.Time `json:"endedAt"`
+}
+
+// Err returns the error telling why the session ended, or nil for an unknown
+// reason.
+func (t Tombstone) Err() error {
+	return endedSessionErrors[t.Reason]
+}
+
+var endReasonsByEventType = map[EventType]EndReason{
+	EventReplaced: EndReasonReplaced,
+	EventExpired:  EndReasonExpired,
+	EventRemoved:  EndReasonLoggedOut,
+	EventRevoked:  EndReasonRevoked,
+}
+
+// EndReasonOf returns the reason of the end of a session for the event
+// ending it, or false for an event that doesn't end a session.
+func EndReasonOf(eventType EventType) (EndReason, bool) {
+	reason, ended := endReasonsByEventType[eventType]
+	return reason, ended
+}
+
+// NewTombstone returns the tombstone of a session ended by the event.
+func NewTombstone(event Event, endedAt time.Time) (Tombstone, bool) {
+	reason, ended := EndReasonOf(event.Type)
+	if !ended {
+		return Tombstone{}, false
+	}
+
+	return Tombstone{
+		SessionID: event.Session.ID,
+		AccountID: event.Session.AccountID,
+		Reason:    reason,
+		EndedAt:   endedAt,
+	}, true
+}
@@ -0,0 +1,37 @@
+//go:build unit
+// +build unit
+
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTombstone(t *testing.T) {
+	endedAt := time.Now()
+	session := Session{ID: "session-1", AccountID: "account-1"}
+
+	table := map[EventType]EndReason{
+		EventReplaced: EndReasonReplaced,
+		EventExpired:  EndReasonExpired,
+		EventRemoved:  EndReasonLoggedOut,
+		EventRevoked:  EndReasonRevoked,
+	}
+
+	for eventType, reason := range table {
+		t.Run(string(eventType), func(t *testing.T) {
+			tombstone, ended := NewTombstone(Event{Type: eventType, Session: session}, endedAt)
+			require.True(t, ended)
+			assert.Equal(t, Tombstone{SessionID: "session-1", AccountID: "account-1", Reason: reason, EndedAt: endedAt}, tombstone)
+		})
+	}
+
+	t.Run("created", func(t *testing.T) {
+		_, ended := NewTombstone(Event{Type: EventCreated, Session: session}, endedAt)
+		assert.False(t, ended)
+	})
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+	httputils "technical-test-backend/internal/http"
+	"technical-test-backend/internal/sessions"
+)
+
+type RevokeSessionsArgs struct {
+	AccountID string `json:"accountId"`
+}
+
+type RevokeSessionsRes struct{}
+
+// Handler serves the moderation endpoints, behind the admin token.
+type Handler struct {
+	sessionPool sessions.Pool
+}
+
+func NewHandler(sessionPool sessions.Pool) *Handler {
+	return &Handler{
+		sessionPool: sessionPool,
+	}
+}
+
+// HandleRevokeSessions ends every session of the account. Its clients are
+// told their session was revoked.
+func (h *Handler) HandleRevokeSessions(w http.ResponseWriter, r *http.Request) {
+	var args RevokeSessionsArgs
+	if err := httputils.DecodeJSON(r, &args); err != nil || args.AccountID == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	h.sessionPool.RevokeSessions(r.Context(), args.AccountID)
+
+	httputils.WriteJSON(w, http.StatusOK, RevokeSessionsRes{})
+}
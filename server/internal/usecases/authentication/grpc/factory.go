@@ -0,0 +1,12 @@
+package grpc
+
+import (
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/authentication"
+	"technical-test-backend/internal/usecases/configs"
+	"technical-test-backend/internal/usecases/players"
+)
+
+func CreateGRPCHandler(sessionPool sessions.Pool, dal players.AccountDAL, configsProvider *configs.Provider) *Handler {
+	return NewHandler(authentication.NewHandler(dal, configsProvider), sessionPool)
+}
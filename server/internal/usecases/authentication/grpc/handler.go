@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	grpcutils "technical-test-backend/internal/grpc"
+	"technical-test-backend/internal/grpc/gamepb"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/authentication"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var errorStatuses = grpcutils.StatusCodes{
+	authentication.ErrorCodeInvalidCredentials: codes.Unauthenticated,
+	sessions.ErrorCodeSessionLimitReached:      codes.ResourceExhausted,
+}
+
+type Handler struct {
+	gamepb.UnimplementedAuthenticationHandlerServer
+
+	authHandler *authentication.Handler
+	sessionPool sessions.Pool
+}
+
+func NewHandler(authHandler *authentication.Handler, sessionPool sessions.Pool) *Handler {
+	return &Handler{
+		authHandler: authHandler,
+		sessionPool: sessionPool,
+	}
+}
+
+func (h *Handler) Register(ctx context.Context, req *gamepb.RegisterRequest) (*gamepb.RegisterResponse, error) {
+	res, err := h.authHandler.Register(ctx, &authentication.RegisterArgs{})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &gamepb.RegisterResponse{
+		AccountId: res.AccountID,
+		Secret:    res.Secret,
+	}, nil
+}
+
+// Login returns the id of the new session in the x-session-id header.
+func (h *Handler) Login(ctx context.Context, req *gamepb.LoginRequest) (*gamepb.LoginResponse, error) {
+	if _, err := uuid.Parse(req.GetAccountId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid account id")
+	}
+
+	if req.GetSecret() == "" {
+		return nil, status.Error(codes.InvalidArgument, "invalid secret")
+	}
+
+	args := authentication.LoginArgs{
+		AccountID: req.GetAccountId(),
+		Secret:    req.GetSecret(),
+	}
+	if _, err := h.authHandler.Login(ctx, &args); err != nil {
+		return nil, grpcutils.MapError(err, errorStatuses)
+	}
+
+	sess, err := h.sessionPool.CreateSession(ctx, args.AccountID, nil)
+	if err != nil {
+		return nil, grpcutils.MapError(err, errorStatuses)
+	}
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(grpcutils.SessionIDMetadata, sess.ID)); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &gamepb.LoginResponse{}, nil
+}
+
+// Logout ends the session of the call right away, instead of waiting for it
+// to expire.
+func (h *Handler) Logout(ctx context.Context, req *gamepb.LogoutRequest) (*gamepb.LogoutResponse, error) {
+	h.sessionPool.RemoveSession(ctx, grpcutils.SessionID(ctx))
+
+	return &gamepb.LogoutResponse{}, nil
+}
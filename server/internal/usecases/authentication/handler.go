@@ -1,62 +1,125 @@
 package authentication
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"fmt"
+	"log"
 	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/usecases/configs"
 	"technical-test-backend/internal/usecases/players"
 	"time"
+
+	"github.com/google/uuid"
 )
 
-type AuthenticateArgs struct {
-	AccountID   string `json:"accountId"`
-	AccessToken string `json:"accessToken"`
+const ErrorCodeInvalidCredentials = "INVALID_CREDENTIALS"
+
+var (
+	ErrInvalidCredentials = errors.NewCoded(ErrorCodeInvalidCredentials, "invalid credentials")
+)
+
+// secretSize is the number of random bytes of a secret.
+const secretSize = 32
+
+// unknownAccountSecretHash is compared against when logging into an unknown
+// account, so it takes as long as a wrong secret.
+var unknownAccountSecretHash = players.HashSecret("")
+
+type RegisterArgs struct{}
+
+// RegisterRes holds the credentials of the new account. The secret can't be
+// recovered afterwards, as only its hash is stored.
+type RegisterRes struct {
+	AccountID string `json:"accountId"`
+	Secret    string `json:"secret"`
+}
+
+type LoginArgs struct {
+	AccountID string `json:"accountId"`
+	Secret    string `json:"secret"`
 }
 
-type AuthenticateRes struct{}
+type LoginRes struct{}
+
+type LogoutRes struct{}
 
 type Handler struct {
-	dal players.AccountDAL
+	dal             players.AccountDAL
+	configsProvider *configs.Provider
 }
 
-func NewHandler(dal players.AccountDAL) *Handler {
+func NewHandler(dal players.AccountDAL, configsProvider *configs.Provider) *Handler {
 	return &Handler{
-		dal: dal,
+		dal:             dal,
+		configsProvider: configsProvider,
+	}
+}
+
+func (h *Handler) Register(ctx context.Context, args *RegisterArgs) (*RegisterRes, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %v", err)
+	}
+
+	accountID := uuid.New().String()
+
+	initialState, err := h.createInitialState(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	account := players.Account{
+		ID:         accountID,
+		SecretHash: players.HashSecret(secret),
 	}
+	if err := h.dal.CreateAccount(ctx, account, initialState); err != nil {
+		return nil, fmt.Errorf("failed to create account: %w", err)
+	}
+
+	return &RegisterRes{
+		AccountID: accountID,
+		Secret:    secret,
+	}, nil
 }
 
-func (h *Handler) Authenticate(args *AuthenticateArgs) (*AuthenticateRes, error) {
-	accessToken, err := h.dal.GetAccessToken(args.AccountID)
+func (h *Handler) Login(ctx context.Context, args *LoginArgs) (*LoginRes, error) {
+	secretHash, err := h.dal.GetSecretHash(ctx, args.AccountID)
+	accountExists := err == nil
+	if errors.Is(err, players.ErrAccountNotFound) {
+		secretHash = unknownAccountSecretHash
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	matches := subtle.ConstantTimeCompare([]byte(players.HashSecret(args.Secret)), []byte(secretHash)) == 1
+	if !accountExists || !matches {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &LoginRes{}, nil
+}
+
+func (h *Handler) createInitialState(accountID string) (core.PersistentState, error) {
+	configs, err := h.configsProvider.GetConfigs()
 	if err != nil {
-		if err.Error() == "account not found" {
-			account := players.Account{
-				ID:          args.AccountID,
-				AccessToken: args.AccessToken,
-			}
-			if err := h.dal.CreateAccount(account, createInitialState()); err != nil {
-				return nil, fmt.Errorf("failed to create account: %v", err)
-			}
-			accessToken = args.AccessToken
-		} else {
-			return nil, fmt.Errorf("failed to get account: %v", err)
-		}
-	}
-
-	if accessToken != args.AccessToken {
-		return nil, fmt.Errorf("invalid access token")
-	}
-
-	return &AuthenticateRes{}, nil
-}
-
-func createInitialState() core.PersistentState {
-	return core.PersistentState{
-		Energy: core.Energy{
-			CurrentAmount:  5,
-			LastRechargeAt: time.Now().UTC(),
-		},
-		LevelProgression: core.LevelProgression{
-			CurrentLevel: 1,
-			Statistics:   []core.LevelStats{},
-		},
+		return core.PersistentState{}, fmt.Errorf("failed to load configs: %v", err)
+	}
+
+	variant := configs.Onboarding.VariantFor(accountID)
+	log.Printf("Onboarding account %s with variant %s", accountID, variant.Name)
+
+	return variant.InitialState(time.Now().UTC()), nil
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
 	}
+
+	return base64.RawURLEncoding.EncodeToString(secret), nil
 }
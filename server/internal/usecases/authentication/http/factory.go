@@ -3,9 +3,10 @@
 import (
 	"technical-test-backend/internal/sessions"
 	"technical-test-backend/internal/usecases/authentication"
+	"technical-test-backend/internal/usecases/configs"
 	"technical-test-backend/internal/usecases/players"
 )
 
-func CreateHTTPHandler(sessionPool sessions.Pool, dal players.AccountDAL) *Handler {
-	return NewHandler(authentication.NewHandler(dal), sessionPool)
+func CreateHTTPHandler(sessionPool sessions.Pool, dal players.AccountDAL, configsProvider *configs.Provider) *Handler {
+	return NewHandler(authentication.NewHandler(dal, configsProvider), sessionPool)
 }
@@ -12,10 +12,15 @@
 
 var (
 	ErrInvalidAccountID   = errors.New("invalid account id")
-	ErrInvalidAccessToken = errors.New("invalid access token")
+	ErrInvalidSecret      = errors.New("invalid secret")
 	ErrInvalidRequestBody = errors.New("invalid request body")
 )
 
+var errorStatuses = httputils.StatusCodes{
+	authentication.ErrorCodeInvalidCredentials: http.StatusUnauthorized,
+	sessions.ErrorCodeSessionLimitReached:      http.StatusConflict,
+}
+
 type Handler struct {
 	authHandler *authentication.Handler
 	sessionPool sessions.Pool
@@ -28,42 +33,53 @@ func NewHandler(authHandler *authentication.Handler, sessionPool sessions.Pool)
 	}
 }
 
-func (h *Handler) HandleAuthenticate(w http.ResponseWriter, r *http.Request) {
-	var args authentication.AuthenticateArgs
+func (h *Handler) HandleRegister(w http.ResponseWriter, r *http.Request) {
+	var args authentication.RegisterArgs
 	if err := httputils.DecodeJSON(r, &args); err != nil {
 		httputils.WriteError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
 		return
 	}
 
-	if args.AccountID == "" {
-		httputils.WriteError(w, http.StatusBadRequest, ErrInvalidAccountID.Error())
+	res, err := h.authHandler.Register(r.Context(), &args)
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	if _, err := uuid.Parse(args.AccountID); err != nil {
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h *Handler) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	var args authentication.LoginArgs
+	if err := httputils.DecodeJSON(r, &args); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if args.AccountID == "" {
 		httputils.WriteError(w, http.StatusBadRequest, ErrInvalidAccountID.Error())
 		return
 	}
 
-	if args.AccessToken == "" {
-		httputils.WriteError(w, http.StatusBadRequest, ErrInvalidAccessToken.Error())
+	if _, err := uuid.Parse(args.AccountID); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, ErrInvalidAccountID.Error())
 		return
 	}
 
-	if _, err := uuid.Parse(args.AccessToken); err != nil {
-		httputils.WriteError(w, http.StatusBadRequest, ErrInvalidAccessToken.Error())
+	if args.Secret == "" {
+		httputils.WriteError(w, http.StatusBadRequest, ErrInvalidSecret.Error())
 		return
 	}
 
-	res, err := h.authHandler.Authenticate(&args)
+	res, err := h.authHandler.Login(r.Context(), &args)
 	if err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		httputils.WriteMappedError(w, err, errorStatuses)
 		return
 	}
 
-	sess, err := h.sessionPool.CreateSession(args.AccountID, nil)
+	sess, err := h.sessionPool.CreateSession(r.Context(), args.AccountID, nil)
 	if err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		httputils.WriteMappedError(w, err, errorStatuses)
 		return
 	}
 
@@ -71,3 +87,13 @@ func (h *Handler) HandleAuthenticate(w http.ResponseWriter, r *http.Request) {
 
 	httputils.WriteJSON(w, http.StatusOK, res)
 }
+
+// HandleLogout ends the session of the request right away, instead of waiting
+// for it to expire.
+func (h *Handler) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get("X-Session-ID")
+
+	h.sessionPool.RemoveSession(r.Context(), sessionID)
+
+	httputils.WriteJSON(w, http.StatusOK, authentication.LogoutRes{})
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"technical-test-backend/internal/core"
+	corecommands "technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/sessions"
+	"time"
+)
+
+// HandleSessionEvent resolves the level left in progress by a session that
+// ended, so the energy spent to begin it isn't lost with the session data.
+func (h *Handler) HandleSessionEvent(event sessions.Event) {
+	switch event.Type {
+	case sessions.EventReplaced, sessions.EventExpired, sessions.EventRemoved, sessions.EventRevoked:
+	default:
+		return
+	}
+
+	var storedData StoredSessionData
+	if err := event.DecodeData(&storedData); err != nil {
+		log.Printf("Failed to decode data of session %s: %v", event.Session.ID, err)
+		return
+	}
+
+	if storedData.CurrentLevelID == nil {
+		return
+	}
+
+	// Not bound to the request that ended the session, the level must be
+	// resolved even if it was cancelled.
+	ctx := context.Background()
+	if h.config.AbandonTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.config.AbandonTimeout)
+		defer cancel()
+	}
+
+	if err := h.AbandonLevel(ctx, event.Session.AccountID, storedData.SessionState); err != nil {
+		log.Printf("Failed to abandon level %d of account %s: %v", *storedData.CurrentLevelID, event.Session.AccountID, err)
+		return
+	}
+
+	log.Printf("Abandoned level %d of account %s", *storedData.CurrentLevelID, event.Session.AccountID)
+}
+
+// AbandonLevel resolves the level in progress in the given session state,
+// following the configured AbandonedLevelPolicy. The account is locked like
+// for HandleStored, as the other sessions of the account may be running
+// commands. Sessions publish their events once their own calls are done, so
+// the lock is never already held by the caller.
+func (h *Handler) AbandonLevel(ctx context.Context, accountID string, sessionState core.SessionState) error {
+	unlock, err := h.LockAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return h.Handle(ctx, SessionData{AccountID: accountID, SessionState: &sessionState}, &corecommands.AbandonLevel{Now: time.Now().UTC()})
+}
@@ -0,0 +1,186 @@
+//go:build unit
+// +build unit
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/core"
+	corecommands "technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/sessions/memory"
+	playersmemory "technical-test-backend/internal/usecases/players/dal/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSessionEvent_WithLevelInProgress_ShouldAbandonLevel(t *testing.T) {
+	table := map[string]func(sp *memory.SessionPool, session sessions.Session){
+		"replaced": func(sp *memory.SessionPool, session sessions.Session) {
+			_, err := sp.CreateSession(context.Background(), session.AccountID, nil)
+			require.NoError(t, err)
+		},
+		"removed": func(sp *memory.SessionPool, session sessions.Session) {
+			sp.RemoveSession(context.Background(), session.ID)
+		},
+		"revoked": func(sp *memory.SessionPool, session sessions.Session) {
+			sp.RevokeSessions(context.Background(), session.AccountID)
+		},
+		"expired": func(sp *memory.SessionPool, session sessions.Session) {
+			time.Sleep(100 * time.Millisecond)
+			sp.CleanupExpiredSessions()
+		},
+	}
+
+	for name, endSession := range table {
+		t.Run(name, func(t *testing.T) {
+			dal := playersmemory.NewDAL()
+			newTestAccount(t, dal, "account-1", 10)
+
+			handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+			sp := memory.NewSessionPool(memory.SessionPoolConfig{TTL: 50 * time.Millisecond})
+			sp.Subscribe(handler.HandleSessionEvent)
+
+			session, err := sp.CreateSession(context.Background(), "account-1", nil)
+			require.NoError(t, err)
+
+			sessionState := core.SessionState{}
+			err = handler.Handle(context.Background(), SessionData{AccountID: "account-1", SessionState: &sessionState}, &corecommands.BeginLevel{
+				LevelID: 1,
+				Now:     time.Now().UTC(),
+			})
+			require.NoError(t, err)
+			require.NoError(t, sp.SetSessionData(context.Background(), session.ID, StoredSessionData{SessionState: sessionState}))
+
+			endSession(sp, session)
+
+			state, err := dal.GetPersistentState(context.Background(), "account-1")
+			require.NoError(t, err)
+			assert.Equal(t, 9, state.Energy.CurrentAmount)
+			assert.Equal(t, []core.LevelStats{{LevelID: 1, Losses: 1}}, state.LevelProgression.Statistics)
+		})
+	}
+}
+
+func TestHandleSessionEvent_WithoutLevelInProgress_ShouldKeepState(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+
+	handler.HandleSessionEvent(sessions.Event{
+		Type:    sessions.EventReplaced,
+		Session: sessions.Session{AccountID: "account-1"},
+		Data:    []byte(`{"lastSequence":3}`),
+	})
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), state.Revision)
+}
+
+// stalledDAL never answers reads before their context is done.
+type stalledDAL struct {
+	*playersmemory.DAL
+}
+
+func (d *stalledDAL) GetPersistentState(ctx context.Context, accountID string) (core.PersistentState, error) {
+	<-ctx.Done()
+	return core.PersistentState{}, ctx.Err()
+}
+
+func TestHandleSessionEvent_WithStalledStorage_ShouldGiveUpAfterTimeout(t *testing.T) {
+	dal := &stalledDAL{DAL: playersmemory.NewDAL()}
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1, AbandonTimeout: 10 * time.Millisecond}, dal, newTestProvider(t))
+
+	levelID := 1
+	data, err := json.Marshal(StoredSessionData{SessionState: core.SessionState{CurrentLevelID: &levelID}})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		handler.HandleSessionEvent(sessions.Event{
+			Type:    sessions.EventExpired,
+			Session: sessions.Session{ID: "session-1", AccountID: "account-1"},
+			Data:    data,
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("HandleSessionEvent didn't give up on the stalled storage")
+	}
+}
+
+func TestAbandonLevel_WhenAccountIsLocked_ShouldWaitForTheLock(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+
+	sessionState := core.SessionState{}
+	err := handler.Handle(context.Background(), SessionData{AccountID: "account-1", SessionState: &sessionState}, &corecommands.BeginLevel{
+		LevelID: 1,
+		Now:     time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	unlock, err := handler.LockAccount(context.Background(), "account-1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, handler.AbandonLevel(ctx, "account-1", sessionState), context.DeadlineExceeded)
+
+	unlock()
+
+	require.NoError(t, handler.AbandonLevel(context.Background(), "account-1", sessionState))
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, []core.LevelStats{{LevelID: 1, Losses: 1}}, state.LevelProgression.Statistics)
+}
+
+func TestAbandonLevel_WithRefundPolicy_ShouldRefundEnergy(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	provider := newTestProviderWithConfig(t, `{
+		"energy": {"maxEnergy": 100, "rechargeIntervalSeconds": 3600},
+		"levels": [{}, {"energyCost": 3, "maxRolls": 10, "targetNumber": 1, "energyReward": 2}],
+		"onboarding": {"variants": [{"name": "default", "weight": 1, "energy": 5, "currentLevel": 1}]},
+		"abandonedLevelPolicy": "refund"
+	}`)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, provider)
+
+	sessionState := core.SessionState{}
+	err := handler.Handle(context.Background(), SessionData{AccountID: "account-1", SessionState: &sessionState}, &corecommands.BeginLevel{
+		LevelID: 1,
+		Now:     time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	t.Run("seeded", func(t *testing.T) {
+		require.NoError(t, handler.AbandonLevel(context.Background(), "account-1", sessionState))
+
+		state, err := dal.GetPersistentState(context.Background(), "account-1")
+		require.NoError(t, err)
+		assert.Equal(t, 7, state.Energy.CurrentAmount)
+		assert.Equal(t, []core.LevelStats{{LevelID: 1, Losses: 1}}, state.LevelProgression.Statistics)
+	})
+
+	t.Run("not seeded", func(t *testing.T) {
+		sessionState.LevelSeed = nil
+		require.NoError(t, handler.AbandonLevel(context.Background(), "account-1", sessionState))
+
+		state, err := dal.GetPersistentState(context.Background(), "account-1")
+		require.NoError(t, err)
+		assert.Equal(t, 10, state.Energy.CurrentAmount)
+		assert.Equal(t, []core.LevelStats{{LevelID: 1, Losses: 1}}, state.LevelProgression.Statistics)
+	})
+}
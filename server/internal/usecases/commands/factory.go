@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"technical-test-backend/internal/metrics"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/configs"
+	"technical-test-backend/internal/usecases/players"
+)
+
+// CreateHandler also subscribes the handler to the sessions events, to
+// resolve the levels abandoned by ended sessions. It's shared by every
+// transport, so they all lock the same accounts. Its metrics are registered
+// on registry.
+func CreateHandler(config Config, sessionPool sessions.Pool, dal players.StateDAL, configsProvider *configs.Provider, registry *metrics.Registry) *Handler {
+	handler := NewHandler(config, dal, configsProvider)
+	handler.metrics = newHandlerMetrics(registry)
+	sessionPool.Subscribe(handler.HandleSessionEvent)
+
+	return handler
+}
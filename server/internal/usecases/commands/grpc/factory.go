@@ -0,0 +1,11 @@
+package grpc
+
+import (
+	corecommands "technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/commands"
+)
+
+func CreateGRPCHandler(commandHandler *commands.Handler, sessionsData sessions.Data, registry *corecommands.Registry) *Handler {
+	return NewHandler(commandHandler, sessionsData, registry)
+}
@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/errors"
+	grpcutils "technical-test-backend/internal/grpc"
+	"technical-test-backend/internal/grpc/gamepb"
+	"technical-test-backend/internal/sessions"
+	usecasescommands "technical-test-backend/internal/usecases/commands"
+
+	"google.golang.org/grpc/codes"
+)
+
+// IndexMetadata is the ErrorInfo metadata key holding the index of the
+// command of a batch that failed.
+const IndexMetadata = "index"
+
+var errorStatuses = grpcutils.StatusCodes{
+	commands.ErrorCodeLevelNotUnlocked:        codes.FailedPrecondition,
+	commands.ErrorCodeNotEnoughEnergy:         codes.FailedPrecondition,
+	commands.ErrorCodeNoLevelInProgress:       codes.FailedPrecondition,
+	commands.ErrorCodeInvalidRolls:            codes.FailedPrecondition,
+	commands.ErrorCodeResultMismatch:          codes.FailedPrecondition,
+	commands.ErrorCodeInvalidLevel:            codes.FailedPrecondition,
+	usecasescommands.ErrorCodeInvalidRequest:  codes.InvalidArgument,
+	usecasescommands.ErrorCodeInvalidCommand:  codes.InvalidArgument,
+	usecasescommands.ErrorCodeTimestampTooFar: codes.InvalidArgument,
+	usecasescommands.ErrorCodeInvalidBatch:    codes.InvalidArgument,
+	usecasescommands.ErrorCodeStateConflict:   codes.Aborted,
+	usecasescommands.ErrorCodeSequenceGap:     codes.FailedPrecondition,
+	sessions.ErrorCodeSessionDataMissing:      codes.Unauthenticated,
+}
+
+type Handler struct {
+	gamepb.UnimplementedCommandHandlerServer
+
+	commandHandler *usecasescommands.Handler
+	sessionsData   sessions.Data
+	registry       *commands.Registry
+}
+
+func NewHandler(commandHandler *usecasescommands.Handler, sessionsData sessions.Data, registry *commands.Registry) *Handler {
+	return &Handler{
+		commandHandler: commandHandler,
+		sessionsData:   sessionsData,
+		registry:       registry,
+	}
+}
+
+func (h *Handler) HandleCommand(ctx context.Context, req *gamepb.CommandRequest) (*gamepb.CommandResponse, error) {
+	command, err := h.parseCommand(req.GetCommand(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := h.commandHandler.HandleStored(ctx, h.sessionsData, grpcutils.SessionID(ctx), grpcutils.AccountID(ctx), req.GetSequence(), []core.Command{command})
+	if err != nil {
+		var batchErr *usecasescommands.BatchError
+		if errors.As(err, &batchErr) {
+			err = batchErr.Err
+		}
+
+		return nil, grpcutils.MapError(err, errorStatuses)
+	}
+
+	return commandResponse(res), nil
+}
+
+func (h *Handler) HandleCommands(ctx context.Context, req *gamepb.BatchCommandRequest) (*gamepb.CommandResponse, error) {
+	commands := make([]core.Command, 0, len(req.GetCommands()))
+	for i, protoCommand := range req.GetCommands() {
+		command, err := h.parseCommand(protoCommand, indexMetadata(i))
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+
+	res, err := h.commandHandler.HandleStored(ctx, h.sessionsData, grpcutils.SessionID(ctx), grpcutils.AccountID(ctx), req.GetSequence(), commands)
+	if err != nil {
+		var batchErr *usecasescommands.BatchError
+		if errors.As(err, &batchErr) {
+			return nil, grpcutils.MapErrorWithMetadata(batchErr.Err, errorStatuses, indexMetadata(batchErr.Index))
+		}
+
+		return nil, grpcutils.MapError(err, errorStatuses)
+	}
+
+	return commandResponse(res), nil
+}
+
+func (h *Handler) parseCommand(command *gamepb.Command, metadata map[string]string) (core.Command, error) {
+	parsed, err := usecasescommands.ParseCommand(h.registry, command.GetCommand(), json.RawMessage(command.GetData()))
+	if err != nil {
+		return nil, grpcutils.MapErrorWithMetadata(err, errorStatuses, metadata)
+	}
+
+	return parsed, nil
+}
+
+func commandResponse(res usecasescommands.CommandRes) *gamepb.CommandResponse {
+	return &gamepb.CommandResponse{
+		Session:   grpcutils.SessionStateToProto(*res.Session),
+		Sequence:  res.Sequence,
+		Duplicate: res.Duplicate,
+	}
+}
+
+func indexMetadata(index int) map[string]string {
+	return map[string]string{IndexMetadata: strconv.Itoa(index)}
+}
@@ -1,27 +1,70 @@
 package commands
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"technical-test-backend/internal/core"
+	corecommands "technical-test-backend/internal/core/commands"
 	"technical-test-backend/internal/errors"
 	"technical-test-backend/internal/usecases/configs"
 	"technical-test-backend/internal/usecases/players"
 	"time"
 )
 
+const (
+	ErrorCodeInvalidRequest  = "INVALID_REQUEST"
+	ErrorCodeInvalidCommand  = "INVALID_COMMAND"
+	ErrorCodeTimestampTooFar = "TIMESTAMP_TOO_FAR"
+	ErrorCodeStateConflict   = "STATE_CONFLICT"
+	ErrorCodeInvalidBatch    = "INVALID_BATCH"
+	ErrorCodeSequenceGap     = "SEQUENCE_GAP"
+)
+
 var (
-	ErrCommandTimestampTooFar  = errors.New("command timestamp is too far")
+	ErrInvalidRequest          = errors.NewCoded(ErrorCodeInvalidRequest, "invalid request body")
+	ErrMissingCommand          = errors.NewCoded(ErrorCodeInvalidRequest, "missing command argument")
+	ErrMissingCommandData      = errors.NewCoded(ErrorCodeInvalidRequest, "missing data argument")
+	ErrUnknownCommand          = errors.NewCoded(ErrorCodeInvalidCommand, "unknown command")
+	ErrInvalidCommandData      = errors.NewCoded(ErrorCodeInvalidCommand, "invalid command data")
+	ErrCommandTimestampTooFar  = errors.NewCoded(ErrorCodeTimestampTooFar, "command timestamp is too far")
 	ErrCommandExecutionFailure = errors.New("command execution failed")
+	ErrStateConflict           = errors.NewCoded(ErrorCodeStateConflict, "state was modified concurrently")
+	ErrEmptyBatch              = errors.NewCoded(ErrorCodeInvalidBatch, "empty command batch")
+	ErrBatchTooLarge           = errors.NewCoded(ErrorCodeInvalidBatch, "command batch is too large")
+	ErrFailedToGenerateSeed    = errors.New("failed to generate seed")
 )
 
 type SessionData struct {
 	AccountID    string
 	SessionState *core.SessionState
+	// LastSequence is the sequence number of the last command applied in the
+	// session. It is only read and updated by HandleSequenced.
+	LastSequence *uint64
+	// SessionID, when set, makes HandleSequenced write the last sequence
+	// along with the persistent state, as a receipt of the session.
+	SessionID string
+}
+
+// StoredSessionData is the value kept in sessions.Data for each session. The
+// embedded SessionState keeps it readable as a plain core.SessionState.
+type StoredSessionData struct {
+	core.SessionState
+	LastSequence uint64 `json:"lastSequence,omitempty"`
 }
 
 type Config struct {
 	MaxTimeDifferenceSeconds float64
+	// MaxConflictRetries is how many times a command is re-executed against
+	// a freshly loaded state when a concurrent write wins the race.
+	MaxConflictRetries int
+	// MaxBatchSize limits the number of commands accepted in a single batch.
+	MaxBatchSize int
+	// AbandonTimeout bounds the resolution of a level left in progress by an
+	// ended session. Zero disables it.
+	AbandonTimeout time.Duration
 }
 
 func (c *Config) MaxTimeDifference() time.Duration {
@@ -29,14 +72,78 @@ func (c *Config) MaxTimeDifference() time.Duration {
 }
 
 type CommandArgs struct {
-	Command string          `json:"command"`
-	Data    json.RawMessage `json:"data"`
+	Command  string          `json:"command"`
+	Data     json.RawMessage `json:"data"`
+	Sequence uint64          `json:"sequence,omitempty"`
+}
+
+// ParseCommand parses a command received by a transport, rejecting it with
+// an INVALID_REQUEST or INVALID_COMMAND error.
+func ParseCommand(registry *corecommands.Registry, name string, data json.RawMessage) (core.Command, error) {
+	if name == "" {
+		return nil, ErrMissingCommand
+	}
+
+	if len(data) == 0 {
+		return nil, ErrMissingCommandData
+	}
+
+	command, err := registry.Parse(name, data)
+	if errors.Is(err, corecommands.ErrUnknownCommand) {
+		return nil, ErrUnknownCommand
+	}
+	if err != nil {
+		return nil, ErrInvalidCommandData.Detailf("%v", err)
+	}
+
+	return command, nil
+}
+
+// BatchCommandArgs carries consecutive commands, the first one numbered
+// Sequence, the next one Sequence + 1 and so on.
+type BatchCommandArgs struct {
+	Commands []CommandArgs `json:"commands"`
+	Sequence uint64        `json:"sequence,omitempty"`
+}
+
+type CommandDescription struct {
+	Name   string               `json:"name"`
+	Schema *corecommands.Schema `json:"schema"`
+}
+
+type ListCommandsRes struct {
+	Commands []CommandDescription `json:"commands"`
+}
+
+// CommandRes carries the session state after the commands, which holds the
+// seed of a level that was just begun.
+type CommandRes struct {
+	Session   *core.SessionState `json:"session,omitempty"`
+	Sequence  uint64             `json:"sequence,omitempty"`
+	Duplicate bool               `json:"duplicate,omitempty"`
+}
+
+// BatchError reports the first command of a batch that failed. None of the
+// batch commands are persisted when it is returned.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("command %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
 }
 
 type Handler struct {
 	config          Config
 	dal             players.StateDAL
 	configsProvider *configs.Provider
+	locks           *accountLocks
+	metrics         *handlerMetrics
 }
 
 func NewHandler(config Config, dal players.StateDAL, configsProvider *configs.Provider) *Handler {
@@ -44,26 +151,66 @@ func NewHandler(config Config, dal players.StateDAL, configsProvider *configs.Pr
 		config:          config,
 		dal:             dal,
 		configsProvider: configsProvider,
+		locks:           newAccountLocks(),
 	}
 }
 
-func (h *Handler) Handle(sessionData SessionData, command core.Command) error {
-	if timedCmd, ok := command.(core.TimedCommand); ok {
-		now := time.Now().UTC()
-		timeDifference := timedCmd.GetTimestamp().Sub(now)
-		if timeDifference > h.config.MaxTimeDifference() {
-			return ErrCommandTimestampTooFar
-		}
+// LockAccount serializes command handling for an account. Callers must hold it
+// from reading the session data until storing it back, so concurrent
+// submissions of the same sequence number can't both be applied. Waiting for
+// it gives up with ctx.Err() once ctx is done.
+func (h *Handler) LockAccount(ctx context.Context, accountID string) (func(), error) {
+	return h.locks.lock(ctx, accountID)
+}
+
+func (h *Handler) Handle(ctx context.Context, sessionData SessionData, command core.Command) error {
+	err := h.HandleBatch(ctx, sessionData, []core.Command{command})
+
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		return batchErr.Err
 	}
 
-	persistentState, err := h.dal.GetPersistentState(sessionData.AccountID)
-	if err != nil {
-		return fmt.Errorf("failed to get persistent state: %v", err)
+	return err
+}
+
+// HandleBatch executes the commands in order against a single loaded player
+// state, and persists the result only if all of them succeed.
+func (h *Handler) HandleBatch(ctx context.Context, sessionData SessionData, commands []core.Command) error {
+	if err := h.validateBatchSize(commands); err != nil {
+		return err
 	}
 
-	playerState := core.PlayerState{
-		Persistent: &persistentState,
-		Session:    sessionData.SessionState,
+	return h.handleObservedBatch(ctx, sessionData, commands, nil)
+}
+
+func (h *Handler) handleObservedBatch(ctx context.Context, sessionData SessionData, commands []core.Command, receipt *players.Receipt) error {
+	err := h.handleBatch(ctx, sessionData, commands, receipt)
+	h.metrics.observe(commands, err)
+	return err
+}
+
+// handleBatch writes the receipt, if any, along with the persistent state.
+func (h *Handler) handleBatch(ctx context.Context, sessionData SessionData, commands []core.Command, receipt *players.Receipt) error {
+	now := time.Now().UTC()
+	for i, command := range commands {
+		if timedCmd, ok := command.(core.TimedCommand); ok {
+			timeDifference := timedCmd.GetTimestamp().Sub(now)
+			if timeDifference > h.config.MaxTimeDifference() {
+				return &BatchError{Index: i, Err: ErrCommandTimestampTooFar}
+			}
+		}
+	}
+
+	// Seeds are drawn once, so retries replay the same outcome.
+	for _, command := range commands {
+		if seededCmd, ok := command.(core.SeededCommand); ok {
+			seed, err := newSeed()
+			if err != nil {
+				return errors.Wrap(ErrFailedToGenerateSeed, err)
+			}
+			seededCmd.SetSeed(seed)
+		}
 	}
 
 	configs, err := h.configsProvider.GetConfigs()
@@ -71,17 +218,73 @@ func (h *Handler) Handle(sessionData SessionData, command core.Command) error {
 		return fmt.Errorf("failed to load configs: %v", err)
 	}
 
-	err = command.Execute(&playerState, configs)
-	if err != nil {
-		return errors.Wrap(err, ErrCommandExecutionFailure)
+	for attempt := 0; ; attempt++ {
+		sessionState, err := h.execute(ctx, sessionData, commands, configs, receipt)
+		if err == nil {
+			*sessionData.SessionState = sessionState
+			return nil
+		}
+
+		if !errors.Is(err, players.ErrStateConflict) {
+			return err
+		}
+
+		if attempt >= h.config.MaxConflictRetries {
+			return errors.Wrap(ErrStateConflict, err)
+		}
 	}
+}
 
-	err = h.dal.SetPersistentState(sessionData.AccountID, *playerState.Persistent)
-	if err != nil {
-		return fmt.Errorf("failed to save persistent state: %v", err)
+func newSeed() (uint64, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+func (h *Handler) validateBatchSize(commands []core.Command) error {
+	if len(commands) == 0 {
+		return ErrEmptyBatch
 	}
 
-	sessionData.SessionState.CurrentLevelID = playerState.Session.CurrentLevelID
+	if h.config.MaxBatchSize > 0 && len(commands) > h.config.MaxBatchSize {
+		return ErrBatchTooLarge
+	}
 
 	return nil
 }
+
+// execute runs the commands against the latest persistent state and a copy of
+// the session state, so a failed attempt leaves the caller's state untouched.
+func (h *Handler) execute(ctx context.Context, sessionData SessionData, commands []core.Command, configs core.Configs, receipt *players.Receipt) (core.SessionState, error) {
+	persistentState, err := h.dal.GetPersistentState(ctx, sessionData.AccountID)
+	if err != nil {
+		return core.SessionState{}, fmt.Errorf("failed to get persistent state: %w", err)
+	}
+
+	sessionState := *sessionData.SessionState
+	playerState := core.PlayerState{
+		Persistent: &persistentState,
+		Session:    &sessionState,
+	}
+
+	for i, command := range commands {
+		err = command.Execute(&playerState, configs)
+		if err != nil {
+			return core.SessionState{}, &BatchError{Index: i, Err: errors.Wrap(err, ErrCommandExecutionFailure)}
+		}
+	}
+
+	if receipt != nil {
+		receipt.SessionState = sessionState
+		err = h.dal.SetPersistentStateWithReceipt(ctx, sessionData.AccountID, *playerState.Persistent, *receipt)
+	} else {
+		err = h.dal.SetPersistentState(ctx, sessionData.AccountID, *playerState.Persistent)
+	}
+	if err != nil {
+		return core.SessionState{}, errors.Wrapf(err, "failed to save persistent state")
+	}
+
+	return sessionState, nil
+}
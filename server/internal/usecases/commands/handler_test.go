@@ -0,0 +1,287 @@
+//go:build unit
+// +build unit
+
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/core"
+	corecommands "technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/core/dice"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/usecases/configs"
+	"technical-test-backend/internal/usecases/players"
+	playersmemory "technical-test-backend/internal/usecases/players/dal/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigJSON = `{
+	"energy": {"maxEnergy": 100, "rechargeIntervalSeconds": 3600},
+	"levels": [{}, {"energyCost": 1, "maxRolls": 10, "targetNumber": 1, "energyReward": 2}],
+	"onboarding": {"variants": [{"name": "default", "weight": 1, "energy": 5, "currentLevel": 1}]}
+}`
+
+// racingDAL simulates a concurrent writer by committing a competing update
+// right before the first `conflicts` writes.
+type racingDAL struct {
+	*playersmemory.DAL
+	conflicts int
+}
+
+func (d *racingDAL) SetPersistentState(ctx context.Context, accountID string, state core.PersistentState) error {
+	if d.conflicts > 0 {
+		d.conflicts--
+		current, err := d.DAL.GetPersistentState(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		current.Energy.CurrentAmount--
+		if err := d.DAL.SetPersistentState(ctx, accountID, current); err != nil {
+			return err
+		}
+	}
+
+	return d.DAL.SetPersistentState(context.Background(), accountID, state)
+}
+
+func newTestProvider(t *testing.T) *configs.Provider {
+	return newTestProviderWithConfig(t, testConfigJSON)
+}
+
+func newTestProviderWithConfig(t *testing.T, configJSON string) *configs.Provider {
+	path := filepath.Join(t.TempDir(), "game_config.json")
+	require.NoError(t, os.WriteFile(path, []byte(configJSON), 0o600))
+	return configs.NewProvider(configs.ProviderConfig{FilePath: path})
+}
+
+func newTestAccount(t *testing.T, dal players.DAL, accountID string, energy int) {
+	require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: accountID}, core.PersistentState{
+		Energy: core.Energy{
+			CurrentAmount:  energy,
+			LastRechargeAt: time.Now().UTC(),
+		},
+		LevelProgression: core.LevelProgression{
+			CurrentLevel: 1,
+			Statistics:   []core.LevelStats{},
+		},
+	}))
+}
+
+func TestHandle_WithConflict_ShouldRetry(t *testing.T) {
+	dal := &racingDAL{DAL: playersmemory.NewDAL(), conflicts: 2}
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1, MaxConflictRetries: 2}, dal, newTestProvider(t))
+	sessionState := &core.SessionState{}
+
+	err := handler.Handle(context.Background(), SessionData{AccountID: "account-1", SessionState: sessionState}, &corecommands.BeginLevel{
+		LevelID: 1,
+		Now:     time.Now().UTC(),
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, sessionState.CurrentLevelID)
+	assert.Equal(t, 1, *sessionState.CurrentLevelID)
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 7, state.Energy.CurrentAmount)
+	assert.Equal(t, int64(3), state.Revision)
+}
+
+func TestHandle_WithConflictAfterRetries_ShouldReturnConflictError(t *testing.T) {
+	dal := &racingDAL{DAL: playersmemory.NewDAL(), conflicts: 3}
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1, MaxConflictRetries: 2}, dal, newTestProvider(t))
+	sessionState := &core.SessionState{}
+
+	err := handler.Handle(context.Background(), SessionData{AccountID: "account-1", SessionState: sessionState}, &corecommands.BeginLevel{
+		LevelID: 1,
+		Now:     time.Now().UTC(),
+	})
+
+	assert.ErrorIs(t, err, ErrStateConflict)
+	assert.ErrorIs(t, err, players.ErrStateConflict)
+	assert.Nil(t, sessionState.CurrentLevelID)
+}
+
+func TestHandle_ConcurrentCommands_ShouldNotLoseUpdates(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 50)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1, MaxConflictRetries: 100}, dal, newTestProvider(t))
+
+	const numGoroutines = 20
+	var wg sync.WaitGroup
+	results := make(chan error, numGoroutines)
+
+	for range numGoroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- handler.Handle(context.Background(), SessionData{AccountID: "account-1", SessionState: &core.SessionState{}}, &corecommands.BeginLevel{
+				LevelID: 1,
+				Now:     time.Now().UTC(),
+			})
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+
+	for err := range results {
+		assert.NoError(t, err)
+	}
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 50-numGoroutines, state.Energy.CurrentAmount)
+	assert.Equal(t, int64(numGoroutines), state.Revision)
+}
+
+func TestHandleBatch_AllSucceed_ShouldPersistOnce(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionState := &core.SessionState{}
+
+	err := handler.HandleBatch(context.Background(), SessionData{AccountID: "account-1", SessionState: sessionState}, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+		&corecommands.EndLevel{Success: false},
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, sessionState.CurrentLevelID)
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 8, state.Energy.CurrentAmount)
+	assert.Equal(t, int64(1), state.Revision)
+	require.Len(t, state.LevelProgression.Statistics, 1)
+	assert.Equal(t, 1, state.LevelProgression.Statistics[0].Losses)
+}
+
+func TestHandleBatch_SeededLevel_ShouldVerifyResult(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionData := SessionData{AccountID: "account-1", SessionState: &core.SessionState{}}
+
+	err := handler.Handle(context.Background(), sessionData, &corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()})
+	require.NoError(t, err)
+	require.NotNil(t, sessionData.SessionState.LevelSeed)
+
+	rolls, success := dice.Play(*sessionData.SessionState.LevelSeed, 10, 1)
+
+	err = handler.Handle(context.Background(), sessionData, &corecommands.EndLevel{Success: !success, Score: 10 - rolls, Rolls: &rolls})
+	assert.ErrorIs(t, err, corecommands.ErrResultMismatch)
+
+	err = handler.Handle(context.Background(), sessionData, &corecommands.EndLevel{Success: success, Score: 10 - rolls, Rolls: &rolls})
+	require.NoError(t, err)
+	assert.Nil(t, sessionData.SessionState.LevelSeed)
+}
+
+func TestHandleBatch_SeededLevelWithoutRolls_ShouldTrustResult(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionData := SessionData{AccountID: "account-1", SessionState: &core.SessionState{}}
+
+	err := handler.Handle(context.Background(), sessionData, &corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()})
+	require.NoError(t, err)
+	require.NotNil(t, sessionData.SessionState.LevelSeed)
+
+	err = handler.Handle(context.Background(), sessionData, &corecommands.EndLevel{Success: true, Score: 5})
+	require.NoError(t, err)
+	assert.Nil(t, sessionData.SessionState.LevelSeed)
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, state.LevelProgression.Statistics[0].Wins)
+}
+
+func TestHandleBatch_WithFailingCommand_ShouldReturnIndexAndNotPersist(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionState := &core.SessionState{}
+
+	err := handler.HandleBatch(context.Background(), SessionData{AccountID: "account-1", SessionState: sessionState}, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+		&corecommands.EndLevel{Success: false},
+		&corecommands.EndLevel{Success: false},
+	})
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 2, batchErr.Index)
+	assert.ErrorIs(t, err, corecommands.ErrNoLevelInProgress)
+	assert.Nil(t, sessionState.CurrentLevelID)
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 10, state.Energy.CurrentAmount)
+	assert.Equal(t, int64(0), state.Revision)
+}
+
+func TestHandleBatch_WithTimestampTooFar_ShouldReturnIndex(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+
+	err := handler.HandleBatch(context.Background(), SessionData{AccountID: "account-1", SessionState: &core.SessionState{}}, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC().Add(time.Minute)},
+	})
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.Index)
+	assert.ErrorIs(t, err, ErrCommandTimestampTooFar)
+}
+
+func TestHandleBatch_InvalidSize_ShouldReturnError(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1, MaxBatchSize: 1}, dal, newTestProvider(t))
+	sessionData := SessionData{AccountID: "account-1", SessionState: &core.SessionState{}}
+
+	err := handler.HandleBatch(context.Background(), sessionData, []core.Command{})
+	assert.ErrorIs(t, err, ErrEmptyBatch)
+
+	err = handler.HandleBatch(context.Background(), sessionData, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+		&corecommands.EndLevel{Success: true},
+	})
+	assert.ErrorIs(t, err, ErrBatchTooLarge)
+}
+
+func TestHandle_WithFailingCommand_ShouldNotReturnBatchError(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+
+	err := handler.Handle(context.Background(), SessionData{AccountID: "account-1", SessionState: &core.SessionState{}}, &corecommands.EndLevel{})
+
+	var batchErr *BatchError
+	assert.False(t, errors.As(err, &batchErr))
+	assert.ErrorIs(t, err, corecommands.ErrNoLevelInProgress)
+	assert.ErrorIs(t, err, ErrCommandExecutionFailure)
+}
@@ -1,12 +1,11 @@
 package http
 
 import (
+	corecommands "technical-test-backend/internal/core/commands"
 	"technical-test-backend/internal/sessions"
 	"technical-test-backend/internal/usecases/commands"
-	"technical-test-backend/internal/usecases/configs"
-	"technical-test-backend/internal/usecases/players"
 )
 
-func CreateHTTPHandler(config commands.Config, sessionsData sessions.Data, dal players.StateDAL, configsProvider *configs.Provider) *Handler {
-	return NewHandler(commands.NewHandler(config, dal, configsProvider), sessionsData)
+func CreateHTTPHandler(commandHandler *commands.Handler, sessionsData sessions.Data, registry *corecommands.Registry) *Handler {
+	return NewHandler(commandHandler, sessionsData, registry)
 }
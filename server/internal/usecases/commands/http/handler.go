@@ -1,26 +1,64 @@
 package http
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"technical-test-backend/internal/core"
 	"technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/errors"
 	httputils "technical-test-backend/internal/http"
 	"technical-test-backend/internal/sessions"
 	usecasescommands "technical-test-backend/internal/usecases/commands"
 )
 
+var errorStatuses = httputils.StatusCodes{
+	commands.ErrorCodeLevelNotUnlocked:        http.StatusUnprocessableEntity,
+	commands.ErrorCodeNotEnoughEnergy:         http.StatusUnprocessableEntity,
+	commands.ErrorCodeNoLevelInProgress:       http.StatusUnprocessableEntity,
+	commands.ErrorCodeInvalidRolls:            http.StatusUnprocessableEntity,
+	commands.ErrorCodeResultMismatch:          http.StatusUnprocessableEntity,
+	commands.ErrorCodeInvalidLevel:            http.StatusUnprocessableEntity,
+	usecasescommands.ErrorCodeInvalidRequest:  http.StatusBadRequest,
+	usecasescommands.ErrorCodeInvalidCommand:  http.StatusBadRequest,
+	usecasescommands.ErrorCodeTimestampTooFar: http.StatusBadRequest,
+	usecasescommands.ErrorCodeInvalidBatch:    http.StatusBadRequest,
+	usecasescommands.ErrorCodeStateConflict:   http.StatusConflict,
+	usecasescommands.ErrorCodeSequenceGap:     http.StatusConflict,
+	sessions.ErrorCodeSessionDataMissing:      http.StatusUnauthorized,
+}
+
+type BatchErrorResponse struct {
+	httputils.ErrorResponse
+	Index int `json:"index"`
+}
+
 type Handler struct {
 	commandHandler *usecasescommands.Handler
 	sessionsData   sessions.Data
+	registry       *commands.Registry
 }
 
-func NewHandler(commandHandler *usecasescommands.Handler, sessionsData sessions.Data) *Handler {
+func NewHandler(commandHandler *usecasescommands.Handler, sessionsData sessions.Data, registry *commands.Registry) *Handler {
 	return &Handler{
 		commandHandler: commandHandler,
 		sessionsData:   sessionsData,
+		registry:       registry,
+	}
+}
+
+func (h *Handler) HandleListCommands(w http.ResponseWriter, r *http.Request) {
+	definitions := h.registry.Definitions()
+
+	res := usecasescommands.ListCommandsRes{
+		Commands: make([]usecasescommands.CommandDescription, 0, len(definitions)),
+	}
+	for _, definition := range definitions {
+		res.Commands = append(res.Commands, usecasescommands.CommandDescription{
+			Name:   definition.Name,
+			Schema: definition.Schema,
+		})
 	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
 }
 
 func (h *Handler) HandleCommand(w http.ResponseWriter, r *http.Request) {
@@ -28,65 +66,62 @@ func (h *Handler) HandleCommand(w http.ResponseWriter, r *http.Request) {
 
 	var commandArgs usecasescommands.CommandArgs
 	if err := httputils.DecodeJSON(r, &commandArgs); err != nil {
-		httputils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		httputils.WriteMappedError(w, usecasescommands.ErrInvalidRequest, errorStatuses)
 		return
 	}
 
-	if commandArgs.Command == "" {
-		httputils.WriteError(w, http.StatusBadRequest, "missing command argument")
+	command, err := usecasescommands.ParseCommand(h.registry, commandArgs.Command, commandArgs.Data)
+	if err != nil {
+		httputils.WriteMappedError(w, err, errorStatuses)
 		return
 	}
 
-	if commandArgs.Data == nil {
-		httputils.WriteError(w, http.StatusBadRequest, "missing data argument")
-		return
-	}
+	res, err := h.commandHandler.HandleStored(r.Context(), h.sessionsData, r.Header.Get("X-Session-ID"), accountID, commandArgs.Sequence, []core.Command{command})
+	if err != nil {
+		var batchErr *usecasescommands.BatchError
+		if errors.As(err, &batchErr) {
+			err = batchErr.Err
+		}
 
-	var sessionState core.SessionState
-	if err := h.sessionsData.GetSessionData(accountID, &sessionState); err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, "missing session data")
+		httputils.WriteMappedError(w, err, errorStatuses)
 		return
 	}
 
-	sessionData := usecasescommands.SessionData{
-		AccountID:    accountID,
-		SessionState: &sessionState,
-	}
-
-	command, err := parseCommand(commandArgs)
-	if err != nil {
-		httputils.WriteError(w, http.StatusBadRequest, "invalid command data")
-		return
-	}
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
 
-	if err := h.commandHandler.Handle(sessionData, command); err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
-		return
-	}
+func (h *Handler) HandleCommands(w http.ResponseWriter, r *http.Request) {
+	accountID := r.Header.Get("X-Account-ID")
 
-	if err := h.sessionsData.SetSessionData(accountID, sessionState); err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, "failed to update session data")
+	var batchArgs usecasescommands.BatchCommandArgs
+	if err := httputils.DecodeJSON(r, &batchArgs); err != nil {
+		httputils.WriteMappedError(w, usecasescommands.ErrInvalidRequest, errorStatuses)
 		return
 	}
 
-	httputils.WriteJSON(w, http.StatusOK, map[string]interface{}{})
-}
-
-func parseCommand(args usecasescommands.CommandArgs) (core.Command, error) {
-
-	var command core.Command
-	switch args.Command {
-	case "BeginLevel":
-		command = &commands.BeginLevel{}
-	case "EndLevel":
-		command = &commands.EndLevel{}
-	default:
-		return nil, fmt.Errorf("invalid command")
+	commands := make([]core.Command, 0, len(batchArgs.Commands))
+	for i, commandArgs := range batchArgs.Commands {
+		command, err := usecasescommands.ParseCommand(h.registry, commandArgs.Command, commandArgs.Data)
+		if err != nil {
+			statusCode, errResponse := httputils.MapError(err, errorStatuses)
+			httputils.WriteJSON(w, statusCode, BatchErrorResponse{ErrorResponse: errResponse, Index: i})
+			return
+		}
+		commands = append(commands, command)
 	}
 
-	if err := json.Unmarshal(args.Data, command); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal command: %v", err)
+	res, err := h.commandHandler.HandleStored(r.Context(), h.sessionsData, r.Header.Get("X-Session-ID"), accountID, batchArgs.Sequence, commands)
+	if err != nil {
+		var batchErr *usecasescommands.BatchError
+		if errors.As(err, &batchErr) {
+			statusCode, errResponse := httputils.MapError(batchErr.Err, errorStatuses)
+			httputils.WriteJSON(w, statusCode, BatchErrorResponse{ErrorResponse: errResponse, Index: batchErr.Index})
+			return
+		}
+
+		httputils.WriteMappedError(w, err, errorStatuses)
+		return
 	}
 
-	return command, nil
+	httputils.WriteJSON(w, http.StatusOK, res)
 }
@@ -0,0 +1,54 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corecommands "technical-test-backend/internal/core/commands"
+	usecasescommands "technical-test-backend/internal/usecases/commands"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHandler serves requests rejected before reaching the command handler
+// or the sessions.
+func newTestHandler() *Handler {
+	return NewHandler(nil, nil, corecommands.NewDefaultRegistry())
+}
+
+func TestHandleCommand_WithInvalidData_ShouldDetailError(t *testing.T) {
+	body := `{"command": "BeginLevel", "data": {"levelId": 0}}`
+	recorder := httptest.NewRecorder()
+
+	newTestHandler().HandleCommand(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body)))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	var res BatchErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	assert.Equal(t, usecasescommands.ErrorCodeInvalidCommand, res.Code)
+	assert.Contains(t, res.Message, "levelId must be positive")
+}
+
+func TestHandleCommands_WithInvalidData_ShouldDetailErrorAndIndex(t *testing.T) {
+	body := `{"commands": [
+		{"command": "BeginLevel", "data": {"levelId": 1}},
+		{"command": "BeginLevel", "data": {"levelId": "one"}}
+	]}`
+	recorder := httptest.NewRecorder()
+
+	newTestHandler().HandleCommands(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body)))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	var res BatchErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	assert.Equal(t, usecasescommands.ErrorCodeInvalidCommand, res.Code)
+	assert.Contains(t, res.Message, "levelId")
+	assert.Equal(t, 1, res.Index)
+}
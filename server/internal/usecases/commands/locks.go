@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"sync"
+)
+
+// accountLocks hands out one lock per account, dropping it once no caller
+// holds or waits on it anymore.
+type accountLocks struct {
+	mutex sync.Mutex
+	locks map[string]*accountLock
+}
+
+// accountLock is held by whoever sent to held, so waiting on it can be
+// abandoned.
+type accountLock struct {
+	held chan struct{}
+	refs int
+}
+
+func newAccountLocks() *accountLocks {
+	return &accountLocks{
+		locks: make(map[string]*accountLock),
+	}
+}
+
+// lock waits for the account's lock until ctx is done, returning ctx.Err()
+// then.
+func (l *accountLocks) lock(ctx context.Context, accountID string) (func(), error) {
+	l.mutex.Lock()
+	lock, exists := l.locks[accountID]
+	if !exists {
+		lock = &accountLock{held: make(chan struct{}, 1)}
+		l.locks[accountID] = lock
+	}
+	lock.refs++
+	l.mutex.Unlock()
+
+	select {
+	case lock.held <- struct{}{}:
+	case <-ctx.Done():
+		l.release(accountID, lock)
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-lock.held
+		l.release(accountID, lock)
+	}, nil
+}
+
+func (l *accountLocks) release(accountID string, lock *accountLock) {
+	l.mutex.Lock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, accountID)
+	}
+	l.mutex.Unlock()
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"reflect"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/metrics"
+)
+
+// handlerMetrics counts the commands handled and the ones that failed. A nil
+// handlerMetrics records nothing, for handlers built by NewHandler.
+type handlerMetrics struct {
+	executions *metrics.Counter
+	failures   *metrics.Counter
+}
+
+func newHandlerMetrics(registry *metrics.Registry) *handlerMetrics {
+	return &handlerMetrics{
+		executions: registry.NewCounter("command_executions_total", "Commands executed, by command.", "command"),
+		failures:   registry.NewCounter("command_failures_total", "Commands failed, by command and error type.", "command", "error"),
+	}
+}
+
+// observe records the outcome of a batch. The commands of a batch are counted
+// up to the one that failed, and a failure not caused by a single command is
+// counted for every command of the batch.
+func (m *handlerMetrics) observe(commands []core.Command, err error) {
+	if m == nil {
+		return
+	}
+
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		for _, command := range commands[:batchErr.Index+1] {
+			m.executions.Inc(commandName(command))
+		}
+		m.failures.Inc(commandName(commands[batchErr.Index]), errorType(batchErr.Err))
+		return
+	}
+
+	for _, command := range commands {
+		m.executions.Inc(commandName(command))
+		if err != nil {
+			m.failures.Inc(commandName(command), errorType(err))
+		}
+	}
+}
+
+// commandName names commands by their type, which is the name they're
+// registered with.
+func commandName(command core.Command) string {
+	commandType := reflect.TypeOf(command)
+	if commandType.Kind() == reflect.Pointer {
+		commandType = commandType.Elem()
+	}
+	return commandType.Name()
+}
+
+// errorType reports the error code of the coded errors, and a fixed type for
+// the others so the number of series stays bounded.
+func errorType(err error) string {
+	if coded, ok := errors.AsCoded(err); ok {
+		return coded.Code
+	}
+
+	switch {
+	case errors.Is(err, ErrCommandExecutionFailure):
+		return "EXECUTION_FAILED"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "TIMEOUT"
+	default:
+		return "INTERNAL"
+	}
+}
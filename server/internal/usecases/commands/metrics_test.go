@@ -0,0 +1,51 @@
+//go:build unit
+// +build unit
+
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/core"
+	corecommands "technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/metrics"
+	playersmemory "technical-test-backend/internal/usecases/players/dal/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleBatch_ShouldCountExecutionsAndFailures(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+
+	registry := metrics.NewRegistry()
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	handler.metrics = newHandlerMetrics(registry)
+
+	err := handler.HandleBatch(context.Background(), SessionData{AccountID: "account-1", SessionState: &core.SessionState{}}, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+		&corecommands.EndLevel{Success: false},
+		&corecommands.EndLevel{Success: false},
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = handler.Handle(ctx, SessionData{AccountID: "account-1", SessionState: &core.SessionState{}}, &corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()})
+	require.Error(t, err)
+
+	var output strings.Builder
+	_, err = registry.WriteTo(&output)
+	require.NoError(t, err)
+
+	assert.Contains(t, output.String(), `command_executions_total{command="BeginLevel"} 2`)
+	assert.Contains(t, output.String(), `command_executions_total{command="EndLevel"} 2`)
+	assert.Contains(t, output.String(), `command_failures_total{command="BeginLevel",error="TIMEOUT"} 1`)
+	assert.Contains(t, output.String(), `command_failures_total{command="EndLevel",error="NO_LEVEL_IN_PROGRESS"} 1`)
+	assert.NotContains(t, output.String(), `command_failures_total{command="BeginLevel",error="NO_LEVEL_IN_PROGRESS"}`)
+}
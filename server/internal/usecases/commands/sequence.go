@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/usecases/players"
+)
+
+var (
+	ErrSequenceGap = errors.NewCoded(ErrorCodeSequenceGap, "command sequence gap")
+)
+
+// HandleSequenced applies consecutive commands numbered from sequence onwards.
+// Commands at or below the session's last applied sequence were already
+// applied and are skipped, so retrying a submission whose response was lost is
+// safe. Only successful commands advance the sequence, and a sequence beyond
+// the next expected one is rejected with ErrSequenceGap. A zero sequence
+// disables the tracking altogether.
+//
+// With a SessionID, the last sequence is written in the same write as the
+// persistent state, and the session catches up with it when its own copy was
+// lost, so the commands are never applied twice. A retry of the last batch
+// gets the session state it resulted in.
+func (h *Handler) HandleSequenced(ctx context.Context, sessionData SessionData, sequence uint64, commands []core.Command) (CommandRes, error) {
+	if sequence == 0 {
+		return CommandRes{}, h.HandleBatch(ctx, sessionData, commands)
+	}
+
+	if err := h.validateBatchSize(commands); err != nil {
+		return CommandRes{}, err
+	}
+
+	if sessionData.SessionID != "" {
+		receipt, found, err := h.dal.GetReceipt(ctx, sessionData.AccountID, sessionData.SessionID)
+		if err != nil {
+			return CommandRes{}, fmt.Errorf("failed to get receipt: %w", err)
+		}
+		if found && receipt.Sequence > *sessionData.LastSequence {
+			*sessionData.SessionState = receipt.SessionState
+			*sessionData.LastSequence = receipt.Sequence
+		}
+	}
+
+	lastSequence := *sessionData.LastSequence
+	endSequence := sequence + uint64(len(commands)) - 1
+
+	if endSequence <= lastSequence {
+		res := CommandRes{Sequence: endSequence, Duplicate: true}
+		if endSequence == lastSequence {
+			res.Session = sessionData.SessionState
+		}
+		return res, nil
+	}
+
+	if sequence > lastSequence+1 {
+		return CommandRes{}, ErrSequenceGap.Detailf("expected sequence %d, got %d", lastSequence+1, sequence)
+	}
+
+	var receipt *players.Receipt
+	if sessionData.SessionID != "" {
+		receipt = &players.Receipt{SessionID: sessionData.SessionID, Sequence: endSequence}
+	}
+
+	applied := int(lastSequence + 1 - sequence)
+	err := h.handleObservedBatch(ctx, sessionData, commands[applied:], receipt)
+	if err != nil {
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) {
+			return CommandRes{}, &BatchError{Index: batchErr.Index + applied, Err: batchErr.Err}
+		}
+		return CommandRes{}, err
+	}
+
+	*sessionData.LastSequence = endSequence
+
+	return CommandRes{Sequence: endSequence, Duplicate: applied > 0}, nil
+}
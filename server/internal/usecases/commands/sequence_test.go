@@ -0,0 +1,146 @@
+//go:build unit
+// +build unit
+
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/core"
+	corecommands "technical-test-backend/internal/core/commands"
+	playersmemory "technical-test-backend/internal/usecases/players/dal/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSequencedSessionData(accountID string, lastSequence uint64) SessionData {
+	return SessionData{
+		AccountID:    accountID,
+		SessionState: &core.SessionState{},
+		LastSequence: &lastSequence,
+	}
+}
+
+func TestHandleSequenced_NextSequence_ShouldApply(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionData := newSequencedSessionData("account-1", 0)
+
+	res, err := handler.HandleSequenced(context.Background(), sessionData, 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, CommandRes{Sequence: 1}, res)
+	assert.Equal(t, uint64(1), *sessionData.LastSequence)
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 9, state.Energy.CurrentAmount)
+}
+
+func TestHandleSequenced_DuplicateSequence_ShouldNotReapply(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionData := newSequencedSessionData("account-1", 0)
+	command := &corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()}
+
+	_, err := handler.HandleSequenced(context.Background(), sessionData, 1, []core.Command{command})
+	require.NoError(t, err)
+	sessionState := *sessionData.SessionState
+
+	res, err := handler.HandleSequenced(context.Background(), sessionData, 1, []core.Command{command})
+
+	require.NoError(t, err)
+	assert.Equal(t, CommandRes{Session: &sessionState, Sequence: 1, Duplicate: true}, res)
+	assert.Equal(t, uint64(1), *sessionData.LastSequence)
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 9, state.Energy.CurrentAmount)
+}
+
+func TestHandleSequenced_SequenceGap_ShouldReturnError(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionData := newSequencedSessionData("account-1", 1)
+
+	_, err := handler.HandleSequenced(context.Background(), sessionData, 3, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+
+	assert.ErrorIs(t, err, ErrSequenceGap)
+	assert.Equal(t, uint64(1), *sessionData.LastSequence)
+}
+
+func TestHandleSequenced_PartiallyAppliedBatch_ShouldApplyRemainingCommands(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionData := newSequencedSessionData("account-1", 0)
+
+	_, err := handler.HandleSequenced(context.Background(), sessionData, 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+	require.NoError(t, err)
+
+	res, err := handler.HandleSequenced(context.Background(), sessionData, 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+		&corecommands.EndLevel{Success: false},
+		&corecommands.EndLevel{Success: false},
+	})
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 2, batchErr.Index)
+	assert.Equal(t, CommandRes{}, res)
+	assert.Equal(t, uint64(1), *sessionData.LastSequence)
+
+	res, err = handler.HandleSequenced(context.Background(), sessionData, 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+		&corecommands.EndLevel{Success: false},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, CommandRes{Sequence: 2, Duplicate: true}, res)
+	assert.Equal(t, uint64(2), *sessionData.LastSequence)
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 9, state.Energy.CurrentAmount)
+	require.Len(t, state.LevelProgression.Statistics, 1)
+	assert.Equal(t, 1, state.LevelProgression.Statistics[0].Losses)
+}
+
+func TestHandleSequenced_FailedCommand_ShouldNotAdvanceSequence(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionData := newSequencedSessionData("account-1", 0)
+
+	_, err := handler.HandleSequenced(context.Background(), sessionData, 1, []core.Command{&corecommands.EndLevel{}})
+
+	assert.ErrorIs(t, err, corecommands.ErrNoLevelInProgress)
+	assert.Equal(t, uint64(0), *sessionData.LastSequence)
+}
+
+func TestHandleSequenced_ZeroSequence_ShouldNotTrack(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionData := newSequencedSessionData("account-1", 3)
+
+	res, err := handler.HandleSequenced(context.Background(), sessionData, 0, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, CommandRes{}, res)
+	assert.Equal(t, uint64(3), *sessionData.LastSequence)
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/sessions"
+)
+
+var (
+	// ErrMissingSessionData is returned when the session has no data, which
+	// happens when it ended in between. The client has to log in again.
+	ErrMissingSessionData        = sessions.ErrSessionDataMissing
+	ErrFailedToGetSessionData    = errors.New("failed to get session data")
+	ErrFailedToUpdateSessionData = errors.New("failed to update session data")
+)
+
+// HandleStored runs HandleSequenced against the data stored for the session,
+// and stores it back once the commands succeeded. The persistent state carries
+// the last sequence of the session, so a batch whose session data failed to be
+// stored isn't applied again on retry. The returned CommandRes
+// carries the resulting session state. The account is locked all along, as
+// the sessions of an account share its persistent state.
+func (h *Handler) HandleStored(ctx context.Context, sessionsData sessions.Data, sessionID, accountID string, sequence uint64, commands []core.Command) (CommandRes, error) {
+	unlock, err := h.LockAccount(ctx, accountID)
+	if err != nil {
+		return CommandRes{}, err
+	}
+	defer unlock()
+
+	var storedData StoredSessionData
+	if err := sessionsData.GetSessionData(ctx, sessionID, &storedData); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return CommandRes{}, ctxErr
+		}
+		if errors.Is(err, ErrMissingSessionData) {
+			return CommandRes{}, ErrMissingSessionData
+		}
+		return CommandRes{}, errors.Wrap(err, ErrFailedToGetSessionData)
+	}
+
+	sessionData := SessionData{
+		AccountID:    accountID,
+		SessionState: &storedData.SessionState,
+		LastSequence: &storedData.LastSequence,
+		SessionID:    sessionID,
+	}
+
+	res, err := h.HandleSequenced(ctx, sessionData, sequence, commands)
+	if err != nil {
+		return CommandRes{}, err
+	}
+
+	if err := sessionsData.SetSessionData(ctx, sessionID, storedData); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return CommandRes{}, ctxErr
+		}
+		return CommandRes{}, ErrFailedToUpdateSessionData
+	}
+
+	res.Session = &storedData.SessionState
+	return res, nil
+}
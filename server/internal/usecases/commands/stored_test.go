@@ -0,0 +1,181 @@
+//go:build unit
+// +build unit
+
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/core"
+	corecommands "technical-test-backend/internal/core/commands"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/sessions/memory"
+	playersmemory "technical-test-backend/internal/usecases/players/dal/memory"
+
+	"technical-test-backend/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleStored_ShouldStoreSessionData(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionPool := memory.NewSessionPool(memory.SessionPoolConfig{TTL: time.Minute})
+	session, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	res, err := handler.HandleStored(context.Background(), sessionPool, session.ID, "account-1", 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), res.Sequence)
+	require.NotNil(t, res.Session.CurrentLevelID)
+	assert.Equal(t, 1, *res.Session.CurrentLevelID)
+
+	var storedData StoredSessionData
+	require.NoError(t, sessionPool.GetSessionData(context.Background(), session.ID, &storedData))
+	assert.Equal(t, uint64(1), storedData.LastSequence)
+	assert.Equal(t, *res.Session, storedData.SessionState)
+}
+
+func TestHandleStored_WithoutSession_ShouldReturnMissingSessionData(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionPool := memory.NewSessionPool(memory.SessionPoolConfig{TTL: time.Minute})
+
+	_, err := handler.HandleStored(context.Background(), sessionPool, "session-1", "account-1", 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+
+	assert.ErrorIs(t, err, ErrMissingSessionData)
+}
+
+func TestHandleStored_WhenAccountIsLocked_ShouldGiveUpOnceContextIsDone(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionPool := memory.NewSessionPool(memory.SessionPoolConfig{TTL: time.Minute})
+	session, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	unlock, err := handler.LockAccount(context.Background(), "account-1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = handler.HandleStored(ctx, sessionPool, session.ID, "account-1", 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+
+	res, err := handler.HandleStored(context.Background(), sessionPool, session.ID, "account-1", 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), res.Sequence)
+}
+
+func TestHandleStored_WithConcurrentSessions_ShouldKeepSessionDataPerSession(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionPool := memory.NewSessionPool(memory.SessionPoolConfig{
+		TTL:         time.Minute,
+		Policy:      sessions.PolicyConcurrent,
+		MaxSessions: 2,
+	})
+	first, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	second, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+
+	firstRes, err := handler.HandleStored(context.Background(), sessionPool, first.ID, "account-1", 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+	require.NoError(t, err)
+	secondRes, err := handler.HandleStored(context.Background(), sessionPool, second.ID, "account-1", 1, []core.Command{
+		&corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()},
+	})
+	require.NoError(t, err)
+
+	assert.False(t, firstRes.Duplicate)
+	assert.False(t, secondRes.Duplicate)
+	require.NotNil(t, firstRes.Session.CurrentLevelID)
+	assert.Equal(t, 1, *firstRes.Session.CurrentLevelID)
+	require.NotNil(t, secondRes.Session.CurrentLevelID)
+	assert.Equal(t, 1, *secondRes.Session.CurrentLevelID)
+
+	for _, session := range []sessions.Session{first, second} {
+		var storedData StoredSessionData
+		require.NoError(t, sessionPool.GetSessionData(context.Background(), session.ID, &storedData))
+		assert.Equal(t, uint64(1), storedData.LastSequence)
+		require.NotNil(t, storedData.CurrentLevelID)
+		assert.Equal(t, 1, *storedData.CurrentLevelID)
+	}
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 8, state.Energy.CurrentAmount)
+}
+
+// failingSessionsData fails the next SetSessionData calls.
+type failingSessionsData struct {
+	sessions.Data
+	failures int
+}
+
+func (d *failingSessionsData) SetSessionData(ctx context.Context, sessionID string, data interface{}) error {
+	if d.failures > 0 {
+		d.failures--
+		return errors.New("storage unavailable")
+	}
+	return d.Data.SetSessionData(ctx, sessionID, data)
+}
+
+func TestHandleStored_WhenSessionDataFailsToStore_ShouldNotReapplyOnRetry(t *testing.T) {
+	dal := playersmemory.NewDAL()
+	newTestAccount(t, dal, "account-1", 10)
+	handler := NewHandler(Config{MaxTimeDifferenceSeconds: 1}, dal, newTestProvider(t))
+	sessionPool := memory.NewSessionPool(memory.SessionPoolConfig{TTL: time.Minute})
+	session, err := sessionPool.CreateSession(context.Background(), "account-1", nil)
+	require.NoError(t, err)
+	sessionsData := &failingSessionsData{Data: sessionPool, failures: 1}
+	command := &corecommands.BeginLevel{LevelID: 1, Now: time.Now().UTC()}
+
+	_, err = handler.HandleStored(context.Background(), sessionsData, session.ID, "account-1", 1, []core.Command{command})
+	require.ErrorIs(t, err, ErrFailedToUpdateSessionData)
+
+	res, err := handler.HandleStored(context.Background(), sessionsData, session.ID, "account-1", 1, []core.Command{command})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), res.Sequence)
+	assert.True(t, res.Duplicate)
+	require.NotNil(t, res.Session.CurrentLevelID)
+	assert.Equal(t, 1, *res.Session.CurrentLevelID)
+	assert.NotNil(t, res.Session.LevelSeed)
+
+	state, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 9, state.Energy.CurrentAmount)
+
+	var storedData StoredSessionData
+	require.NoError(t, sessionPool.GetSessionData(context.Background(), session.ID, &storedData))
+	assert.Equal(t, uint64(1), storedData.LastSequence)
+	assert.Equal(t, *res.Session, storedData.SessionState)
+
+	res, err = handler.HandleStored(context.Background(), sessionsData, session.ID, "account-1", 2, []core.Command{
+		&corecommands.AbandonLevel{Now: time.Now().UTC()},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), res.Sequence)
+	assert.False(t, res.Duplicate)
+}
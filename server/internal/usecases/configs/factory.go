@@ -0,0 +1,62 @@
+package configs
+
+import (
+	"context"
+	"log"
+	"technical-test-backend/internal/lifecycle"
+	"technical-test-backend/internal/metrics"
+	"technical-test-backend/internal/worker"
+	"time"
+)
+
+// CreateProvider registers the loading of the configs on the lifecycle, which
+// fails to start without them, and their reloading every
+// config.ReloadInterval. The outcome of the reloads is reported on registry.
+func CreateProvider(lc *lifecycle.Lifecycle, config ProviderConfig, registry *metrics.Registry) *Provider {
+	provider := NewProvider(config)
+
+	reloads := registry.NewCounter("configs_reloads_total", "Reloads of the game configs file, by result.", "result")
+	lastReloadSuccess := registry.NewGauge("configs_last_reload_success", "Whether the last reload of the game configs file succeeded.")
+	lastReloadTime := registry.NewGauge("configs_last_reload_timestamp_seconds", "Time of the last reload of the game configs file.")
+	reload := func() error {
+		err := provider.Reload()
+
+		result, success := "success", 1.0
+		if err != nil {
+			result, success = "failure", 0
+		}
+		reloads.Inc(result)
+		lastReloadSuccess.Set(success)
+		lastReloadTime.Set(float64(time.Now().Unix()))
+
+		return err
+	}
+
+	reloadWorker := worker.New(worker.Config{
+		Interval: config.ReloadInterval,
+	}, func() {
+		if err := reload(); err != nil {
+			log.Printf("Failed to reload configs, keeping the previous version: %v", err)
+		}
+	})
+
+	lc.Append(lifecycle.Hook{
+		Name: "configs provider",
+		OnStart: func(ctx context.Context) error {
+			if err := reload(); err != nil {
+				return err
+			}
+
+			if config.ReloadInterval > 0 {
+				reloadWorker.Start()
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			reloadWorker.Stop()
+			return nil
+		},
+	})
+
+	return provider
+}
@@ -0,0 +1,40 @@
+//go:build unit
+// +build unit
+
+package configs
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"technical-test-backend/internal/lifecycle"
+	"technical-test-backend/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateProvider_ShouldLoadConfigsOnStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game_config.json")
+	writeConfigFile(t, path, validConfigJSON)
+	lc := lifecycle.New()
+
+	provider := CreateProvider(lc, ProviderConfig{FilePath: path}, metrics.NewRegistry())
+	require.NoError(t, lc.Start(context.Background()))
+	t.Cleanup(func() { lc.Stop(context.Background()) })
+
+	configs, err := provider.GetConfigs()
+	require.NoError(t, err)
+	assert.Equal(t, 10, configs.Energy.MaxEnergy)
+}
+
+func TestCreateProvider_WithInvalidConfigs_ShouldFailToStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game_config.json")
+	writeConfigFile(t, path, `{"energy": {"maxEnergy": 0}}`)
+	lc := lifecycle.New()
+
+	CreateProvider(lc, ProviderConfig{FilePath: path}, metrics.NewRegistry())
+
+	assert.Error(t, lc.Start(context.Background()))
+}
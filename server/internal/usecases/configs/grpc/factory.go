@@ -0,0 +1,7 @@
+package grpc
+
+import "technical-test-backend/internal/usecases/configs"
+
+func CreateGRPCHandler(configsProvider *configs.Provider) *Handler {
+	return NewHandler(configs.NewHandler(configsProvider))
+}
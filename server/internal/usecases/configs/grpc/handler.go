@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"context"
+	grpcutils "technical-test-backend/internal/grpc"
+	"technical-test-backend/internal/grpc/gamepb"
+	usecasesconfigs "technical-test-backend/internal/usecases/configs"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler serves the GetConfigs method of the InitializationHandler service,
+// along with the players Handler.
+type Handler struct {
+	configHandler *usecasesconfigs.Handler
+}
+
+func NewHandler(configHandler *usecasesconfigs.Handler) *Handler {
+	return &Handler{
+		configHandler: configHandler,
+	}
+}
+
+func (h *Handler) GetConfigs(ctx context.Context, req *gamepb.GetConfigsRequest) (*gamepb.GetConfigsResponse, error) {
+	res, err := h.configHandler.GetConfigs(&usecasesconfigs.GetConfigsArgs{})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &gamepb.GetConfigsResponse{
+		Configs: grpcutils.ConfigsToProto(res.Configs),
+		Version: res.Version,
+	}, nil
+}
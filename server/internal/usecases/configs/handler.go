@@ -10,6 +10,7 @@ type GetConfigsArgs struct{
 
 type GetConfigsRes struct {
 	Configs core.Configs `json:"configs"`
+	Version string       `json:"version"`
 }
 
 type Handler struct {
@@ -23,12 +24,13 @@ func NewHandler(configs *Provider) *Handler {
 }
 
 func (h *Handler) GetConfigs(args *GetConfigsArgs) (*GetConfigsRes, error) {
-	configs, err := h.configs.GetConfigs()
+	versioned, err := h.configs.GetVersionedConfigs()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configs: %v", err)
 	}
 
 	return &GetConfigsRes{
-		Configs: configs,
+		Configs: versioned.Configs,
+		Version: versioned.Version,
 	}, nil
 }
@@ -1,36 +1,158 @@
 package configs
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
+	"sync/atomic"
 	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+	"time"
+)
+
+var (
+	ErrInvalidConfigs = errors.New("invalid configs")
 )
 
 type ProviderConfig struct {
 	FilePath string
+	// ReloadInterval is how often the file is checked for changes. Zero
+	// disables reloading.
+	ReloadInterval time.Duration
 }
 
+// VersionedConfigs are parsed configs along with the hash of the file they
+// were read from, so clients can tell when their copy is stale.
+type VersionedConfigs struct {
+	Configs core.Configs
+	Version string
+}
+
+// Subscriber is called with the new configs once a reload swapped them in.
+// It's called from the reloading goroutine, so it must not block nor reload.
+type Subscriber func(versioned VersionedConfigs)
+
+// Provider caches the configs read from a file. Reload swaps in a new version
+// atomically, and keeps serving the last good one if the file is invalid.
 type Provider struct {
 	configPath string
+	current    atomic.Pointer[VersionedConfigs]
+	// reloadMutex serializes reloads, readers never wait on it.
+	reloadMutex sync.Mutex
+
+	subscribersMutex sync.RWMutex
+	subscribers      map[int]Subscriber
+	nextID           int
 }
 
 func NewProvider(config ProviderConfig) *Provider {
 	return &Provider{
-		configPath: config.FilePath,
+		configPath:  config.FilePath,
+		subscribers: make(map[int]Subscriber),
+	}
+}
+
+// Subscribe registers a subscriber to the configs updates, and returns a
+// function to unregister it. The first load isn't an update.
+func (p *Provider) Subscribe(subscriber Subscriber) func() {
+	p.subscribersMutex.Lock()
+	defer p.subscribersMutex.Unlock()
+
+	id := p.nextID
+	p.nextID++
+	p.subscribers[id] = subscriber
+
+	return func() {
+		p.subscribersMutex.Lock()
+		defer p.subscribersMutex.Unlock()
+		delete(p.subscribers, id)
 	}
 }
 
 func (p *Provider) GetConfigs() (core.Configs, error) {
+	versioned, err := p.GetVersionedConfigs()
+	if err != nil {
+		return core.Configs{}, err
+	}
+
+	return versioned.Configs, nil
+}
+
+// GetVersionedConfigs returns the cached configs, loading them on first use.
+func (p *Provider) GetVersionedConfigs() (VersionedConfigs, error) {
+	if current := p.current.Load(); current != nil {
+		return *current, nil
+	}
+
+	if err := p.Reload(); err != nil {
+		return VersionedConfigs{}, err
+	}
+
+	return *p.current.Load(), nil
+}
+
+// Reload reads the file and swaps in its configs if its content changed. An
+// invalid file is rejected, leaving the current configs in place.
+func (p *Provider) Reload() error {
+	p.reloadMutex.Lock()
+	defer p.reloadMutex.Unlock()
+
 	data, err := os.ReadFile(p.configPath)
 	if err != nil {
-		return core.Configs{}, fmt.Errorf("failed to read config file %s: %w", p.configPath, err)
+		return fmt.Errorf("failed to read config file %s: %w", p.configPath, err)
+	}
+
+	hash := sha256.Sum256(data)
+	version := hex.EncodeToString(hash[:])
+	current := p.current.Load()
+	if current != nil && current.Version == version {
+		return nil
+	}
+
+	configs, err := ParseConfigs(data)
+	if err != nil {
+		return err
+	}
+
+	updated := VersionedConfigs{
+		Configs: configs,
+		Version: version,
 	}
+	p.current.Store(&updated)
+
+	if current != nil {
+		p.publish(updated)
+	}
+	return nil
+}
+
+func (p *Provider) publish(versioned VersionedConfigs) {
+	p.subscribersMutex.RLock()
+	defer p.subscribersMutex.RUnlock()
+
+	for _, subscriber := range p.subscribers {
+		subscriber(versioned)
+	}
+}
+
+// ParseConfigs decodes and validates a config file. Unknown fields are
+// rejected, as they're most likely typos.
+func ParseConfigs(data []byte) (core.Configs, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
 
 	configs := core.Configs{}
-	if err := json.Unmarshal(data, &configs); err != nil {
+	if err := decoder.Decode(&configs); err != nil {
 		return core.Configs{}, fmt.Errorf("failed to parse config JSON: %w", err)
 	}
 
+	if err := configs.Validate(); err != nil {
+		return core.Configs{}, errors.Wrap(ErrInvalidConfigs, err)
+	}
+
 	return configs, nil
 }
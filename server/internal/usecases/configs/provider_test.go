@@ -0,0 +1,157 @@
+//go:build unit
+// +build unit
+
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validConfigJSON = `{
+	"energy": {"maxEnergy": 10, "rechargeIntervalSeconds": 60},
+	"levels": [{}, {"energyCost": 1, "maxRolls": 10, "targetNumber": 1, "energyReward": 2}],
+	"onboarding": {"variants": [{"name": "default", "weight": 1, "energy": 5, "currentLevel": 1}]}
+}`
+
+func writeConfigFile(t *testing.T, path, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func newTestProvider(t *testing.T) (*Provider, string) {
+	path := filepath.Join(t.TempDir(), "game_config.json")
+	writeConfigFile(t, path, validConfigJSON)
+	return NewProvider(ProviderConfig{FilePath: path}), path
+}
+
+func TestGetConfigs_ShouldCacheConfigs(t *testing.T) {
+	provider, path := newTestProvider(t)
+
+	configs, err := provider.GetConfigs()
+	require.NoError(t, err)
+	assert.Equal(t, 10, configs.Energy.MaxEnergy)
+
+	require.NoError(t, os.Remove(path))
+
+	configs, err = provider.GetConfigs()
+	require.NoError(t, err)
+	assert.Equal(t, 10, configs.Energy.MaxEnergy)
+}
+
+func TestGetConfigs_MissingFile_ShouldFail(t *testing.T) {
+	provider := NewProvider(ProviderConfig{FilePath: filepath.Join(t.TempDir(), "missing.json")})
+
+	_, err := provider.GetConfigs()
+
+	assert.Error(t, err)
+}
+
+func TestReload(t *testing.T) {
+	provider, path := newTestProvider(t)
+	initial, err := provider.GetVersionedConfigs()
+	require.NoError(t, err)
+	require.NotEmpty(t, initial.Version)
+
+	t.Run("unchanged file", func(t *testing.T) {
+		require.NoError(t, provider.Reload())
+
+		current, err := provider.GetVersionedConfigs()
+		require.NoError(t, err)
+		assert.Equal(t, initial, current)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		writeConfigFile(t, path, `{"energy": {"maxEnergy": 10, "rechargeIntervalSeconds": 60}, "levels": [{}]}`)
+
+		err := provider.Reload()
+
+		assert.ErrorIs(t, err, ErrInvalidConfigs)
+		current, err := provider.GetVersionedConfigs()
+		require.NoError(t, err)
+		assert.Equal(t, initial, current)
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		writeConfigFile(t, path, `{"energy": `)
+
+		err := provider.Reload()
+
+		assert.Error(t, err)
+		current, err := provider.GetVersionedConfigs()
+		require.NoError(t, err)
+		assert.Equal(t, initial, current)
+	})
+
+	t.Run("updated file", func(t *testing.T) {
+		writeConfigFile(t, path, `{
+			"energy": {"maxEnergy": 20, "rechargeIntervalSeconds": 60},
+			"levels": [{}, {"energyCost": 1, "maxRolls": 10, "targetNumber": 1, "energyReward": 2}],
+			"onboarding": {"variants": [{"name": "default", "weight": 1, "energy": 5, "currentLevel": 1}]}
+		}`)
+
+		require.NoError(t, provider.Reload())
+
+		current, err := provider.GetVersionedConfigs()
+		require.NoError(t, err)
+		assert.Equal(t, 20, current.Configs.Energy.MaxEnergy)
+		assert.NotEqual(t, initial.Version, current.Version)
+	})
+}
+
+func TestSubscribe_ShouldOnlyReceiveUpdates(t *testing.T) {
+	provider, path := newTestProvider(t)
+
+	var updates []VersionedConfigs
+	unsubscribe := provider.Subscribe(func(versioned VersionedConfigs) {
+		updates = append(updates, versioned)
+	})
+
+	require.NoError(t, provider.Reload())
+	require.NoError(t, provider.Reload())
+	assert.Empty(t, updates)
+
+	writeConfigFile(t, path, `{"energy": `)
+	assert.Error(t, provider.Reload())
+	assert.Empty(t, updates)
+
+	writeConfigFile(t, path, strings.Replace(validConfigJSON, `"maxEnergy": 10`, `"maxEnergy": 20`, 1))
+	require.NoError(t, provider.Reload())
+
+	require.Len(t, updates, 1)
+	assert.Equal(t, 20, updates[0].Configs.Energy.MaxEnergy)
+
+	unsubscribe()
+	writeConfigFile(t, path, validConfigJSON)
+	require.NoError(t, provider.Reload())
+	assert.Len(t, updates, 1)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown field", `{"energy": {"maxEnergy": 10, "rechargeIntervalSeconds": 60, "max": 1}, "levels": [{}, {"maxRolls": 1, "targetNumber": 1}]}`},
+		{"non-positive max energy", `{"energy": {"maxEnergy": 0, "rechargeIntervalSeconds": 60}, "levels": [{}, {"maxRolls": 1, "targetNumber": 1}]}`},
+		{"non-positive recharge interval", `{"energy": {"maxEnergy": 10}, "levels": [{}, {"maxRolls": 1, "targetNumber": 1}]}`},
+		{"non-positive max rolls", `{"energy": {"maxEnergy": 10, "rechargeIntervalSeconds": 60}, "levels": [{}, {"targetNumber": 1}]}`},
+		{"unreachable target number", `{"energy": {"maxEnergy": 10, "rechargeIntervalSeconds": 60}, "levels": [{}, {"maxRolls": 1, "targetNumber": 7}]}`},
+		{"negative energy cost", `{"energy": {"maxEnergy": 10, "rechargeIntervalSeconds": 60}, "levels": [{}, {"energyCost": -1, "maxRolls": 1, "targetNumber": 1}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, path := newTestProvider(t)
+			writeConfigFile(t, path, tt.content)
+
+			_, err := provider.GetConfigs()
+
+			assert.Error(t, err)
+		})
+	}
+}
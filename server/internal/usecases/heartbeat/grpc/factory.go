@@ -0,0 +1,10 @@
+package grpc
+
+import (
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/configs"
+)
+
+func CreateGRPCHandler(sessionPool sessions.Pool, configsProvider *configs.Provider) *Handler {
+	return NewHandler(sessionPool, configsProvider)
+}
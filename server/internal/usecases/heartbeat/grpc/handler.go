@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+	grpcutils "technical-test-backend/internal/grpc"
+	"technical-test-backend/internal/grpc/gamepb"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/configs"
+)
+
+type Handler struct {
+	gamepb.UnimplementedHeartbeatHandlerServer
+
+	sessionPool     sessions.Pool
+	configsProvider *configs.Provider
+}
+
+func NewHandler(sessionPool sessions.Pool, configsProvider *configs.Provider) *Handler {
+	return &Handler{
+		sessionPool:     sessionPool,
+		configsProvider: configsProvider,
+	}
+}
+
+func (h *Handler) Heartbeat(ctx context.Context, req *gamepb.HeartbeatRequest) (*gamepb.HeartbeatResponse, error) {
+	if err := h.sessionPool.UpdateActivity(ctx, grpcutils.SessionID(ctx)); err != nil {
+		return nil, grpcutils.MapError(err, nil)
+	}
+
+	res := &gamepb.HeartbeatResponse{}
+	if versioned, err := h.configsProvider.GetVersionedConfigs(); err == nil {
+		res.ConfigsVersion = versioned.Version
+	}
+
+	return res, nil
+}
@@ -1,7 +1,10 @@
 package http
 
-import "technical-test-backend/internal/sessions"
+import (
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/configs"
+)
 
-func CreateHTTPHandler(sessionPool sessions.Pool) *Handler {
-	return NewHandler(sessionPool)
+func CreateHTTPHandler(sessionPool sessions.Pool, configsProvider *configs.Provider) *Handler {
+	return NewHandler(sessionPool, configsProvider)
 }
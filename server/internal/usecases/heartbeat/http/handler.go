@@ -4,25 +4,39 @@
 	"net/http"
 	httputils "technical-test-backend/internal/http"
 	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/configs"
 )
 
+// HeartbeatRes carries the current configs version, so clients can refresh
+// their configs once it changes.
+type HeartbeatRes struct {
+	ConfigsVersion string `json:"configsVersion,omitempty"`
+}
+
 type Handler struct {
-	sessionPool sessions.Pool
+	sessionPool     sessions.Pool
+	configsProvider *configs.Provider
 }
 
-func NewHandler(sessionPool sessions.Pool) *Handler {
+func NewHandler(sessionPool sessions.Pool, configsProvider *configs.Provider) *Handler {
 	return &Handler{
-		sessionPool: sessionPool,
+		sessionPool:     sessionPool,
+		configsProvider: configsProvider,
 	}
 }
 
 func (h *Handler) HandleHeartbeat(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.Header.Get("X-Session-ID")
 
-	if err := h.sessionPool.UpdateActivity(sessionID); err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+	if err := h.sessionPool.UpdateActivity(r.Context(), sessionID); err != nil {
+		httputils.WriteMappedError(w, err, nil)
 		return
 	}
 
-	httputils.WriteJSON(w, http.StatusOK, map[string]interface{}{})
+	var res HeartbeatRes
+	if versioned, err := h.configsProvider.GetVersionedConfigs(); err == nil {
+		res.ConfigsVersion = versioned.Version
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
 }
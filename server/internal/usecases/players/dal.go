@@ -1,13 +1,39 @@
 package players
 
-import "technical-test-backend/internal/core"
+import (
+	"context"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+)
 
+var (
+	ErrStateConflict        = errors.New("persistent state revision conflict")
+	ErrAccountNotFound      = errors.New("account not found")
+	ErrAccountAlreadyExists = errors.New("account already exists")
+)
+
+// AccountDAL and StateDAL implementations return ErrAccountNotFound for
+// unknown accounts, possibly wrapped, and ctx.Err() once ctx is done.
 type AccountDAL interface {
-	CreateAccount(account Account, state core.PersistentState) error
-	GetAccessToken(accountID string) (string, error)
+	CreateAccount(ctx context.Context, account Account, state core.PersistentState) error
+	GetSecretHash(ctx context.Context, accountID string) (string, error)
 }
 
 type StateDAL interface {
-	GetPersistentState(accountID string) (core.PersistentState, error)
-	SetPersistentState(accountID string, state core.PersistentState) error
+	GetPersistentState(ctx context.Context, accountID string) (core.PersistentState, error)
+	// SetPersistentState is a compare-and-set: it only stores the state if
+	// state.Revision matches the stored revision, returning ErrStateConflict
+	// otherwise. On success the stored revision becomes state.Revision + 1.
+	SetPersistentState(ctx context.Context, accountID string, state core.PersistentState) error
+	// SetPersistentStateWithReceipt is SetPersistentState storing the receipt
+	// in the same write, replacing the previous receipt of its session. Only
+	// the receipts of the last MaxReceipts sessions are kept.
+	SetPersistentStateWithReceipt(ctx context.Context, accountID string, state core.PersistentState, receipt Receipt) error
+	// GetReceipt returns the last receipt of the session, if kept.
+	GetReceipt(ctx context.Context, accountID, sessionID string) (Receipt, bool, error)
+}
+
+type DAL interface {
+	AccountDAL
+	StateDAL
 }
@@ -0,0 +1,408 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/usecases/players"
+)
+
+var (
+	ErrFailedToOpenFile     = errors.New("failed to open data file")
+	ErrFailedToReplayLog    = errors.New("failed to replay data file")
+	ErrFailedToWriteRecord  = errors.New("failed to write record")
+	ErrFailedToCompactLog   = errors.New("failed to compact data file")
+	ErrDALClosed            = errors.New("dal is closed")
+	ErrAccountAlreadyExists = players.ErrAccountAlreadyExists
+	ErrAccountNotFound      = players.ErrAccountNotFound
+)
+
+const (
+	opCreateAccount = "createAccount"
+	opSetState      = "setState"
+)
+
+type DALConfig struct {
+	FilePath string
+	// CompactionRatio triggers a compaction on open when the log holds more
+	// than CompactionRatio records per account. Zero disables compaction.
+	CompactionRatio int
+}
+
+// DAL is an append-only, fsync'd log of account changes. Every write is
+// appended as a JSON line and synced before returning, and the full state is
+// rebuilt in memory by replaying the log on open.
+type DAL struct {
+	accounts map[string]AccountData
+	file     *os.File
+	config   DALConfig
+	mutex    sync.RWMutex
+}
+
+type AccountData struct {
+	Account         players.Account      `json:"account"`
+	PersistentState core.PersistentState `json:"persistentState"`
+	Receipts        []players.Receipt    `json:"receipts,omitempty"`
+}
+
+// record is a line of the log. Receipts are the receipts of an account in a
+// snapshot written by a compaction, or the one written with a state update.
+type record struct {
+	Op        string                `json:"op"`
+	AccountID string                `json:"accountId"`
+	Account   *players.Account      `json:"account,omitempty"`
+	State     *core.PersistentState `json:"state,omitempty"`
+	Receipts  []players.Receipt     `json:"receipts,omitempty"`
+}
+
+func NewDAL(config DALConfig) (*DAL, error) {
+	if err := os.MkdirAll(filepath.Dir(config.FilePath), 0o755); err != nil {
+		return nil, errors.Wrap(err, ErrFailedToOpenFile)
+	}
+
+	file, err := os.OpenFile(config.FilePath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrFailedToOpenFile)
+	}
+
+	d := &DAL{
+		accounts: make(map[string]AccountData),
+		file:     file,
+		config:   config,
+	}
+
+	recordCount, err := d.replay()
+	if err != nil {
+		file.Close()
+		return nil, errors.Wrap(err, ErrFailedToReplayLog)
+	}
+
+	if config.CompactionRatio > 0 && recordCount > config.CompactionRatio*max(len(d.accounts), 1) {
+		if err := d.compact(); err != nil {
+			d.file.Close()
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+func (d *DAL) CreateAccount(ctx context.Context, account players.Account, state core.PersistentState) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.file == nil {
+		return ErrDALClosed
+	}
+
+	if _, exists := d.accounts[account.ID]; exists {
+		return ErrAccountAlreadyExists
+	}
+
+	err := d.append(record{
+		Op:        opCreateAccount,
+		AccountID: account.ID,
+		Account:   &account,
+		State:     &state,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.accounts[account.ID] = AccountData{
+		Account:         account,
+		PersistentState: state,
+	}
+	return nil
+}
+
+func (d *DAL) GetSecretHash(ctx context.Context, accountID string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	accountData, exists := d.accounts[accountID]
+	if !exists {
+		return "", ErrAccountNotFound
+	}
+
+	return accountData.Account.SecretHash, nil
+}
+
+func (d *DAL) GetPersistentState(ctx context.Context, accountID string) (core.PersistentState, error) {
+	if err := ctx.Err(); err != nil {
+		return core.PersistentState{}, err
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	accountData, exists := d.accounts[accountID]
+	if !exists {
+		return core.PersistentState{}, ErrAccountNotFound
+	}
+
+	return accountData.PersistentState, nil
+}
+
+func (d *DAL) SetPersistentState(ctx context.Context, accountID string, state core.PersistentState) error {
+	return d.setPersistentState(ctx, accountID, state, nil)
+}
+
+func (d *DAL) SetPersistentStateWithReceipt(ctx context.Context, accountID string, state core.PersistentState, receipt players.Receipt) error {
+	return d.setPersistentState(ctx, accountID, state, []players.Receipt{receipt})
+}
+
+func (d *DAL) GetReceipt(ctx context.Context, accountID, sessionID string) (players.Receipt, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return players.Receipt{}, false, err
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	accountData, exists := d.accounts[accountID]
+	if !exists {
+		return players.Receipt{}, false, ErrAccountNotFound
+	}
+
+	receipt, found := players.FindReceipt(accountData.Receipts, sessionID)
+	return receipt, found, nil
+}
+
+// setPersistentState writes the state and the receipts in a single record.
+func (d *DAL) setPersistentState(ctx context.Context, accountID string, state core.PersistentState, receipts []players.Receipt) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.file == nil {
+		return ErrDALClosed
+	}
+
+	accountData, exists := d.accounts[accountID]
+	if !exists {
+		return ErrAccountNotFound
+	}
+
+	if accountData.PersistentState.Revision != state.Revision {
+		return players.ErrStateConflict
+	}
+
+	state.Revision++
+	err := d.append(record{
+		Op:        opSetState,
+		AccountID: accountID,
+		State:     &state,
+		Receipts:  receipts,
+	})
+	if err != nil {
+		return err
+	}
+
+	accountData.PersistentState = state
+	for _, receipt := range receipts {
+		accountData.Receipts = players.AppendReceipt(accountData.Receipts, receipt)
+	}
+	d.accounts[accountID] = accountData
+	return nil
+}
+
+func (d *DAL) GetAccountCount() (int, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return len(d.accounts), nil
+}
+
+// Compact rewrites the log as a single record per account.
+func (d *DAL) Compact() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.file == nil {
+		return ErrDALClosed
+	}
+
+	return d.compact()
+}
+
+func (d *DAL) Close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.file == nil {
+		return nil
+	}
+
+	err := d.file.Close()
+	d.file = nil
+	return err
+}
+
+func (d *DAL) append(r record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToWriteRecord)
+	}
+
+	if _, err := d.file.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, ErrFailedToWriteRecord)
+	}
+
+	if err := d.file.Sync(); err != nil {
+		return errors.Wrap(err, ErrFailedToWriteRecord)
+	}
+
+	return nil
+}
+
+// replay rebuilds the in-memory state from the log. A trailing partial line,
+// left behind by a crash in the middle of a write, is truncated away.
+func (d *DAL) replay() (int, error) {
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	reader := bufio.NewReader(d.file)
+	var validOffset int64
+	recordCount := 0
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		var r record
+		if err := json.Unmarshal(bytes.TrimSpace(line), &r); err != nil {
+			return 0, fmt.Errorf("corrupted record at offset %d: %w", validOffset, err)
+		}
+
+		if err := d.apply(r); err != nil {
+			return 0, fmt.Errorf("invalid record at offset %d: %w", validOffset, err)
+		}
+
+		validOffset += int64(len(line))
+		recordCount++
+	}
+
+	if err := d.file.Truncate(validOffset); err != nil {
+		return 0, err
+	}
+
+	if _, err := d.file.Seek(validOffset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return recordCount, nil
+}
+
+func (d *DAL) apply(r record) error {
+	switch r.Op {
+	case opCreateAccount:
+		if r.Account == nil || r.State == nil {
+			return fmt.Errorf("missing account data")
+		}
+		d.accounts[r.AccountID] = AccountData{
+			Account:         *r.Account,
+			PersistentState: *r.State,
+			Receipts:        r.Receipts,
+		}
+	case opSetState:
+		accountData, exists := d.accounts[r.AccountID]
+		if !exists || r.State == nil {
+			return fmt.Errorf("state update for unknown account %s", r.AccountID)
+		}
+		accountData.PersistentState = *r.State
+		for _, receipt := range r.Receipts {
+			accountData.Receipts = players.AppendReceipt(accountData.Receipts, receipt)
+		}
+		d.accounts[r.AccountID] = accountData
+	default:
+		return fmt.Errorf("unknown operation %q", r.Op)
+	}
+
+	return nil
+}
+
+// compact writes a snapshot to a temporary file, syncs it and atomically
+// renames it over the current log.
+func (d *DAL) compact() error {
+	tmpPath := d.config.FilePath + ".tmp"
+	tmpFile, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToCompactLog)
+	}
+
+	writer := bufio.NewWriter(tmpFile)
+	for accountID, accountData := range d.accounts {
+		line, err := json.Marshal(record{
+			Op:        opCreateAccount,
+			AccountID: accountID,
+			Account:   &accountData.Account,
+			State:     &accountData.PersistentState,
+			Receipts:  accountData.Receipts,
+		})
+		if err != nil {
+			tmpFile.Close()
+			return errors.Wrap(err, ErrFailedToCompactLog)
+		}
+		writer.Write(append(line, '\n'))
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, ErrFailedToCompactLog)
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, ErrFailedToCompactLog)
+	}
+
+	if err := os.Rename(tmpPath, d.config.FilePath); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, ErrFailedToCompactLog)
+	}
+
+	if err := syncDir(filepath.Dir(d.config.FilePath)); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, ErrFailedToCompactLog)
+	}
+
+	d.file.Close()
+	d.file = tmpFile
+	return nil
+}
+
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
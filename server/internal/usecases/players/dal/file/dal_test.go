@@ -0,0 +1,264 @@
+//go:build unit
+// +build unit
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/usecases/players"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(t *testing.T) DALConfig {
+	return DALConfig{
+		FilePath: filepath.Join(t.TempDir(), "players.log"),
+	}
+}
+
+func newTestState(energy int) core.PersistentState {
+	return core.PersistentState{
+		Energy: core.Energy{
+			CurrentAmount:  energy,
+			LastRechargeAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		LevelProgression: core.LevelProgression{
+			CurrentLevel: 1,
+			Statistics:   []core.LevelStats{},
+		},
+	}
+}
+
+func TestCreateAccount(t *testing.T) {
+	dal, err := NewDAL(newTestConfig(t))
+	require.NoError(t, err)
+	defer dal.Close()
+
+	t.Run("successful creation", func(t *testing.T) {
+		account := players.Account{ID: "account-1", SecretHash: "hash-1"}
+
+		err := dal.CreateAccount(context.Background(), account, newTestState(5))
+		require.NoError(t, err)
+
+		secretHash, err := dal.GetSecretHash(context.Background(), account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, account.SecretHash, secretHash)
+	})
+
+	t.Run("duplicate account", func(t *testing.T) {
+		account := players.Account{ID: "account-2", SecretHash: "hash-2"}
+		require.NoError(t, dal.CreateAccount(context.Background(), account, newTestState(5)))
+
+		err := dal.CreateAccount(context.Background(), account, newTestState(5))
+
+		assert.ErrorIs(t, err, ErrAccountAlreadyExists)
+	})
+}
+
+func TestGetPersistentState_NonExistentAccount(t *testing.T) {
+	dal, err := NewDAL(newTestConfig(t))
+	require.NoError(t, err)
+	defer dal.Close()
+
+	_, err = dal.GetPersistentState(context.Background(), "non-existent-account")
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	err = dal.SetPersistentState(context.Background(), "non-existent-account", newTestState(1))
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestReopen_ShouldRestoreState(t *testing.T) {
+	config := newTestConfig(t)
+
+	dal, err := NewDAL(config)
+	require.NoError(t, err)
+
+	account := players.Account{ID: "account-1", SecretHash: "hash-1"}
+	require.NoError(t, dal.CreateAccount(context.Background(), account, newTestState(5)))
+
+	expectedState := newTestState(3)
+	expectedState.LevelProgression.Statistics = append(expectedState.LevelProgression.Statistics, core.LevelStats{
+		LevelID:   1,
+		BestScore: 4,
+		Wins:      1,
+	})
+	require.NoError(t, dal.SetPersistentState(context.Background(), account.ID, expectedState))
+	require.NoError(t, dal.Close())
+	expectedState.Revision++
+
+	reopened, err := NewDAL(config)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	state, err := reopened.GetPersistentState(context.Background(), account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, expectedState, state)
+	assert.Equal(t, 1, accountCount(t, reopened))
+}
+
+func TestReopen_WithPartialTrailingRecord_ShouldTruncate(t *testing.T) {
+	config := newTestConfig(t)
+
+	dal, err := NewDAL(config)
+	require.NoError(t, err)
+	require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: "account-1", SecretHash: "hash-1"}, newTestState(5)))
+	require.NoError(t, dal.Close())
+
+	f, err := os.OpenFile(config.FilePath, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"op":"setState","accountId":"account-1","sta`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := NewDAL(config)
+	require.NoError(t, err)
+
+	state, err := reopened.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 5, state.Energy.CurrentAmount)
+
+	require.NoError(t, reopened.SetPersistentState(context.Background(), "account-1", newTestState(2)))
+	require.NoError(t, reopened.Close())
+
+	reopened, err = NewDAL(config)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	state, err = reopened.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, state.Energy.CurrentAmount)
+}
+
+func TestReopen_WithCorruptedRecord_ShouldFail(t *testing.T) {
+	config := newTestConfig(t)
+	require.NoError(t, os.WriteFile(config.FilePath, []byte("not json\n"), 0o600))
+
+	_, err := NewDAL(config)
+
+	assert.ErrorIs(t, err, ErrFailedToReplayLog)
+}
+
+func TestCompact_ShouldKeepLatestState(t *testing.T) {
+	config := newTestConfig(t)
+	config.CompactionRatio = 2
+
+	dal, err := NewDAL(config)
+	require.NoError(t, err)
+	require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: "account-1", SecretHash: "hash-1"}, newTestState(5)))
+	for i := range 10 {
+		state := newTestState(i)
+		state.Revision = int64(i)
+		require.NoError(t, dal.SetPersistentState(context.Background(), "account-1", state))
+	}
+	require.NoError(t, dal.Close())
+
+	sizeBefore, err := os.Stat(config.FilePath)
+	require.NoError(t, err)
+
+	reopened, err := NewDAL(config)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	sizeAfter, err := os.Stat(config.FilePath)
+	require.NoError(t, err)
+	assert.Less(t, sizeAfter.Size(), sizeBefore.Size())
+
+	state, err := reopened.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 9, state.Energy.CurrentAmount)
+
+	state.Energy.CurrentAmount = 1
+	require.NoError(t, reopened.SetPersistentState(context.Background(), "account-1", state))
+	state, err = reopened.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, state.Energy.CurrentAmount)
+}
+
+func TestClose_ShouldRejectWrites(t *testing.T) {
+	dal, err := NewDAL(newTestConfig(t))
+	require.NoError(t, err)
+	require.NoError(t, dal.Close())
+
+	err = dal.CreateAccount(context.Background(), players.Account{ID: "account-1", SecretHash: "hash-1"}, newTestState(5))
+
+	assert.ErrorIs(t, err, ErrDALClosed)
+}
+
+func TestSetPersistentState_StaleRevision_ShouldConflict(t *testing.T) {
+	config := newTestConfig(t)
+
+	dal, err := NewDAL(config)
+	require.NoError(t, err)
+	require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: "account-1", SecretHash: "hash-1"}, newTestState(5)))
+
+	stale, err := dal.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	require.NoError(t, dal.SetPersistentState(context.Background(), "account-1", newTestState(4)))
+
+	err = dal.SetPersistentState(context.Background(), "account-1", stale)
+	assert.ErrorIs(t, err, players.ErrStateConflict)
+	require.NoError(t, dal.Close())
+
+	reopened, err := NewDAL(config)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	state, err := reopened.GetPersistentState(context.Background(), "account-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), state.Revision)
+	assert.Equal(t, 4, state.Energy.CurrentAmount)
+}
+
+func TestSetPersistentStateWithReceipt_ShouldKeepNewestReceipts(t *testing.T) {
+	config := newTestConfig(t)
+	// Replays the receipts from a compacted log.
+	config.CompactionRatio = 2
+
+	dal, err := NewDAL(config)
+	require.NoError(t, err)
+	require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: "account-1", SecretHash: "hash-1"}, newTestState(5)))
+	levelID := 1
+	for i := range players.MaxReceipts + 1 {
+		state := newTestState(i)
+		state.Revision = int64(i)
+		receipt := players.Receipt{
+			SessionID:    fmt.Sprintf("session-%d", i),
+			Sequence:     uint64(i + 1),
+			SessionState: core.SessionState{CurrentLevelID: &levelID},
+		}
+		require.NoError(t, dal.SetPersistentStateWithReceipt(context.Background(), "account-1", state, receipt))
+	}
+	require.NoError(t, dal.Close())
+
+	reopened, err := NewDAL(config)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	_, found, err := reopened.GetReceipt(context.Background(), "account-1", "session-0")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	receipt, found, err := reopened.GetReceipt(context.Background(), "account-1", fmt.Sprintf("session-%d", players.MaxReceipts))
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, uint64(players.MaxReceipts+1), receipt.Sequence)
+	assert.Equal(t, core.SessionState{CurrentLevelID: &levelID}, receipt.SessionState)
+
+	_, _, err = reopened.GetReceipt(context.Background(), "account-2", "session-0")
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func accountCount(t *testing.T, dal *DAL) int {
+	t.Helper()
+	count, err := dal.GetAccountCount()
+	require.NoError(t, err)
+	return count
+}
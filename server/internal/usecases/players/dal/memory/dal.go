@@ -1,7 +1,7 @@
 package memory
 
 import (
-	"fmt"
+	"context"
 	"sync"
 	"technical-test-backend/internal/core"
 	"technical-test-backend/internal/usecases/players"
@@ -15,6 +15,7 @@ type DAL struct {
 type AccountData struct {
 	Account         players.Account      `json:"account"`
 	PersistentState core.PersistentState `json:"persistentState"`
+	Receipts        []players.Receipt    `json:"receipts,omitempty"`
 }
 
 func NewDAL() *DAL {
@@ -23,12 +24,16 @@ func NewDAL() *DAL {
 	}
 }
 
-func (d *DAL) CreateAccount(account players.Account, state core.PersistentState) error {
+func (d *DAL) CreateAccount(ctx context.Context, account players.Account, state core.PersistentState) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
 	if _, exists := d.accounts[account.ID]; exists {
-		return fmt.Errorf("account already exists")
+		return players.ErrAccountAlreadyExists
 	}
 
 	accountData := AccountData{
@@ -40,47 +45,92 @@ func (d *DAL) CreateAccount(account players.Account, state core.PersistentState)
 	return nil
 }
 
-func (d *DAL) GetAccessToken(accountID string) (string, error) {
+func (d *DAL) GetSecretHash(ctx context.Context, accountID string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	d.mutex.RLock()
 	defer d.mutex.RUnlock()
 
 	accountData, exists := d.accounts[accountID]
 	if !exists {
-		return "", fmt.Errorf("account not found")
+		return "", players.ErrAccountNotFound
 	}
 
-	return accountData.Account.AccessToken, nil
+	return accountData.Account.SecretHash, nil
 }
 
-func (d *DAL) GetPersistentState(accountID string) (core.PersistentState, error) {
+func (d *DAL) GetPersistentState(ctx context.Context, accountID string) (core.PersistentState, error) {
+	if err := ctx.Err(); err != nil {
+		return core.PersistentState{}, err
+	}
+
 	d.mutex.RLock()
 	defer d.mutex.RUnlock()
 
 	accountData, exists := d.accounts[accountID]
 	if !exists {
-		return core.PersistentState{}, fmt.Errorf("account not found")
+		return core.PersistentState{}, players.ErrAccountNotFound
 	}
 
 	return accountData.PersistentState, nil
 }
 
-func (d *DAL) SetPersistentState(accountID string, state core.PersistentState) error {
+func (d *DAL) SetPersistentState(ctx context.Context, accountID string, state core.PersistentState) error {
+	return d.setPersistentState(ctx, accountID, state, nil)
+}
+
+func (d *DAL) SetPersistentStateWithReceipt(ctx context.Context, accountID string, state core.PersistentState, receipt players.Receipt) error {
+	return d.setPersistentState(ctx, accountID, state, &receipt)
+}
+
+func (d *DAL) GetReceipt(ctx context.Context, accountID, sessionID string) (players.Receipt, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return players.Receipt{}, false, err
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	accountData, exists := d.accounts[accountID]
+	if !exists {
+		return players.Receipt{}, false, players.ErrAccountNotFound
+	}
+
+	receipt, found := players.FindReceipt(accountData.Receipts, sessionID)
+	return receipt, found, nil
+}
+
+func (d *DAL) setPersistentState(ctx context.Context, accountID string, state core.PersistentState, receipt *players.Receipt) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
 	accountData, exists := d.accounts[accountID]
 	if !exists {
-		return fmt.Errorf("account not found")
+		return players.ErrAccountNotFound
 	}
 
+	if accountData.PersistentState.Revision != state.Revision {
+		return players.ErrStateConflict
+	}
+
+	state.Revision++
 	accountData.PersistentState = state
+	if receipt != nil {
+		accountData.Receipts = players.AppendReceipt(accountData.Receipts, *receipt)
+	}
 	d.accounts[accountID] = accountData
 	return nil
 }
 
-func (d *DAL) GetAccountCount() int {
+func (d *DAL) GetAccountCount() (int, error) {
 	d.mutex.RLock()
 	defer d.mutex.RUnlock()
 
-	return len(d.accounts)
+	return len(d.accounts), nil
 }
@@ -0,0 +1,181 @@
+//go:build unit
+// +build unit
+
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/usecases/players"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestState(energy int) core.PersistentState {
+	return core.PersistentState{
+		Energy: core.Energy{
+			CurrentAmount:  energy,
+			LastRechargeAt: time.Now(),
+		},
+		LevelProgression: core.LevelProgression{
+			CurrentLevel: 1,
+			Statistics:   []core.LevelStats{},
+		},
+	}
+}
+
+func TestSetPersistentState(t *testing.T) {
+	dal := NewDAL()
+	accountID := "test-account-123"
+	require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: accountID}, newTestState(5)))
+
+	t.Run("matching revision", func(t *testing.T) {
+		state, err := dal.GetPersistentState(context.Background(), accountID)
+		require.NoError(t, err)
+
+		state.Energy.CurrentAmount = 4
+		err = dal.SetPersistentState(context.Background(), accountID, state)
+		require.NoError(t, err)
+
+		stored, err := dal.GetPersistentState(context.Background(), accountID)
+		require.NoError(t, err)
+		assert.Equal(t, state.Revision+1, stored.Revision)
+		assert.Equal(t, 4, stored.Energy.CurrentAmount)
+	})
+
+	t.Run("stale revision", func(t *testing.T) {
+		stale, err := dal.GetPersistentState(context.Background(), accountID)
+		require.NoError(t, err)
+
+		fresh := stale
+		require.NoError(t, dal.SetPersistentState(context.Background(), accountID, fresh))
+
+		stale.Energy.CurrentAmount = 0
+		err = dal.SetPersistentState(context.Background(), accountID, stale)
+
+		assert.ErrorIs(t, err, players.ErrStateConflict)
+		stored, err := dal.GetPersistentState(context.Background(), accountID)
+		require.NoError(t, err)
+		assert.Equal(t, fresh.Energy.CurrentAmount, stored.Energy.CurrentAmount)
+	})
+
+	t.Run("non-existent account", func(t *testing.T) {
+		err := dal.SetPersistentState(context.Background(), "non-existent-account", newTestState(1))
+
+		assert.ErrorIs(t, err, players.ErrAccountNotFound)
+	})
+}
+
+func TestConcurrentSetPersistentState(t *testing.T) {
+	t.Run("same revision should have a single winner", func(t *testing.T) {
+		dal := NewDAL()
+		accountID := "concurrent-test-account"
+		require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: accountID}, newTestState(50)))
+
+		initial, err := dal.GetPersistentState(context.Background(), accountID)
+		require.NoError(t, err)
+
+		const numGoroutines = 50
+		var wg sync.WaitGroup
+		errors := make(chan error, numGoroutines)
+
+		for i := range numGoroutines {
+			wg.Add(1)
+			go func(energy int) {
+				defer wg.Done()
+				state := initial
+				state.Energy.CurrentAmount = energy
+				errors <- dal.SetPersistentState(context.Background(), accountID, state)
+			}(i)
+		}
+
+		wg.Wait()
+		close(errors)
+
+		successes := 0
+		for err := range errors {
+			if err == nil {
+				successes++
+			} else {
+				assert.ErrorIs(t, err, players.ErrStateConflict)
+			}
+		}
+
+		assert.Equal(t, 1, successes)
+		stored, err := dal.GetPersistentState(context.Background(), accountID)
+		require.NoError(t, err)
+		assert.Equal(t, initial.Revision+1, stored.Revision)
+	})
+
+	t.Run("read-modify-write with retries should not lose updates", func(t *testing.T) {
+		dal := NewDAL()
+		accountID := "concurrent-test-account"
+		require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: accountID}, newTestState(0)))
+
+		const numGoroutines = 50
+		var wg sync.WaitGroup
+
+		for range numGoroutines {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					state, err := dal.GetPersistentState(context.Background(), accountID)
+					if !assert.NoError(t, err) {
+						return
+					}
+
+					state.Energy.CurrentAmount++
+					err = dal.SetPersistentState(context.Background(), accountID, state)
+					if err == nil {
+						return
+					}
+					if !assert.ErrorIs(t, err, players.ErrStateConflict) {
+						return
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		stored, err := dal.GetPersistentState(context.Background(), accountID)
+		require.NoError(t, err)
+		assert.Equal(t, numGoroutines, stored.Energy.CurrentAmount)
+		assert.Equal(t, int64(numGoroutines), stored.Revision)
+	})
+}
+
+func TestDAL_WithDoneContext_ShouldReturnContextError(t *testing.T) {
+	dal := NewDAL()
+	accountID := "test-account-123"
+	require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: accountID}, newTestState(5)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := dal.GetPersistentState(ctx, accountID)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = dal.SetPersistentState(ctx, accountID, newTestState(4))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = dal.GetSecretHash(ctx, accountID)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = dal.CreateAccount(ctx, players.Account{ID: "other-account"}, newTestState(5))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, accountCount(t, dal))
+}
+
+func accountCount(t *testing.T, dal *DAL) int {
+	t.Helper()
+	count, err := dal.GetAccountCount()
+	require.NoError(t, err)
+	return count
+}
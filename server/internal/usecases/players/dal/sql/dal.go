@@ -0,0 +1,330 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/errors"
+	"technical-test-backend/internal/usecases/players"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// DriverSQLite is the only driver supported, as the queries and the error
+// codes are SQLite's.
+const DriverSQLite = "sqlite"
+
+// busyTimeout is how long a connection waits for the lock held by another one
+// before failing with SQLITE_BUSY.
+const busyTimeout = 5 * time.Second
+
+var (
+	ErrFailedToOpenDatabase  = errors.New("failed to open database")
+	ErrFailedToMigrate       = errors.New("failed to migrate database")
+	ErrAccountAlreadyExists  = players.ErrAccountAlreadyExists
+	ErrAccountNotFound       = players.ErrAccountNotFound
+	ErrFailedToQueryDatabase = errors.New("failed to query database")
+)
+
+type DALConfig struct {
+	// Driver is the database/sql driver name, which must be DriverSQLite.
+	Driver       string
+	DSN          string
+	MaxOpenConns int
+}
+
+// DAL stores accounts and persistent states in a SQLite database. Its
+// migrations drop columns, which requires SQLite 3.35 or later, as embedded in
+// modernc.org/sqlite.
+type DAL struct {
+	db *sql.DB
+}
+
+func NewDAL(config DALConfig) (*DAL, error) {
+	if config.Driver != DriverSQLite {
+		return nil, errors.Wrap(fmt.Errorf("unsupported driver %q", config.Driver), ErrFailedToOpenDatabase)
+	}
+
+	db, err := sql.Open(config.Driver, withBusyTimeout(config.DSN))
+	if err != nil {
+		return nil, errors.Wrap(err, ErrFailedToOpenDatabase)
+	}
+
+	db.SetMaxOpenConns(config.MaxOpenConns)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, ErrFailedToOpenDatabase)
+	}
+
+	if err := Migrate(db); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, ErrFailedToMigrate)
+	}
+
+	return &DAL{
+		db: db,
+	}, nil
+}
+
+func (d *DAL) CreateAccount(ctx context.Context, account players.Account, state core.PersistentState) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+	defer tx.Rollback()
+
+	// The primary key rejects concurrent creations of the same account, which
+	// a prior lookup wouldn't.
+	_, err = tx.ExecContext(ctx, `INSERT INTO accounts (id, secret_hash) VALUES (?, ?)`, account.ID, account.SecretHash)
+	if isPrimaryKeyViolation(err) {
+		return ErrAccountAlreadyExists
+	}
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO player_states (account_id, revision, energy_current_amount, energy_last_recharge_at, current_level) VALUES (?, ?, ?, ?, ?)`,
+		account.ID, state.Revision, state.Energy.CurrentAmount, formatTime(state.Energy.LastRechargeAt), state.LevelProgression.CurrentLevel)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	if err := insertLevelStats(ctx, tx, account.ID, state.LevelProgression.Statistics); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	return nil
+}
+
+func (d *DAL) GetSecretHash(ctx context.Context, accountID string) (string, error) {
+	var secretHash string
+	err := d.db.QueryRowContext(ctx, `SELECT secret_hash FROM accounts WHERE id = ?`, accountID).Scan(&secretHash)
+	if err == sql.ErrNoRows {
+		return "", ErrAccountNotFound
+	}
+	if err != nil {
+		return "", errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	return secretHash, nil
+}
+
+func (d *DAL) GetPersistentState(ctx context.Context, accountID string) (core.PersistentState, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return core.PersistentState{}, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+	defer tx.Rollback()
+
+	var state core.PersistentState
+	var lastRechargeAt string
+	err = tx.QueryRowContext(ctx, `SELECT revision, energy_current_amount, energy_last_recharge_at, current_level FROM player_states WHERE account_id = ?`, accountID).
+		Scan(&state.Revision, &state.Energy.CurrentAmount, &lastRechargeAt, &state.LevelProgression.CurrentLevel)
+	if err == sql.ErrNoRows {
+		return core.PersistentState{}, ErrAccountNotFound
+	}
+	if err != nil {
+		return core.PersistentState{}, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	state.Energy.LastRechargeAt, err = time.Parse(time.RFC3339Nano, lastRechargeAt)
+	if err != nil {
+		return core.PersistentState{}, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT level_id, best_score, wins, losses FROM level_stats WHERE account_id = ? ORDER BY position`, accountID)
+	if err != nil {
+		return core.PersistentState{}, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+	defer rows.Close()
+
+	state.LevelProgression.Statistics = []core.LevelStats{}
+	for rows.Next() {
+		var stats core.LevelStats
+		if err := rows.Scan(&stats.LevelID, &stats.BestScore, &stats.Wins, &stats.Losses); err != nil {
+			return core.PersistentState{}, errors.Wrap(err, ErrFailedToQueryDatabase)
+		}
+		state.LevelProgression.Statistics = append(state.LevelProgression.Statistics, stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return core.PersistentState{}, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	return state, nil
+}
+
+func (d *DAL) SetPersistentState(ctx context.Context, accountID string, state core.PersistentState) error {
+	return d.setPersistentState(ctx, accountID, state, nil)
+}
+
+func (d *DAL) SetPersistentStateWithReceipt(ctx context.Context, accountID string, state core.PersistentState, receipt players.Receipt) error {
+	return d.setPersistentState(ctx, accountID, state, &receipt)
+}
+
+func (d *DAL) GetReceipt(ctx context.Context, accountID, sessionID string) (players.Receipt, bool, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return players.Receipt{}, false, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM accounts WHERE id = ?`, accountID).Scan(&exists); err != nil {
+		return players.Receipt{}, false, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+	if exists == 0 {
+		return players.Receipt{}, false, ErrAccountNotFound
+	}
+
+	receipt := players.Receipt{SessionID: sessionID}
+	var sessionState string
+	err = tx.QueryRowContext(ctx, `SELECT sequence, session_state FROM receipts WHERE account_id = ? AND session_id = ?`, accountID, sessionID).
+		Scan(&receipt.Sequence, &sessionState)
+	if err == sql.ErrNoRows {
+		return players.Receipt{}, false, nil
+	}
+	if err != nil {
+		return players.Receipt{}, false, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	if err := json.Unmarshal([]byte(sessionState), &receipt.SessionState); err != nil {
+		return players.Receipt{}, false, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	return receipt, true, nil
+}
+
+// setPersistentState updates the state and upserts the receipt in the same
+// transaction, keeping the newest MaxReceipts receipts of the account.
+func (d *DAL) setPersistentState(ctx context.Context, accountID string, state core.PersistentState, receipt *players.Receipt) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE player_states SET revision = revision + 1, energy_current_amount = ?, energy_last_recharge_at = ?, current_level = ? WHERE account_id = ? AND revision = ?`,
+		state.Energy.CurrentAmount, formatTime(state.Energy.LastRechargeAt), state.LevelProgression.CurrentLevel, accountID, state.Revision)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+	if updated == 0 {
+		var exists int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM player_states WHERE account_id = ?`, accountID).Scan(&exists); err != nil {
+			return errors.Wrap(err, ErrFailedToQueryDatabase)
+		}
+		if exists == 0 {
+			return ErrAccountNotFound
+		}
+		return players.ErrStateConflict
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM level_stats WHERE account_id = ?`, accountID); err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	if err := insertLevelStats(ctx, tx, accountID, state.LevelProgression.Statistics); err != nil {
+		return err
+	}
+
+	if receipt != nil {
+		if err := upsertReceipt(ctx, tx, accountID, state.Revision+1, *receipt); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	return nil
+}
+
+func (d *DAL) GetAccountCount() (int, error) {
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM accounts`).Scan(&count); err != nil {
+		return 0, errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	return count, nil
+}
+
+func (d *DAL) Close() error {
+	return d.db.Close()
+}
+
+func insertLevelStats(ctx context.Context, tx *sql.Tx, accountID string, statistics []core.LevelStats) error {
+	for position, stats := range statistics {
+		_, err := tx.ExecContext(ctx, `INSERT INTO level_stats (account_id, position, level_id, best_score, wins, losses) VALUES (?, ?, ?, ?, ?, ?)`,
+			accountID, position, stats.LevelID, stats.BestScore, stats.Wins, stats.Losses)
+		if err != nil {
+			return errors.Wrap(err, ErrFailedToQueryDatabase)
+		}
+	}
+
+	return nil
+}
+
+func upsertReceipt(ctx context.Context, tx *sql.Tx, accountID string, revision int64, receipt players.Receipt) error {
+	sessionState, err := json.Marshal(receipt.SessionState)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO receipts (account_id, session_id, sequence, session_state, revision) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (account_id, session_id) DO UPDATE SET sequence = excluded.sequence, session_state = excluded.session_state, revision = excluded.revision`,
+		accountID, receipt.SessionID, receipt.Sequence, string(sessionState), revision)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM receipts WHERE account_id = ? AND session_id NOT IN (
+		SELECT session_id FROM receipts WHERE account_id = ? ORDER BY revision DESC LIMIT ?)`,
+		accountID, accountID, players.MaxReceipts)
+	if err != nil {
+		return errors.Wrap(err, ErrFailedToQueryDatabase)
+	}
+
+	return nil
+}
+
+// isPrimaryKeyViolation tells whether err is the rejection of a duplicate
+// primary key by SQLite, the driver shipped with the server.
+func isPrimaryKeyViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// withBusyTimeout sets busyTimeout on every connection of the DSN, unless it
+// sets its own.
+func withBusyTimeout(dsn string) string {
+	if strings.Contains(dsn, "busy_timeout") {
+		return dsn
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%s_pragma=busy_timeout(%d)", dsn, separator, busyTimeout.Milliseconds())
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
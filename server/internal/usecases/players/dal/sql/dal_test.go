@@ -0,0 +1,263 @@
+//go:build unit
+// +build unit
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/usecases/players"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+func newTestConfig(t *testing.T) DALConfig {
+	return DALConfig{
+		Driver:       "sqlite",
+		DSN:          filepath.Join(t.TempDir(), "players.db"),
+		MaxOpenConns: 1,
+	}
+}
+
+func newTestState(energy int) core.PersistentState {
+	return core.PersistentState{
+		Energy: core.Energy{
+			CurrentAmount:  energy,
+			LastRechargeAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		LevelProgression: core.LevelProgression{
+			CurrentLevel: 1,
+			Statistics:   []core.LevelStats{},
+		},
+	}
+}
+
+func TestCreateAccount(t *testing.T) {
+	dal, err := NewDAL(newTestConfig(t))
+	require.NoError(t, err)
+	defer dal.Close()
+
+	t.Run("successful creation", func(t *testing.T) {
+		account := players.Account{ID: "account-1", SecretHash: "hash-1"}
+
+		err := dal.CreateAccount(context.Background(), account, newTestState(5))
+		require.NoError(t, err)
+
+		secretHash, err := dal.GetSecretHash(context.Background(), account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, account.SecretHash, secretHash)
+
+		state, err := dal.GetPersistentState(context.Background(), account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, newTestState(5), state)
+	})
+
+	t.Run("duplicate account", func(t *testing.T) {
+		account := players.Account{ID: "account-2", SecretHash: "hash-2"}
+		require.NoError(t, dal.CreateAccount(context.Background(), account, newTestState(5)))
+
+		err := dal.CreateAccount(context.Background(), account, newTestState(5))
+
+		assert.ErrorIs(t, err, ErrAccountAlreadyExists)
+	})
+
+	t.Run("account count", func(t *testing.T) {
+		assert.Equal(t, 2, accountCount(t, dal))
+	})
+}
+
+func TestCreateAccount_WithConcurrentCreations_ShouldCreateOnce(t *testing.T) {
+	config := newTestConfig(t)
+	config.DSN += "?_pragma=busy_timeout(5000)"
+	config.MaxOpenConns = 8
+	dal, err := NewDAL(config)
+	require.NoError(t, err)
+	defer dal.Close()
+
+	account := players.Account{ID: "account-1", SecretHash: "hash-1"}
+
+	const creations = 8
+	errs := make(chan error, creations)
+	var wg sync.WaitGroup
+	for range creations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- dal.CreateAccount(context.Background(), account, newTestState(5))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrAccountAlreadyExists)
+	}
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 1, accountCount(t, dal))
+}
+
+func TestGetSecretHash_NonExistentAccount(t *testing.T) {
+	dal, err := NewDAL(newTestConfig(t))
+	require.NoError(t, err)
+	defer dal.Close()
+
+	_, err = dal.GetSecretHash(context.Background(), "non-existent-account")
+
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestSetPersistentState(t *testing.T) {
+	dal, err := NewDAL(newTestConfig(t))
+	require.NoError(t, err)
+	defer dal.Close()
+
+	account := players.Account{ID: "account-1", SecretHash: "hash-1"}
+	require.NoError(t, dal.CreateAccount(context.Background(), account, newTestState(5)))
+
+	t.Run("successful update with level stats", func(t *testing.T) {
+		expectedState := newTestState(3)
+		expectedState.LevelProgression.CurrentLevel = 3
+		expectedState.LevelProgression.Statistics = []core.LevelStats{
+			{LevelID: 2, BestScore: 4, Wins: 1, Losses: 2},
+			{LevelID: 1, BestScore: 7, Wins: 3, Losses: 0},
+		}
+
+		err := dal.SetPersistentState(context.Background(), account.ID, expectedState)
+		require.NoError(t, err)
+
+		expectedState.Revision++
+		state, err := dal.GetPersistentState(context.Background(), account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expectedState, state)
+	})
+
+	t.Run("removed level stats", func(t *testing.T) {
+		expectedState := newTestState(1)
+		expectedState.Revision = 1
+
+		err := dal.SetPersistentState(context.Background(), account.ID, expectedState)
+		require.NoError(t, err)
+
+		expectedState.Revision++
+		state, err := dal.GetPersistentState(context.Background(), account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expectedState, state)
+	})
+
+	t.Run("stale revision", func(t *testing.T) {
+		stale := newTestState(0)
+
+		err := dal.SetPersistentState(context.Background(), account.ID, stale)
+
+		assert.ErrorIs(t, err, players.ErrStateConflict)
+	})
+
+	t.Run("non-existent account", func(t *testing.T) {
+		err := dal.SetPersistentState(context.Background(), "non-existent-account", newTestState(1))
+
+		assert.ErrorIs(t, err, ErrAccountNotFound)
+	})
+}
+
+func TestReopen_ShouldRestoreState(t *testing.T) {
+	config := newTestConfig(t)
+
+	dal, err := NewDAL(config)
+	require.NoError(t, err)
+
+	account := players.Account{ID: "account-1", SecretHash: "hash-1"}
+	require.NoError(t, dal.CreateAccount(context.Background(), account, newTestState(5)))
+	require.NoError(t, dal.Close())
+
+	reopened, err := NewDAL(config)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	state, err := reopened.GetPersistentState(context.Background(), account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, newTestState(5), state)
+}
+
+func TestNewDAL_UnknownDriver_ShouldFail(t *testing.T) {
+	_, err := NewDAL(DALConfig{Driver: "unknown", DSN: "unused"})
+
+	assert.ErrorIs(t, err, ErrFailedToOpenDatabase)
+}
+
+func TestNewDAL_ShouldSetBusyTimeout(t *testing.T) {
+	dal, err := NewDAL(newTestConfig(t))
+	require.NoError(t, err)
+	defer dal.Close()
+
+	var timeout int64
+	require.NoError(t, dal.db.QueryRow(`PRAGMA busy_timeout`).Scan(&timeout))
+	assert.Equal(t, busyTimeout.Milliseconds(), timeout)
+}
+
+func TestGetAccountCount_WhenClosed_ShouldFail(t *testing.T) {
+	dal, err := NewDAL(newTestConfig(t))
+	require.NoError(t, err)
+	require.NoError(t, dal.Close())
+
+	_, err = dal.GetAccountCount()
+
+	assert.ErrorIs(t, err, ErrFailedToQueryDatabase)
+}
+
+func TestSetPersistentStateWithReceipt_ShouldKeepNewestReceipts(t *testing.T) {
+	config := newTestConfig(t)
+
+	dal, err := NewDAL(config)
+	require.NoError(t, err)
+	require.NoError(t, dal.CreateAccount(context.Background(), players.Account{ID: "account-1", SecretHash: "hash-1"}, newTestState(5)))
+	levelID := 1
+	for i := range players.MaxReceipts + 1 {
+		state := newTestState(i)
+		state.Revision = int64(i)
+		receipt := players.Receipt{
+			SessionID:    fmt.Sprintf("session-%d", i),
+			Sequence:     uint64(i + 1),
+			SessionState: core.SessionState{CurrentLevelID: &levelID},
+		}
+		require.NoError(t, dal.SetPersistentStateWithReceipt(context.Background(), "account-1", state, receipt))
+	}
+	require.NoError(t, dal.Close())
+
+	reopened, err := NewDAL(config)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	_, found, err := reopened.GetReceipt(context.Background(), "account-1", "session-0")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	receipt, found, err := reopened.GetReceipt(context.Background(), "account-1", fmt.Sprintf("session-%d", players.MaxReceipts))
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, uint64(players.MaxReceipts+1), receipt.Sequence)
+	assert.Equal(t, core.SessionState{CurrentLevelID: &levelID}, receipt.SessionState)
+
+	_, _, err = reopened.GetReceipt(context.Background(), "account-2", "session-0")
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func accountCount(t *testing.T, dal *DAL) int {
+	t.Helper()
+	count, err := dal.GetAccountCount()
+	require.NoError(t, err)
+	return count
+}
@@ -0,0 +1,186 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"technical-test-backend/internal/usecases/players"
+	"time"
+)
+
+type Migration struct {
+	Version    int
+	Name       string
+	Statements []string
+	// Apply optionally migrates data in Go, after Statements have run and in
+	// the same transaction.
+	Apply func(tx *sql.Tx) error
+}
+
+// migrations must only ever be appended to. Applied versions are recorded in
+// the schema_migrations table and are never run twice.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create accounts and player states",
+		Statements: []string{
+			`CREATE TABLE accounts (
+				id TEXT PRIMARY KEY,
+				access_token TEXT NOT NULL
+			)`,
+			`CREATE TABLE player_states (
+				account_id TEXT PRIMARY KEY REFERENCES accounts(id),
+				energy_current_amount INTEGER NOT NULL,
+				energy_last_recharge_at TEXT NOT NULL,
+				current_level INTEGER NOT NULL
+			)`,
+			`CREATE TABLE level_stats (
+				account_id TEXT NOT NULL REFERENCES accounts(id),
+				position INTEGER NOT NULL,
+				level_id INTEGER NOT NULL,
+				best_score INTEGER NOT NULL,
+				wins INTEGER NOT NULL,
+				losses INTEGER NOT NULL,
+				PRIMARY KEY (account_id, level_id)
+			)`,
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add player state revision",
+		Statements: []string{
+			`ALTER TABLE player_states ADD COLUMN revision INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		Version: 3,
+		Name:    "hash account access tokens",
+		Statements: []string{
+			`ALTER TABLE accounts ADD COLUMN secret_hash TEXT NOT NULL DEFAULT ''`,
+		},
+		Apply: hashAccessTokens,
+	},
+	{
+		Version: 4,
+		Name:    "drop account access tokens",
+		Statements: []string{
+			`ALTER TABLE accounts DROP COLUMN access_token`,
+		},
+	},
+	{
+		Version: 5,
+		Name:    "create receipts",
+		Statements: []string{
+			`CREATE TABLE receipts (
+				account_id TEXT NOT NULL REFERENCES accounts(id),
+				session_id TEXT NOT NULL,
+				sequence INTEGER NOT NULL,
+				session_state TEXT NOT NULL,
+				revision INTEGER NOT NULL,
+				PRIMARY KEY (account_id, session_id)
+			)`,
+		},
+	},
+}
+
+// Migrate applies every migration newer than the current schema version, each
+// one in its own transaction.
+func Migrate(db *sql.DB) error {
+	return migrate(db, migrations)
+}
+
+func migrate(db *sql.DB, migrations []Migration) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	currentVersion, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if migration.Version <= currentVersion {
+			continue
+		}
+
+		if err := applyMigration(db, migration); err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func SchemaVersion(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return int(version.Int64), nil
+}
+
+func applyMigration(db *sql.DB, migration Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range migration.Statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	if migration.Apply != nil {
+		if err := migration.Apply(tx); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		migration.Version, migration.Name, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// hashAccessTokens turns the plain access tokens stored before secrets were
+// hashed into secret hashes, so existing accounts can still log in.
+func hashAccessTokens(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, access_token FROM accounts`)
+	if err != nil {
+		return err
+	}
+
+	secretHashes := make(map[string]string)
+	for rows.Next() {
+		var id, accessToken string
+		if err := rows.Scan(&id, &accessToken); err != nil {
+			rows.Close()
+			return err
+		}
+		secretHashes[id] = players.HashSecret(accessToken)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, secretHash := range secretHashes {
+		if _, err := tx.Exec(`UPDATE accounts SET secret_hash = ? WHERE id = ?`, secretHash, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
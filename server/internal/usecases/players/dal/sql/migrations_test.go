@@ -0,0 +1,104 @@
+//go:build unit
+// +build unit
+
+package sql
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"technical-test-backend/internal/usecases/players"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "migrations.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrate_ShouldApplyAllMigrations(t *testing.T) {
+	db := openTestDB(t)
+
+	err := Migrate(db)
+	require.NoError(t, err)
+
+	version, err := SchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, migrations[len(migrations)-1].Version, version)
+}
+
+func TestMigrate_ShouldBeIdempotent(t *testing.T) {
+	db := openTestDB(t)
+
+	require.NoError(t, Migrate(db))
+	require.NoError(t, Migrate(db))
+
+	var applied int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied))
+	assert.Equal(t, len(migrations), applied)
+}
+
+func TestMigrate_ShouldOnlyApplyPendingMigrations(t *testing.T) {
+	db := openTestDB(t)
+
+	first := []Migration{
+		{Version: 1, Name: "create table", Statements: []string{`CREATE TABLE things (id TEXT PRIMARY KEY)`}},
+	}
+	require.NoError(t, migrate(db, first))
+
+	second := append(first, Migration{
+		Version:    2,
+		Name:       "add column",
+		Statements: []string{`ALTER TABLE things ADD COLUMN name TEXT`},
+	})
+	require.NoError(t, migrate(db, second))
+
+	version, err := SchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	_, err = db.Exec(`INSERT INTO things (id, name) VALUES ('a', 'b')`)
+	assert.NoError(t, err)
+}
+
+func TestMigrate_FailedMigration_ShouldRollback(t *testing.T) {
+	db := openTestDB(t)
+
+	broken := []Migration{
+		{Version: 1, Name: "broken", Statements: []string{
+			`CREATE TABLE things (id TEXT PRIMARY KEY)`,
+			`NOT VALID SQL`,
+		}},
+	}
+
+	err := migrate(db, broken)
+	assert.Error(t, err)
+
+	version, err := SchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+}
+
+func TestMigrate_ShouldHashLegacyAccessTokens(t *testing.T) {
+	db := openTestDB(t)
+
+	require.NoError(t, migrate(db, migrations[:2]))
+	_, err := db.Exec(`INSERT INTO accounts (id, access_token) VALUES ('account-1', 'token-1')`)
+	require.NoError(t, err)
+
+	require.NoError(t, Migrate(db))
+
+	var secretHash string
+	require.NoError(t, db.QueryRow(`SELECT secret_hash FROM accounts WHERE id = 'account-1'`).Scan(&secretHash))
+	assert.Equal(t, players.HashSecret("token-1"), secretHash)
+
+	_, err = db.Exec(`SELECT access_token FROM accounts`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,10 @@
+package grpc
+
+import (
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/players"
+)
+
+func CreateGRPCHandler(sessionPool sessions.Pool, dal players.StateDAL) *Handler {
+	return NewHandler(players.NewStateHandler(dal), sessionPool)
+}
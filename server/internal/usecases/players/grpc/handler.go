@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"technical-test-backend/internal/core"
+	grpcutils "technical-test-backend/internal/grpc"
+	"technical-test-backend/internal/grpc/gamepb"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases"
+	"technical-test-backend/internal/usecases/players"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errorStatuses = grpcutils.StatusCodes{
+	sessions.ErrorCodeSessionDataMissing: codes.Unauthenticated,
+}
+
+// Handler serves the GetPlayerState method of the InitializationHandler
+// service, along with the configs Handler.
+type Handler struct {
+	stateHandler *players.StateHandler
+	sessionPool  sessions.Pool
+}
+
+func NewHandler(stateHandler *players.StateHandler, sessionPool sessions.Pool) *Handler {
+	return &Handler{
+		stateHandler: stateHandler,
+		sessionPool:  sessionPool,
+	}
+}
+
+func (h *Handler) GetPlayerState(ctx context.Context, req *gamepb.GetPlayerStateRequest) (*gamepb.GetPlayerStateResponse, error) {
+	accountID := grpcutils.AccountID(ctx)
+
+	var sessionState core.SessionState
+	if err := h.sessionPool.GetSessionData(ctx, grpcutils.SessionID(ctx), &sessionState); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, grpcutils.MapError(ctxErr, nil)
+		}
+		return nil, grpcutils.MapError(err, errorStatuses)
+	}
+
+	sessionData := usecases.SessionData{
+		AccountID:    accountID,
+		SessionState: &sessionState,
+	}
+
+	res, err := h.stateHandler.GetPlayerState(ctx, sessionData, &players.GetPlayerStateArgs{})
+	if err != nil {
+		return nil, grpcutils.MapError(err, nil)
+	}
+
+	return &gamepb.GetPlayerStateResponse{
+		PlayerState: &gamepb.PlayerState{
+			Persistent: grpcutils.PersistentStateToProto(res.PlayerState.Persistent),
+			Session:    grpcutils.SessionStateToProto(res.PlayerState.Session),
+		},
+		ServerTime: timestamppb.New(res.ServerTime),
+	}, nil
+}
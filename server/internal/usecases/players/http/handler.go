@@ -9,6 +9,10 @@
 	"technical-test-backend/internal/usecases/players"
 )
 
+var errorStatuses = httputils.StatusCodes{
+	sessions.ErrorCodeSessionDataMissing: http.StatusUnauthorized,
+}
+
 type Handler struct {
 	stateHandler *players.StateHandler
 	sessionPool  sessions.Pool
@@ -25,8 +29,12 @@ func (h *Handler) HandleGetPlayerState(w http.ResponseWriter, r *http.Request) {
 	accountID := r.Header.Get("X-Account-ID")
 
 	var sessionState core.SessionState
-	if err := h.sessionPool.GetSessionData(accountID, &sessionState); err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, "missing session data")
+	if err := h.sessionPool.GetSessionData(r.Context(), r.Header.Get("X-Session-ID"), &sessionState); err != nil {
+		if ctxErr := r.Context().Err(); ctxErr != nil {
+			httputils.WriteMappedError(w, ctxErr, nil)
+			return
+		}
+		httputils.WriteMappedError(w, err, errorStatuses)
 		return
 	}
 
@@ -41,9 +49,9 @@ func (h *Handler) HandleGetPlayerState(w http.ResponseWriter, r *http.Request) {
 		SessionState: &sessionState,
 	}
 
-	res, err := h.stateHandler.GetPlayerState(sessionData, &args)
+	res, err := h.stateHandler.GetPlayerState(r.Context(), sessionData, &args)
 	if err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		httputils.WriteMappedError(w, err, nil)
 		return
 	}
 
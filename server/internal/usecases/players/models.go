@@ -1,6 +1,59 @@
 package players
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"technical-test-backend/internal/core"
+)
+
+// MaxReceipts is the number of sessions whose last receipt is kept per
+// account, the oldest receipts being dropped past it.
+const MaxReceipts = 8
+
+// Receipt records the last sequenced batch of commands applied by a session,
+// with the session state it resulted in. It's written along with the
+// persistent state produced by the batch, so a batch whose session data
+// couldn't be stored afterwards is still known to be applied.
+type Receipt struct {
+	SessionID    string            `json:"sessionId"`
+	Sequence     uint64            `json:"sequence"`
+	SessionState core.SessionState `json:"sessionState"`
+}
+
 type Account struct {
-	ID          string `json:"id"`
-	AccessToken string `json:"accessToken"`
+	ID string `json:"id"`
+	// SecretHash is the hash of the secret issued to the account, as returned
+	// by HashSecret. The secret itself is never stored.
+	SecretHash string `json:"secretHash"`
+}
+
+// AppendReceipt returns the receipts with receipt last, replacing the previous
+// receipt of its session, and without the oldest ones past MaxReceipts.
+func AppendReceipt(receipts []Receipt, receipt Receipt) []Receipt {
+	kept := make([]Receipt, 0, min(len(receipts)+1, MaxReceipts))
+	for _, existing := range receipts {
+		if existing.SessionID != receipt.SessionID {
+			kept = append(kept, existing)
+		}
+	}
+	kept = append(kept, receipt)
+
+	return kept[max(len(kept)-MaxReceipts, 0):]
+}
+
+// FindReceipt returns the receipt of the session among receipts.
+func FindReceipt(receipts []Receipt, sessionID string) (Receipt, bool) {
+	for _, receipt := range receipts {
+		if receipt.SessionID == sessionID {
+			return receipt, true
+		}
+	}
+	return Receipt{}, false
+}
+
+// HashSecret hashes an account secret for storage. Secrets are random and long
+// enough that a fast hash doesn't make them practical to brute-force.
+func HashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
 }
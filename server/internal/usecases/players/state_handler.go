@@ -1,6 +1,7 @@
 package players
 
 import (
+	"context"
 	"fmt"
 	"technical-test-backend/internal/core"
 	"technical-test-backend/internal/usecases"
@@ -29,10 +30,10 @@ func NewStateHandler(dal StateDAL) *StateHandler {
 	}
 }
 
-func (h *StateHandler) GetPlayerState(sessionData usecases.SessionData, args *GetPlayerStateArgs) (*GetPlayerStateRes, error) {
-	persistentState, err := h.dal.GetPersistentState(sessionData.AccountID)
+func (h *StateHandler) GetPlayerState(ctx context.Context, sessionData usecases.SessionData, args *GetPlayerStateArgs) (*GetPlayerStateRes, error) {
+	persistentState, err := h.dal.GetPersistentState(ctx, sessionData.AccountID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get persistent state: %v", err)
+		return nil, fmt.Errorf("failed to get persistent state: %w", err)
 	}
 
 	return &GetPlayerStateRes{
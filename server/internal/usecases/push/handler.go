@@ -0,0 +1,284 @@
+package push
+
+import (
+	"context"
+	"log"
+	"sync"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/configs"
+	"technical-test-backend/internal/usecases/players"
+	"time"
+)
+
+// streamBufferSize is how many events a stream holds while its client is slow
+// to read them. Further events are dropped.
+const streamBufferSize = 16
+
+type EventType string
+
+const (
+	EventConnected EventType = "connected"
+	// EventKeepAlive carries no data, it keeps the connection busy so proxies
+	// and clients can tell it's still open.
+	EventKeepAlive      EventType = "keep_alive"
+	EventSessionEnded   EventType = "session_ended"
+	EventConfigsUpdated EventType = "configs_updated"
+	EventEnergyRefilled EventType = "energy_refilled"
+)
+
+type Event struct {
+	Type EventType
+	Data interface{}
+}
+
+type ConnectedData struct {
+	ConfigsVersion string    `json:"configsVersion,omitempty"`
+	ServerTime     time.Time `json:"serverTime"`
+}
+
+// SessionEndedData tells why the session ended. The stream is closed right
+// after it.
+type SessionEndedData struct {
+	Reason sessions.EndReason `json:"reason"`
+}
+
+type ConfigsUpdatedData struct {
+	ConfigsVersion string `json:"configsVersion"`
+}
+
+type EnergyRefilledData struct {
+	CurrentAmount int `json:"currentAmount"`
+}
+
+type Config struct {
+	// KeepAliveInterval is how often a stream refreshes its session, so it
+	// must be shorter than the session TTL. Zero disables the streams.
+	KeepAliveInterval time.Duration
+}
+
+// Handler pushes events to the clients holding a stream open, which also
+// keeps their session alive in place of heartbeats.
+type Handler struct {
+	config          Config
+	sessionPool     sessions.Pool
+	dal             players.StateDAL
+	configsProvider *configs.Provider
+
+	mutex   sync.Mutex
+	streams map[string]map[*stream]struct{}
+	closed  chan struct{}
+	close   sync.Once
+}
+
+func NewHandler(config Config, sessionPool sessions.Pool, dal players.StateDAL, configsProvider *configs.Provider) *Handler {
+	return &Handler{
+		config:          config,
+		sessionPool:     sessionPool,
+		dal:             dal,
+		configsProvider: configsProvider,
+		streams:         make(map[string]map[*stream]struct{}),
+		closed:          make(chan struct{}),
+	}
+}
+
+// stream is an open connection of a session.
+type stream struct {
+	sessionID string
+	events    chan Event
+	ended     chan struct{}
+	end       sync.Once
+	reason    sessions.EndReason
+}
+
+// push queues the event, dropping it if the client can't keep up.
+func (s *stream) push(event Event) {
+	select {
+	case s.events <- event:
+	default:
+		log.Printf("Dropped %s event of session %s", event.Type, s.sessionID)
+	}
+}
+
+func (s *stream) endWith(reason sessions.EndReason) {
+	s.end.Do(func() {
+		s.reason = reason
+		close(s.ended)
+	})
+}
+
+// Stream sends the events of the session until ctx is done, the session ends
+// or send fails. The energy is checked at every keep-alive, and when it's
+// expected to be refilled.
+func (h *Handler) Stream(ctx context.Context, sessionID string, accountID string, send func(Event) error) error {
+	s := h.register(sessionID)
+	defer h.unregister(s)
+
+	connected := ConnectedData{ServerTime: time.Now().UTC()}
+	if versioned, err := h.configsProvider.GetVersionedConfigs(); err == nil {
+		connected.ConfigsVersion = versioned.Version
+	}
+	if err := send(Event{Type: EventConnected, Data: connected}); err != nil {
+		return err
+	}
+
+	keepAlive := time.NewTicker(h.config.KeepAliveInterval)
+	defer keepAlive.Stop()
+
+	refill := time.NewTimer(0)
+	refill.Stop()
+	defer refill.Stop()
+
+	// A refill is only pushed once the energy was seen below the max.
+	refilled := true
+	checkEnergy := func() error {
+		amount, refilledAt, err := h.getEnergy(ctx, accountID)
+		if err != nil {
+			log.Printf("Failed to check energy of account %s: %v", accountID, err)
+			return nil
+		}
+
+		wasRefilled := refilled
+		refilled = !time.Now().Before(refilledAt)
+		if !refilled {
+			refill.Reset(time.Until(refilledAt))
+			return nil
+		}
+
+		if wasRefilled {
+			return nil
+		}
+		return send(Event{Type: EventEnergyRefilled, Data: EnergyRefilledData{CurrentAmount: amount}})
+	}
+
+	if err := checkEnergy(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.closed:
+			return nil
+		case <-s.ended:
+			return send(Event{Type: EventSessionEnded, Data: SessionEndedData{Reason: s.reason}})
+		case event := <-s.events:
+			if err := send(event); err != nil {
+				return err
+			}
+		case <-refill.C:
+			if err := checkEnergy(); err != nil {
+				return err
+			}
+		case <-keepAlive.C:
+			if _, alive := h.sessionPool.GetAccountID(ctx, sessionID); !alive {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return send(Event{Type: EventSessionEnded, Data: SessionEndedData{Reason: h.getEndReason(ctx, sessionID)}})
+			}
+
+			if err := h.sessionPool.UpdateActivity(ctx, sessionID); err != nil {
+				log.Printf("Warning: Failed to update session activity: %v", err)
+			}
+
+			if err := send(Event{Type: EventKeepAlive}); err != nil {
+				return err
+			}
+
+			if err := checkEnergy(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// HandleSessionEvent ends the streams of the sessions that ended. Sessions
+// ended by another instance are only noticed at the next keep-alive.
+func (h *Handler) HandleSessionEvent(event sessions.Event) {
+	reason, ended := sessions.EndReasonOf(event.Type)
+	if !ended {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for s := range h.streams[event.Session.ID] {
+		s.endWith(reason)
+	}
+}
+
+// HandleConfigsUpdate tells every stream about the new configs version.
+func (h *Handler) HandleConfigsUpdate(versioned configs.VersionedConfigs) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, sessionStreams := range h.streams {
+		for s := range sessionStreams {
+			s.push(Event{Type: EventConfigsUpdated, Data: ConfigsUpdatedData{ConfigsVersion: versioned.Version}})
+		}
+	}
+}
+
+// Close ends every stream, as they would otherwise hold a graceful shutdown
+// until its deadline.
+func (h *Handler) Close() {
+	h.close.Do(func() {
+		close(h.closed)
+	})
+}
+
+func (h *Handler) register(sessionID string) *stream {
+	s := &stream{
+		sessionID: sessionID,
+		events:    make(chan Event, streamBufferSize),
+		ended:     make(chan struct{}),
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.streams[sessionID] == nil {
+		h.streams[sessionID] = make(map[*stream]struct{})
+	}
+	h.streams[sessionID][s] = struct{}{}
+
+	return s
+}
+
+func (h *Handler) unregister(s *stream) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.streams[s.sessionID], s)
+	if len(h.streams[s.sessionID]) == 0 {
+		delete(h.streams, s.sessionID)
+	}
+}
+
+// getEnergy returns the energy of the account as of now, and when it gets
+// back to the max.
+func (h *Handler) getEnergy(ctx context.Context, accountID string) (int, time.Time, error) {
+	gameConfigs, err := h.configsProvider.GetConfigs()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	state, err := h.dal.GetPersistentState(ctx, accountID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return state.Energy.PredictAmount(time.Now(), gameConfigs.Energy), state.Energy.RefilledAt(gameConfigs.Energy), nil
+}
+
+// getEndReason falls back to expired for a session the pool doesn't remember,
+// as it's the most likely reason.
+func (h *Handler) getEndReason(ctx context.Context, sessionID string) sessions.EndReason {
+	if tombstone, found := h.sessionPool.GetTombstone(ctx, sessionID); found {
+		return tombstone.Reason
+	}
+
+	return sessions.EndReasonExpired
+}
@@ -0,0 +1,244 @@
+//go:build unit
+// +build unit
+
+package push
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"technical-test-backend/internal/core"
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/sessions/memory"
+	"technical-test-backend/internal/usecases/configs"
+	"technical-test-backend/internal/usecases/players"
+	playersmemory "technical-test-backend/internal/usecases/players/dal/memory"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigJSON = `{
+	"energy": {"maxEnergy": 10, "rechargeIntervalSeconds": 1},
+	"levels": [{}, {"energyCost": 1, "maxRolls": 10, "targetNumber": 1, "energyReward": 2}],
+	"onboarding": {"variants": [{"name": "default", "weight": 1, "energy": 5, "currentLevel": 1}]}
+}`
+
+type testEnv struct {
+	handler     *Handler
+	sessionPool *memory.SessionPool
+	dal         players.DAL
+	provider    *configs.Provider
+	configPath  string
+}
+
+func newTestEnv(t *testing.T, keepAliveInterval time.Duration, sessionTTL time.Duration) *testEnv {
+	configPath := filepath.Join(t.TempDir(), "game_config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(testConfigJSON), 0o600))
+
+	env := &testEnv{
+		sessionPool: memory.NewSessionPool(memory.SessionPoolConfig{TTL: sessionTTL, TombstoneTTL: time.Minute}),
+		dal:         playersmemory.NewDAL(),
+		provider:    configs.NewProvider(configs.ProviderConfig{FilePath: configPath}),
+		configPath:  configPath,
+	}
+	require.NoError(t, env.provider.Reload())
+
+	env.handler = NewHandler(Config{KeepAliveInterval: keepAliveInterval}, env.sessionPool, env.dal, env.provider)
+	env.sessionPool.Subscribe(env.handler.HandleSessionEvent)
+	env.provider.Subscribe(env.handler.HandleConfigsUpdate)
+
+	return env
+}
+
+func (env *testEnv) newAccount(t *testing.T, accountID string, energy core.Energy) sessions.Session {
+	require.NoError(t, env.dal.CreateAccount(context.Background(), players.Account{ID: accountID}, core.PersistentState{Energy: energy}))
+
+	session, err := env.sessionPool.CreateSession(context.Background(), accountID, nil)
+	require.NoError(t, err)
+	return session
+}
+
+// stream runs a stream in the background, returning its events and the error
+// it returned once it's over.
+func (env *testEnv) stream(ctx context.Context, session sessions.Session) (<-chan Event, <-chan error) {
+	events := make(chan Event, 100)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- env.handler.Stream(ctx, session.ID, session.AccountID, func(event Event) error {
+			events <- event
+			return nil
+		})
+		close(events)
+	}()
+
+	return events, done
+}
+
+func nextEvent(t *testing.T, events <-chan Event, eventType EventType) Event {
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case event, open := <-events:
+			require.True(t, open, "stream closed before a %s event", eventType)
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			require.FailNow(t, "no event", "no %s event received", eventType)
+		}
+	}
+}
+
+func fullEnergy() core.Energy {
+	return core.Energy{CurrentAmount: 10, LastRechargeAt: time.Now()}
+}
+
+func TestStream_ShouldStartWithConnected(t *testing.T) {
+	env := newTestEnv(t, time.Minute, time.Minute)
+	session := env.newAccount(t, "account-1", fullEnergy())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, done := env.stream(ctx, session)
+
+	event := nextEvent(t, events, EventConnected)
+	versioned, err := env.provider.GetVersionedConfigs()
+	require.NoError(t, err)
+	assert.Equal(t, versioned.Version, event.Data.(ConnectedData).ConfigsVersion)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestStream_WhenSessionEnds_ShouldPushReasonAndClose(t *testing.T) {
+	table := map[string]struct {
+		endSession func(env *testEnv, session sessions.Session)
+		reason     sessions.EndReason
+	}{
+		"replaced": {
+			endSession: func(env *testEnv, session sessions.Session) {
+				_, err := env.sessionPool.CreateSession(context.Background(), session.AccountID, nil)
+				require.NoError(t, err)
+			},
+			reason: sessions.EndReasonReplaced,
+		},
+		"logged out": {
+			endSession: func(env *testEnv, session sessions.Session) {
+				env.sessionPool.RemoveSession(context.Background(), session.ID)
+			},
+			reason: sessions.EndReasonLoggedOut,
+		},
+		"revoked": {
+			endSession: func(env *testEnv, session sessions.Session) {
+				env.sessionPool.RevokeSessions(context.Background(), session.AccountID)
+			},
+			reason: sessions.EndReasonRevoked,
+		},
+	}
+
+	for name, row := range table {
+		t.Run(name, func(t *testing.T) {
+			env := newTestEnv(t, time.Minute, time.Minute)
+			session := env.newAccount(t, "account-1", fullEnergy())
+
+			events, done := env.stream(context.Background(), session)
+			nextEvent(t, events, EventConnected)
+
+			row.endSession(env, session)
+
+			event := nextEvent(t, events, EventSessionEnded)
+			assert.Equal(t, SessionEndedData{Reason: row.reason}, event.Data)
+			assert.NoError(t, <-done)
+		})
+	}
+}
+
+func TestStream_ShouldKeepSessionAlive(t *testing.T) {
+	env := newTestEnv(t, 20*time.Millisecond, 100*time.Millisecond)
+	session := env.newAccount(t, "account-1", fullEnergy())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, done := env.stream(ctx, session)
+	nextEvent(t, events, EventConnected)
+
+	time.Sleep(300 * time.Millisecond)
+
+	_, alive := env.sessionPool.GetSession(context.Background(), session.ID)
+	assert.True(t, alive)
+	nextEvent(t, events, EventKeepAlive)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestStream_WhenSessionEndedUnnoticed_ShouldPushReasonAtKeepAlive(t *testing.T) {
+	env := newTestEnv(t, 20*time.Millisecond, time.Minute)
+	session := env.newAccount(t, "account-1", fullEnergy())
+
+	// Streams of another instance only notice the session ended at their next
+	// keep-alive.
+	otherHandler := NewHandler(Config{KeepAliveInterval: 20 * time.Millisecond}, env.sessionPool, env.dal, env.provider)
+	env.handler = otherHandler
+
+	events, done := env.stream(context.Background(), session)
+	nextEvent(t, events, EventConnected)
+
+	_, err := env.sessionPool.CreateSession(context.Background(), session.AccountID, nil)
+	require.NoError(t, err)
+
+	event := nextEvent(t, events, EventSessionEnded)
+	assert.Equal(t, SessionEndedData{Reason: sessions.EndReasonReplaced}, event.Data)
+	assert.NoError(t, <-done)
+}
+
+func TestStream_ShouldPushConfigsUpdates(t *testing.T) {
+	env := newTestEnv(t, time.Minute, time.Minute)
+	session := env.newAccount(t, "account-1", fullEnergy())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, _ := env.stream(ctx, session)
+	nextEvent(t, events, EventConnected)
+
+	updatedJSON := strings.Replace(testConfigJSON, `"maxEnergy": 10`, `"maxEnergy": 20`, 1)
+	require.NoError(t, os.WriteFile(env.configPath, []byte(updatedJSON), 0o600))
+	require.NoError(t, env.provider.Reload())
+
+	event := nextEvent(t, events, EventConfigsUpdated)
+	versioned, err := env.provider.GetVersionedConfigs()
+	require.NoError(t, err)
+	assert.Equal(t, ConfigsUpdatedData{ConfigsVersion: versioned.Version}, event.Data)
+}
+
+func TestStream_ShouldPushEnergyRefill(t *testing.T) {
+	env := newTestEnv(t, time.Minute, time.Minute)
+	session := env.newAccount(t, "account-1", core.Energy{
+		CurrentAmount:  9,
+		LastRechargeAt: time.Now().Add(-900 * time.Millisecond),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, _ := env.stream(ctx, session)
+
+	event := nextEvent(t, events, EventEnergyRefilled)
+	assert.Equal(t, EnergyRefilledData{CurrentAmount: 10}, event.Data)
+}
+
+func TestClose_ShouldEndStreams(t *testing.T) {
+	env := newTestEnv(t, time.Minute, time.Minute)
+	session := env.newAccount(t, "account-1", fullEnergy())
+
+	events, done := env.stream(context.Background(), session)
+	nextEvent(t, events, EventConnected)
+
+	env.handler.Close()
+	env.handler.Close()
+
+	assert.NoError(t, <-done)
+	assert.Empty(t, env.handler.streams)
+}
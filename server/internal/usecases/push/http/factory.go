@@ -0,0 +1,23 @@
+package http
+
+import (
+	"technical-test-backend/internal/sessions"
+	"technical-test-backend/internal/usecases/configs"
+	"technical-test-backend/internal/usecases/players"
+	"technical-test-backend/internal/usecases/push"
+)
+
+// CreateHTTPHandler also subscribes the push handler to the sessions events
+// and the configs updates, to forward them to the streams. The returned
+// function ends every stream.
+func CreateHTTPHandler(config push.Config, sessionPool sessions.Pool, dal players.StateDAL, configsProvider *configs.Provider) (*Handler, func()) {
+	pushHandler := push.NewHandler(config, sessionPool, dal, configsProvider)
+	unsubscribeSessions := sessionPool.Subscribe(pushHandler.HandleSessionEvent)
+	unsubscribeConfigs := configsProvider.Subscribe(pushHandler.HandleConfigsUpdate)
+
+	return NewHandler(pushHandler), func() {
+		unsubscribeSessions()
+		unsubscribeConfigs()
+		pushHandler.Close()
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"technical-test-backend/internal/usecases/push"
+)
+
+type Handler struct {
+	pushHandler *push.Handler
+}
+
+func NewHandler(pushHandler *push.Handler) *Handler {
+	return &Handler{
+		pushHandler: pushHandler,
+	}
+}
+
+// HandleSubscribe streams the events of the session as server-sent events,
+// until the client disconnects or the session ends.
+func (h *Handler) HandleSubscribe(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get("X-Session-ID")
+	accountID := r.Header.Get("X-Account-ID")
+
+	controller := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		log.Printf("Failed to open stream of session %s: %v", sessionID, err)
+		return
+	}
+
+	err := h.pushHandler.Stream(r.Context(), sessionID, accountID, func(event push.Event) error {
+		if err := writeEvent(w, event); err != nil {
+			return err
+		}
+		return controller.Flush()
+	})
+	if err != nil {
+		log.Printf("Closed stream of session %s: %v", sessionID, err)
+	}
+}
+
+// writeEvent writes the event in the text/event-stream format. Keep-alives
+// are comments, ignored by clients.
+func writeEvent(w io.Writer, event push.Event) error {
+	if event.Type == push.EventKeepAlive {
+		_, err := io.WriteString(w, ": keep-alive\n\n")
+		return err
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
@@ -1,6 +1,9 @@
 package worker
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 type Config struct {
 	Interval time.Duration
@@ -10,6 +13,8 @@ type impl struct {
 	config Config
 	f      func()
 	ticker *time.Ticker
+	done   chan struct{}
+	wg     sync.WaitGroup
 }
 
 func New(config Config, f func()) *impl {
@@ -21,15 +26,31 @@ func New(config Config, f func()) *impl {
 
 func (w *impl) Start() {
 	w.ticker = time.NewTicker(w.config.Interval)
+	w.done = make(chan struct{})
+
+	w.wg.Add(1)
 	go func() {
-		for range w.ticker.C {
-			w.f()
+		defer w.wg.Done()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-w.ticker.C:
+				w.f()
+			}
 		}
 	}()
 }
 
+// Stop waits for a run in progress to finish, so nothing it uses is closed
+// under it.
 func (w *impl) Stop() {
-	if w.ticker != nil {
-		w.ticker.Stop()
+	if w.ticker == nil {
+		return
 	}
+
+	w.ticker.Stop()
+	close(w.done)
+	w.wg.Wait()
+	w.ticker = nil
 }
@@ -0,0 +1,39 @@
+//go:build unit
+// +build unit
+
+package worker
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStop_ShouldWaitForRunInProgress(t *testing.T) {
+	var runs, finished atomic.Int32
+	w := New(Config{Interval: time.Millisecond}, func() {
+		runs.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		finished.Add(1)
+	})
+
+	w.Start()
+	time.Sleep(10 * time.Millisecond)
+	w.Stop()
+
+	assert.Equal(t, runs.Load(), finished.Load())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, finished.Load(), runs.Load(), "no run after Stop")
+}
+
+func TestStop_WithoutStart_ShouldDoNothing(t *testing.T) {
+	w := New(Config{Interval: time.Millisecond}, func() {})
+
+	w.Stop()
+	w.Start()
+	w.Stop()
+	w.Stop()
+}